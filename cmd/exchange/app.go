@@ -9,10 +9,18 @@ import (
 	"github.com/pinmonl/pinmonl/database"
 	"github.com/pinmonl/pinmonl/handler/server"
 	"github.com/pinmonl/pinmonl/monler"
+	"github.com/pinmonl/pinmonl/monler/provider/crates"
 	"github.com/pinmonl/pinmonl/monler/provider/docker"
 	"github.com/pinmonl/pinmonl/monler/provider/git"
 	"github.com/pinmonl/pinmonl/monler/provider/github"
+	"github.com/pinmonl/pinmonl/monler/provider/gitlab"
+	"github.com/pinmonl/pinmonl/monler/provider/gomod"
+	"github.com/pinmonl/pinmonl/monler/provider/helm"
 	"github.com/pinmonl/pinmonl/monler/provider/npm"
+	"github.com/pinmonl/pinmonl/monler/provider/oci"
+	"github.com/pinmonl/pinmonl/monler/provider/packagist"
+	"github.com/pinmonl/pinmonl/monler/provider/pypi"
+	"github.com/pinmonl/pinmonl/monler/provider/rubygems"
 	"github.com/pinmonl/pinmonl/monler/provider/website"
 	"github.com/pinmonl/pinmonl/monler/provider/youtube"
 	"github.com/pinmonl/pinmonl/queue"
@@ -86,6 +94,11 @@ func setupMonler(cfg *config) {
 			git.IsDev = true
 			git.CloneProgress = os.Stdout
 		}
+		if cfg.Git.Cache != "" {
+			// Cache size is configured in megabytes.
+			git.CloneCache = git.NewCache(cfg.Git.Cache, cfg.Git.CacheSize<<20)
+		}
+		catchErr(git.SetCredentials(cfg.Git.Credentials))
 		monler.Register(gitPvd.ProviderName(), gitPvd)
 	}
 
@@ -93,9 +106,14 @@ func setupMonler(cfg *config) {
 		if githubPvd, err := github.NewProvider(); err == nil {
 			monler.Register(githubPvd.ProviderName(), githubPvd)
 			github.AddToken(cfg.Github.Tokens)
+			github.AlwaysClone = cfg.Github.Clone
 		}
 	}
 
+	if gitlabPvd, err := gitlab.NewProvider(cfg.Gitlab.Hosts); err == nil {
+		monler.Register(gitlabPvd.ProviderName(), gitlabPvd)
+	}
+
 	if len(cfg.Youtube.Tokens) > 0 {
 		if youtubePvd, err := youtube.NewProvider(cfg.Youtube.Tokens); err == nil {
 			monler.Register(youtubePvd.ProviderName(), youtubePvd)
@@ -106,10 +124,38 @@ func setupMonler(cfg *config) {
 		monler.Register(npmPvd.ProviderName(), npmPvd)
 	}
 
+	if gomodPvd, err := gomod.NewProvider(cfg.Gomod.Proxy); err == nil {
+		monler.Register(gomodPvd.ProviderName(), gomodPvd)
+	}
+
+	if pypiPvd, err := pypi.NewProvider(); err == nil {
+		monler.Register(pypiPvd.ProviderName(), pypiPvd)
+	}
+
+	if cratesPvd, err := crates.NewProvider(); err == nil {
+		monler.Register(cratesPvd.ProviderName(), cratesPvd)
+	}
+
+	if rubygemsPvd, err := rubygems.NewProvider(); err == nil {
+		monler.Register(rubygemsPvd.ProviderName(), rubygemsPvd)
+	}
+
+	if packagistPvd, err := packagist.NewProvider(); err == nil {
+		monler.Register(packagistPvd.ProviderName(), packagistPvd)
+	}
+
+	if helmPvd, err := helm.NewProvider(); err == nil {
+		monler.Register(helmPvd.ProviderName(), helmPvd)
+	}
+
 	if dockerPvd, err := docker.NewProvider(); err == nil {
 		monler.Register(dockerPvd.ProviderName(), dockerPvd)
 	}
 
+	if ociPvd, err := oci.NewProvider(cfg.Oci.Hosts); err == nil {
+		monler.Register(ociPvd.ProviderName(), ociPvd)
+	}
+
 	if websitePvd, err := website.NewProvider(); err == nil {
 		monler.Register(websitePvd.ProviderName(), websitePvd)
 	}
@@ -131,7 +177,7 @@ func newQueue(cfg *config, db *database.DB, stores *store.Stores) *queue.Manager
 		cfg.Queue.Worker,
 	)
 	catchErr(err)
-	qm = qm.Stores(stores)
+	qm = qm.Stores(stores).Persist(cfg.Queue.Persist).Retention(cfg.Queue.Retention)
 	return qm
 }
 
@@ -151,6 +197,7 @@ func newHandler(cfg *config, db *database.DB, stores *store.Stores, qm *queue.Ma
 		TokenIssuer: cfg.JWT.Issuer,
 		Queue:       qm,
 		Version:     version.Version,
+		TrustProxy:  cfg.TrustProxy,
 
 		Monls:     stores.Monls,
 		Monpkgs:   stores.Monpkgs,
@@ -3,6 +3,7 @@ package main
 import (
 	"time"
 
+	"github.com/pinmonl/pinmonl/monler/provider/git"
 	"github.com/spf13/viper"
 )
 
@@ -10,6 +11,9 @@ type config struct {
 	Address string
 	Verbose int
 
+	ShutdownTimeout time.Duration
+	TrustProxy      bool
+
 	JWT struct {
 		Secret string
 		Issuer string
@@ -22,16 +26,34 @@ type config struct {
 	}
 
 	Git struct {
-		Dev bool
+		Dev         bool
+		Cache       string
+		CacheSize   int64
+		Credentials []*git.Credential
 	}
 
 	Github struct {
 		Tokens []string
+		Clone  bool
+	}
+
+	Gitlab struct {
+		Hosts []string
+	}
+
+	Gomod struct {
+		Proxy string
+	}
+
+	Oci struct {
+		Hosts []string
 	}
 
 	Queue struct {
-		Job    int
-		Worker int
+		Job       int
+		Worker    int
+		Persist   bool
+		Retention time.Duration
 	}
 
 	Youtube struct {
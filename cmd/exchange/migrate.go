@@ -30,6 +30,9 @@ var migrateCmd = &cobra.Command{
 }
 
 func (a *application) migrateUp() error {
+	if err := a.db.CheckFTS5(); err != nil {
+		return err
+	}
 	err := a.db.Migrate.Up()
 	if err != migrate.ErrNoChange {
 		return err
@@ -40,13 +40,23 @@ func initConfig() {
 	viper.SetDefault("db.driver", "postgres")
 	viper.SetDefault("db.dsn", "postgres://pinmonl:pinmonl@pg:5432/pinmonl?sslmode=disable")
 	viper.SetDefault("git.dev", false)
+	viper.SetDefault("git.cache", "")
+	viper.SetDefault("git.cachesize", 10240)
 	viper.SetDefault("github.tokens", []string{})
+	viper.SetDefault("github.clone", false)
+	viper.SetDefault("gitlab.hosts", []string{"gitlab.com"})
+	viper.SetDefault("gomod.proxy", "https://proxy.golang.org")
+	viper.SetDefault("oci.hosts", []string{"ghcr.io", "quay.io"})
 	viper.SetDefault("youtube.tokens", []string{})
 	viper.SetDefault("jwt.expire", "168h")
 	viper.SetDefault("jwt.issuer", "pinmonl-exchange")
 	viper.SetDefault("jwt.secret", string(generateKey()))
 	viper.SetDefault("queue.job", 1)
 	viper.SetDefault("queue.worker", 1)
+	viper.SetDefault("queue.persist", false)
+	viper.SetDefault("queue.retention", "720h")
+	viper.SetDefault("shutdowntimeout", "30s")
+	viper.SetDefault("trustproxy", false)
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
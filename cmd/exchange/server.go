@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"sync"
 
+	"github.com/pinmonl/pinmonl/pkgs/graceful"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -18,27 +20,42 @@ var serverCmd = &cobra.Command{
 	Run: withApp(func(cmd *cobra.Command, args []string, app *application) {
 		app.migrateUp()
 
+		ctx, cancel := graceful.SignalContext()
+		defer cancel()
+
+		// The queue has its own context, so the running jobs are
+		// interrupted only when the shutdown times out.
+		qctx, qcancel := context.WithCancel(context.Background())
+		defer qcancel()
+
 		wg := &sync.WaitGroup{}
 
 		wg.Add(1)
 		go func() {
-			app.queue.Start()
+			app.queue.Start(qctx)
 			wg.Done()
 		}()
 
-		wg.Add(1)
+		srv := &http.Server{
+			Addr:    app.cfg.Address,
+			Handler: app.handler,
+		}
 		go func() {
 			logrus.Printf("listen on %s", app.cfg.Address)
-			http.ListenAndServe(app.cfg.Address, app.handler)
-			wg.Done()
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logrus.Errorf("server: %v", err)
+				cancel()
+			}
 		}()
 
 		wg.Add(1)
 		go func() {
-			app.runner.Start()
+			app.runner.Start(ctx)
 			wg.Done()
 		}()
 
-		wg.Wait()
+		<-ctx.Done()
+		app.queue.Stop()
+		graceful.Shutdown(srv, wg, app.cfg.ShutdownTimeout, qcancel)
 	}),
 }
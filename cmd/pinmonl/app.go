@@ -10,6 +10,7 @@ import (
 	"github.com/pinmonl/pinmonl/handler/web"
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/pkgs/generate"
+	"github.com/pinmonl/pinmonl/pkgs/mailer"
 	"github.com/pinmonl/pinmonl/pubsub"
 	"github.com/pinmonl/pinmonl/queue"
 	"github.com/pinmonl/pinmonl/runner"
@@ -60,7 +61,8 @@ func withApp(fn func(*cobra.Command, []string, *application)) func(*cobra.Comman
 		stores := store.NewStores(db)
 		exm := newExchange(cfg, configs)
 		hub := newPubsubHub(cfg, stores)
-		qm := newQueue(cfg, db, stores, exm, hub)
+		ml := newMailer(cfg)
+		qm := newQueue(cfg, db, stores, exm, hub, ml)
 		runner := newRunner(cfg, stores, qm, exm)
 		handler := newHandler(cfg, db, stores, qm, exm, hub, configs)
 
@@ -111,17 +113,31 @@ func newDB(cfg *config) *database.DB {
 	return db
 }
 
-func newQueue(cfg *config, db *database.DB, stores *store.Stores, exm *exchange.Manager, hub pubsub.Pubsuber) *queue.Manager {
+func newQueue(cfg *config, db *database.DB, stores *store.Stores, exm *exchange.Manager, hub pubsub.Pubsuber, ml *mailer.Mailer) *queue.Manager {
 	qm, err := queue.NewManager(
 		db,
 		cfg.Queue.Job,
 		cfg.Queue.Worker,
 	)
 	catchErr(err)
-	qm = qm.Stores(stores).ExchangeManager(exm).Pubsuber(hub)
+	qm = qm.Stores(stores).ExchangeManager(exm).Pubsuber(hub).Mailer(ml).Persist(cfg.Queue.Persist).Retention(cfg.Queue.Retention)
 	return qm
 }
 
+// newMailer returns nil if smtp is not configured.
+func newMailer(cfg *config) *mailer.Mailer {
+	if cfg.SMTP.Host == "" {
+		return nil
+	}
+	return &mailer.Mailer{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	}
+}
+
 func newExchange(cfg *config, configs *store.Configs) *exchange.Manager {
 	exm, err := exchange.NewManager(
 		configs,
@@ -138,6 +154,7 @@ func newRunner(cfg *config, stores *store.Stores, qm *queue.Manager, exm *exchan
 		Stores:   stores,
 
 		ExchangeEnabled: cfg.Exchange.Enabled,
+		DigestEnabled:   cfg.SMTP.Host != "",
 	}
 	return r
 }
@@ -162,20 +179,29 @@ func newHandler(cfg *config, db *database.DB, stores *store.Stores, qm *queue.Ma
 		Pubsub:      hub,
 
 		ExchangeEnabled: cfg.Exchange.Enabled,
+		SearchContent:   cfg.Search.Content,
 		DevServer:       cfg.Web.DevServer,
-
-		Images:    stores.Images,
-		Monls:     stores.Monls,
-		Monpkgs:   stores.Monpkgs,
-		Pinls:     stores.Pinls,
-		Pkgs:      stores.Pkgs,
-		Sharepins: stores.Sharepins,
-		Shares:    stores.Shares,
-		Sharetags: stores.Sharetags,
-		Stats:     stores.Stats,
-		Taggables: stores.Taggables,
-		Tags:      stores.Tags,
-		Users:     stores.Users,
+		Admins:          cfg.Web.Admins,
+		TrustProxy:      cfg.Web.TrustProxy,
+		FeedTokenExpire: cfg.Feed.TokenExpire,
+
+		Digests:          stores.Digests,
+		Images:           stores.Images,
+		Jobs:             stores.Jobs,
+		Monls:            stores.Monls,
+		Monpkgs:          stores.Monpkgs,
+		NotifyDeliveries: stores.NotifyDeliveries,
+		NotifyRules:      stores.NotifyRules,
+		Pinls:            stores.Pinls,
+		Pkgs:             stores.Pkgs,
+		Presets:          stores.Presets,
+		Sharepins:        stores.Sharepins,
+		Shares:           stores.Shares,
+		Sharetags:        stores.Sharetags,
+		Stats:            stores.Stats,
+		Taggables:        stores.Taggables,
+		Tags:             stores.Tags,
+		Users:            stores.Users,
 	}
 
 	if cfg.DefaultUser {
@@ -10,10 +10,18 @@ type config struct {
 	Address string
 	Verbose int
 
+	ShutdownTimeout time.Duration
+
 	DefaultUser bool
 
 	Web struct {
-		DevServer string
+		Admins     []string
+		DevServer  string
+		TrustProxy bool
+	}
+
+	Feed struct {
+		TokenExpire time.Duration
 	}
 
 	JWT struct {
@@ -32,9 +40,23 @@ type config struct {
 		Address string
 	}
 
+	Search struct {
+		Content bool
+	}
+
 	Queue struct {
-		Job    int
-		Worker int
+		Job       int
+		Worker    int
+		Persist   bool
+		Retention time.Duration
+	}
+
+	SMTP struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
 	}
 }
 
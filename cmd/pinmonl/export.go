@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pinmonl/pinmonl/store/storeutils"
+	"github.com/spf13/cobra"
+)
+
+var exportUser string
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	flags := exportCmd.Flags()
+	flags.StringVarP(&exportUser, "user", "u", "", "login of the user to export")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "export archive to file or stdout",
+	Args:  cobra.MaximumNArgs(1),
+	Run: withApp(func(cmd *cobra.Command, args []string, app *application) {
+		catchErr(app.migrateUp())
+
+		ctx := context.TODO()
+		user, err := app.findUser(ctx, exportUser)
+		catchErr(err)
+
+		var w io.Writer = os.Stdout
+		if len(args) > 0 {
+			file, err := os.Create(args[0])
+			catchErr(err)
+			defer file.Close()
+			w = file
+		}
+
+		archive, err := storeutils.ExportArchive(ctx, app.archiveStores(), user.ID)
+		catchErr(err)
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		catchErr(enc.Encode(archive))
+	}),
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/bookmark"
+	"github.com/pinmonl/pinmonl/queue/job"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importUser   string
+	importFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	flags := importCmd.Flags()
+	flags.StringVarP(&importUser, "user", "u", "", "login of the user to import into")
+	flags.StringVarP(&importFormat, "format", "f", "auto", "file format (auto/archive/bookmark)")
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "import archive or netscape bookmark file",
+	Args:  cobra.ExactArgs(1),
+	Run: withApp(func(cmd *cobra.Command, args []string, app *application) {
+		catchErr(app.migrateUp())
+
+		ctx := context.TODO()
+		user, err := app.findUser(ctx, importUser)
+		catchErr(err)
+
+		file, err := os.Open(args[0])
+		catchErr(err)
+		defer file.Close()
+
+		br := bufio.NewReader(file)
+		format := importFormat
+		if format == "auto" {
+			format = detectImportFormat(br)
+		}
+
+		switch format {
+		case "archive":
+			catchErr(app.importArchive(ctx, user, br))
+		case "bookmark":
+			catchErr(app.importBookmarks(ctx, user, br))
+		default:
+			catchErr(fmt.Errorf("unknown format %q", format))
+		}
+	}),
+}
+
+// detectImportFormat treats JSON file as archive and others as
+// netscape bookmark file.
+func detectImportFormat(br *bufio.Reader) string {
+	head, _ := br.Peek(512)
+	if bytes.HasPrefix(bytes.TrimSpace(head), []byte("{")) {
+		return "archive"
+	}
+	return "bookmark"
+}
+
+// findUser finds the user by login or falls back to the default user.
+func (a *application) findUser(ctx context.Context, login string) (*model.User, error) {
+	if login != "" {
+		user, err := a.stores.Users.FindLogin(ctx, login)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("user %q not found", login)
+		}
+		return user, nil
+	}
+
+	if !a.cfg.DefaultUser {
+		return nil, errors.New("please specify the user by --user")
+	}
+	if err := a.bootstrapDefaultUser(ctx); err != nil {
+		return nil, err
+	}
+	user, err := a.stores.Users.Find(ctx, a.configs.GetUserDefaultUserID())
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("default user not found")
+	}
+	return user, nil
+}
+
+func (a *application) importBookmarks(ctx context.Context, user *model.User, r io.Reader) error {
+	bookmarks, err := bookmark.ParseNetscape(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		results storeutils.ImportResultList
+		outerr  error
+	)
+	txerr := a.db.TxFunc(ctx, func(ctx context.Context) bool {
+		results, outerr = storeutils.ImportBookmarks(ctx, a.stores.Pinls, a.stores.Tags, a.stores.Taggables, user.ID, bookmarks)
+		return outerr == nil
+	})
+	if outerr != nil {
+		return outerr
+	}
+	if txerr != nil {
+		return txerr
+	}
+	a.runPinlJobs(results.PinlIDs())
+
+	for _, ir := range results {
+		fmt.Printf("%d\t%s\t%s", ir.Row, ir.Status, ir.URL)
+		if ir.Message != "" {
+			fmt.Printf("\t%s", ir.Message)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("created: %d, duplicate: %d, invalid: %d\n",
+		results.Count(storeutils.ImportCreated),
+		results.Count(storeutils.ImportDuplicate),
+		results.Count(storeutils.ImportInvalid))
+	return nil
+}
+
+func (a *application) importArchive(ctx context.Context, user *model.User, r io.Reader) error {
+	var archive storeutils.Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return err
+	}
+	if err := archive.Validate(); err != nil {
+		return err
+	}
+
+	var (
+		result *storeutils.ArchiveImportResult
+		outerr error
+	)
+	txerr := a.db.TxFunc(ctx, func(ctx context.Context) bool {
+		result, outerr = storeutils.ImportArchive(ctx, a.archiveStores(), user.ID, &archive)
+		return outerr == nil
+	})
+	if outerr != nil {
+		return outerr
+	}
+	if txerr != nil {
+		return txerr
+	}
+	a.runPinlJobs(result.CreatedPinlIDs)
+
+	counts := []struct {
+		name  string
+		count storeutils.ArchiveCount
+	}{
+		{"tags", result.Tags},
+		{"pinls", result.Pinls},
+		{"taggables", result.Taggables},
+		{"shares", result.Shares},
+		{"sharetags", result.Sharetags},
+		{"images", result.Images},
+	}
+	for _, c := range counts {
+		fmt.Printf("%s: created %d, skipped %d\n", c.name, c.count.Created, c.count.Skipped)
+	}
+	return nil
+}
+
+// runPinlJobs runs the pinl jobs before exit so that the pinls
+// are linked to monls.
+func (a *application) runPinlJobs(pinlIDs []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		a.queue.Start(ctx)
+		close(stopped)
+	}()
+
+	errchs := make([]<-chan error, 0)
+	for _, pinlID := range pinlIDs {
+		errchs = append(errchs, a.queue.Add(job.NewPinlUpdated(pinlID)))
+	}
+	for _, errch := range errchs {
+		<-errch
+	}
+
+	cancel()
+	<-stopped
+}
+
+func (a *application) archiveStores() storeutils.ArchiveStores {
+	return storeutils.ArchiveStores{
+		Images:    a.stores.Images,
+		Pinls:     a.stores.Pinls,
+		Presets:   a.stores.Presets,
+		Shares:    a.stores.Shares,
+		Sharetags: a.stores.Sharetags,
+		Taggables: a.stores.Taggables,
+		Tags:      a.stores.Tags,
+	}
+}
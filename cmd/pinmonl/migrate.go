@@ -31,8 +31,11 @@ var migrateCmd = &cobra.Command{
 }
 
 func (a *application) migrateUp() error {
+	if err := a.db.CheckFTS5(); err != nil {
+		return err
+	}
 	err := a.db.Migrate.Up()
-	if err != migrate.ErrNoChange {
+	if err != nil && err != migrate.ErrNoChange {
 		return err
 	}
 	return nil
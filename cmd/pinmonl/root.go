@@ -42,12 +42,24 @@ func initConfig() {
 	viper.SetDefault("db.dsn", "client.db")
 	viper.SetDefault("exchange.address", "https://pinmonl.io")
 	viper.SetDefault("exchange.enabled", true)
+	viper.SetDefault("feed.tokenexpire", "0")
 	viper.SetDefault("jwt.expire", "24h")
 	viper.SetDefault("jwt.issuer", "pinmonl")
 	viper.SetDefault("jwt.secret", string(generateKey()))
 	viper.SetDefault("queue.job", 1)
 	viper.SetDefault("queue.worker", 1)
+	viper.SetDefault("queue.persist", false)
+	viper.SetDefault("queue.retention", "720h")
+	viper.SetDefault("search.content", false)
+	viper.SetDefault("shutdowntimeout", "30s")
+	viper.SetDefault("smtp.from", "pinmonl@localhost")
+	viper.SetDefault("smtp.host", "")
+	viper.SetDefault("smtp.password", "")
+	viper.SetDefault("smtp.port", 25)
+	viper.SetDefault("smtp.username", "")
+	viper.SetDefault("web.admins", []string{})
 	viper.SetDefault("web.devserver", "")
+	viper.SetDefault("web.trustproxy", false)
 
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/pinmonl/pinmonl/pkgs/graceful"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -17,39 +18,56 @@ var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "start client web server",
 	Run: withApp(func(cmd *cobra.Command, args []string, app *application) {
-		app.migrateUp()
+		if err := app.migrateUp(); err != nil {
+			logrus.Errorf("migrate: %v", err)
+		}
+
+		ctx, cancel := graceful.SignalContext()
+		defer cancel()
 
 		if app.cfg.DefaultUser {
-			ctx := context.TODO()
 			if err := app.bootstrapDefaultUser(ctx); err != nil {
 				logrus.Fatal(err)
 			}
 		}
 
-		wg := &sync.WaitGroup{}
-		wg.Add(4)
-
+		srv := &http.Server{
+			Addr:    app.cfg.Address,
+			Handler: app.handler,
+		}
 		go func() {
 			logrus.Debugf("listen on %s", app.cfg.Address)
-			http.ListenAndServe(app.cfg.Address, app.handler)
-			wg.Done()
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logrus.Errorf("server: %v", err)
+				cancel()
+			}
 		}()
 
+		// The queue has its own context, so the running jobs are
+		// interrupted only when the shutdown times out.
+		qctx, qcancel := context.WithCancel(context.Background())
+		defer qcancel()
+
+		wg := &sync.WaitGroup{}
+		wg.Add(3)
+
 		go func() {
-			app.queue.Start()
+			app.queue.Start(qctx)
 			wg.Done()
 		}()
 
 		go func() {
-			app.hub.Start()
+			app.hub.Start(ctx)
 			wg.Done()
 		}()
 
 		go func() {
-			app.runner.Start()
+			app.runner.Start(ctx)
 			wg.Done()
 		}()
 
-		wg.Wait()
+		<-ctx.Done()
+		app.queue.Stop()
+		graceful.Shutdown(srv, wg, app.cfg.ShutdownTimeout, qcancel)
 	}),
 }
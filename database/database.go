@@ -10,7 +10,8 @@ import (
 )
 
 var (
-	ErrNoTx = errors.New("Tx does not exist")
+	ErrNoTx   = errors.New("Tx does not exist")
+	ErrNoFTS5 = errors.New("sqlite3 is built without FTS5, build with the tag sqlite_fts5")
 )
 
 type DB struct {
@@ -49,6 +50,23 @@ func (d *DB) DriverName() string {
 	return d.driver
 }
 
+// CheckFTS5 returns ErrNoFTS5 if the driver is sqlite3 without FTS5,
+// which is required by the migrations of full-text search.
+func (d *DB) CheckFTS5() error {
+	if d.driver != "sqlite3" {
+		return nil
+	}
+	var enabled bool
+	err := d.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrNoFTS5
+	}
+	return nil
+}
+
 func (d *DB) TxFunc(ctx context.Context, fn func(context.Context) bool) error {
 	tx, err := d.Begin()
 	if err != nil {
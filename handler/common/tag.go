@@ -74,6 +74,10 @@ func TagListHandler(tags *store.Tags) http.Handler {
 		}
 
 		tList, err := tags.List(ctx, opts)
+		if errors.Is(err, store.ErrInvalidCursor) {
+			response.JSON(w, err, http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			response.JSON(w, err, http.StatusInternalServerError)
 			return
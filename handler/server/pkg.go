@@ -13,6 +13,7 @@ import (
 	"github.com/pinmonl/pinmonl/pkgs/response"
 	"github.com/pinmonl/pinmonl/queue/job"
 	"github.com/pinmonl/pinmonl/store"
+	"github.com/sirupsen/logrus"
 )
 
 type pkgListQuery struct {
@@ -88,10 +89,23 @@ func (s *Server) pkgListHandler(w http.ResponseWriter, r *http.Request) {
 		return true
 	})
 
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+
 	if monl.FetchedAt.Time().IsZero() {
-		// Enqueue
-		cherr := s.Queue.Add(job.NewMonlCrawler(monl.ID))
-		<-cherr
+		// The job added in persisted mode may be run later or by
+		// another instance, so the crawler runs in the request.
+		crawler := job.NewMonlCrawler(monl.ID)
+		if s.Queue.Persisted() {
+			err = s.Queue.Run(ctx, crawler)
+		} else {
+			err = <-s.Queue.Add(crawler)
+		}
+		if err != nil {
+			logrus.Debugf("server: crawl monl %s err(%s)", monl.URL, err)
+		}
 
 		// Reload data of monl.
 		if monl2, err := s.Monls.Find(ctx, monl.ID); err == nil {
@@ -109,6 +123,10 @@ func (s *Server) pkgListHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pkgs, err := s.Monpkgs.ListWithPkg(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		response.JSON(w, err, http.StatusInternalServerError)
 		return
@@ -119,5 +137,7 @@ func (s *Server) pkgListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.ListJSON(w, pkgs, pg.ToPageInfo(count), http.StatusOK)
+	response.ListJSON(w, pkgs, pg.ToCursorPageInfo(count, len(pkgs), func(i int) string {
+		return store.IDCursor(pkgs[i].ID)
+	}), http.StatusOK)
 }
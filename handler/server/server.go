@@ -7,6 +7,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-chi/chi"
 	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/pkgs/request"
 	"github.com/pinmonl/pinmonl/queue"
 	"github.com/pinmonl/pinmonl/store"
@@ -21,6 +22,9 @@ type Server struct {
 	Queue       *queue.Manager
 	Version     *semver.Version
 
+	// TrustProxy honors the forwarded headers in the links of response.
+	TrustProxy bool
+
 	Monls     *store.Monls
 	Monpkgs   *store.Monpkgs
 	Pinls     *store.Pinls
@@ -39,6 +43,7 @@ func (s *Server) Handler() http.Handler {
 	r.Use(s.authenticate())
 	r.Use(s.logMachine())
 	r.Mount("/api", s.APIRouter())
+	r.Mount("/", s.SharingRouter())
 
 	return r
 }
@@ -85,20 +90,20 @@ func (s *Server) APIRouter() chi.Router {
 		).Get("/", s.statListHandler)
 	})
 
-	// r.Route("/sharing", func(r chi.Router) {
-	// 	r.Route("/{user}/{share}", func(r chi.Router) {
-	// 		r.Use(
-	// 			s.bindUser(),
-	// 			s.bindUserSharing(),
-	// 			s.shareStatusMustBe(model.Active),
-	// 		)
-	// 		r.Get("/", s.sharingHandler)
-	// 		r.With(s.pagination()).
-	// 			Get("/pinl", s.sharingPinlListHandler)
-	// 		r.With(s.pagination()).
-	// 			Get("/tag", s.sharingTagListHandler)
-	// 	})
-	// })
+	r.Route("/sharing", func(r chi.Router) {
+		r.Route("/{user}/{share}", func(r chi.Router) {
+			r.Use(
+				s.bindUser(),
+				s.bindUserSharing(),
+				s.shareStatusMustBe(model.Active),
+			)
+			r.Get("/", s.sharingHandler)
+			r.With(s.pagination()).
+				Get("/pinl", s.sharingPinlListHandler)
+			r.With(s.pagination()).
+				Get("/tag", s.sharingTagListHandler)
+		})
+	})
 
 	// r.Route("/pinl", func(r chi.Router) {
 	// 	r.Use(s.authorize())
@@ -115,6 +120,24 @@ func (s *Server) APIRouter() chi.Router {
 	return r
 }
 
+// SharingRouter serves the public pages of the active shares.
+func (s *Server) SharingRouter() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{user}/{share}", func(r chi.Router) {
+		r.Use(
+			s.bindUser(),
+			s.bindUserSharing(),
+			s.shareStatusMustBe(model.Active),
+		)
+		r.With(s.pagination()).
+			Get("/", s.sharingPageHandler)
+		r.Get("/atom", s.sharingAtomHandler)
+	})
+
+	return r
+}
+
 func (s *Server) pagination() func(http.Handler) http.Handler {
 	return request.Pagination("page", "page_size", 10)
 }
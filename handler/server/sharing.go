@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -71,15 +72,12 @@ func (s *Server) sharingPinlListHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	if len(query.Tags) > 0 {
-		stList, err := s.Sharetags.ListWithTag(ctx, &store.SharetagOpts{
-			ShareIDs: []string{share.ID},
-			TagNames: query.Tags,
-		})
+		tagIDs, err := s.sharingTagIDs(ctx, share, query.Tags)
 		if err != nil {
 			response.JSON(w, err, http.StatusInternalServerError)
 			return
 		}
-		spOpts.TagIDs = stList.Tags().Keys()
+		spOpts.TagIDs = tagIDs
 	}
 
 	spList, err := s.Sharepins.ListWithPinl(ctx, spOpts)
@@ -91,6 +89,18 @@ func (s *Server) sharingPinlListHandler(w http.ResponseWriter, r *http.Request)
 	response.JSON(w, spList.Pinls(), http.StatusOK)
 }
 
+// sharingTagIDs finds the tag ids of the share by names.
+func (s *Server) sharingTagIDs(ctx context.Context, share *model.Share, names []string) ([]string, error) {
+	stList, err := s.Sharetags.ListWithTag(ctx, &store.SharetagOpts{
+		ShareIDs: []string{share.ID},
+		TagNames: names,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stList.Tags().Keys(), nil
+}
+
 // sharingTagListHandler lists the tags with any kind of the share.
 func (s *Server) sharingTagListHandler(w http.ResponseWriter, r *http.Request) {
 	query, err := request.ParseTagQuery(r)
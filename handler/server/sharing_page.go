@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/feed"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+// sharingFeedLimit is the number of pinls in the feed of share.
+const sharingFeedLimit = 50
+
+var sharingPageTmpl = template.Must(template.New("sharing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Share.Name}} - {{.User.Login}}</title>
+<link rel="alternate" type="application/atom+xml" title="{{.Share.Name}}" href="{{.FeedURL}}">
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 1em; color: #222; }
+main { display: flex; gap: 2em; }
+nav { min-width: 200px; }
+nav ul, section ul { padding-left: 1em; }
+section { flex: 1; }
+.pinl { margin-bottom: 1.5em; }
+.pinl p { margin: .25em 0; }
+.tag { background: #eee; border-radius: 3px; padding: 0 .3em; margin-right: .3em; font-size: .85em; }
+.release { color: #555; font-size: .9em; }
+.active { font-weight: bold; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Share.Name}}</h1>
+<p>by {{if .User.Name}}{{.User.Name}}{{else}}{{.User.Login}}{{end}} &middot; <a href="{{.FeedURL}}">Atom feed</a></p>
+{{if .Share.Description}}<p>{{.Share.Description}}</p>{{end}}
+<form method="get">
+<input type="search" name="q" value="{{.Query}}" placeholder="Search">
+{{if .Tag}}<input type="hidden" name="tag" value="{{.Tag}}">{{end}}
+</form>
+</header>
+<main>
+<nav>
+<h2>Tags</h2>
+<ul>
+<li{{if not .Tag}} class="active"{{end}}><a href="{{.PageURL}}">All</a></li>
+</ul>
+{{template "tags" .}}
+</nav>
+<section>
+{{range .Pinls}}<div class="pinl">
+<p><a href="{{.URL}}">{{if .Title}}{{.Title}}{{else}}{{.URL}}{{end}}</a></p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .TagNames}}<p>{{range .TagNames}}<span class="tag">{{.}}</span>{{end}}</p>{{end}}
+{{range .Releases}}<p class="release">{{.Pkg.Provider}} {{.Pkg.ProviderURI}}: <strong>{{.Stat.Value}}</strong>{{if not .Stat.RecordedAt.Time.IsZero}} ({{.Stat.RecordedAt.Time.Format "2006-01-02"}}){{end}}</p>
+{{end}}</div>
+{{else}}<p>No pinls.</p>
+{{end}}
+<p>{{if .PrevURL}}<a href="{{.PrevURL}}">&laquo; Previous</a> {{end}}{{if .NextURL}}<a href="{{.NextURL}}">Next &raquo;</a>{{end}}</p>
+</section>
+</main>
+</body>
+</html>
+{{define "tags"}}<ul>
+{{range .Tags}}{{template "tag" .}}{{end}}</ul>
+{{end}}
+{{define "tag"}}<li{{if .Active}} class="active"{{end}}><a href="{{.URL}}">{{.Name}}</a>{{if .Children}}
+<ul>
+{{range .Children}}{{template "tag" .}}{{end}}</ul>{{end}}</li>
+{{end}}`))
+
+var sharingFeedContentTmpl = template.Must(template.New("sharing_feed").Parse(
+	`{{if .Description}}<p>{{.Description}}</p>
+{{end}}{{if .Releases}}<ul>
+{{range .Releases}}<li>{{.Pkg.Provider}} {{.Pkg.ProviderURI}}: <strong>{{.Stat.Value}}</strong></li>
+{{end}}</ul>{{end}}`))
+
+type sharingPage struct {
+	User    *model.User
+	Share   *model.Share
+	Query   string
+	Tag     string
+	Tags    []*sharingTag
+	Pinls   []*sharingPinl
+	PageURL string
+	FeedURL string
+	PrevURL string
+	NextURL string
+}
+
+// sharingTag is a node of the tag tree of share.
+type sharingTag struct {
+	Name     string
+	URL      string
+	Active   bool
+	Children []*sharingTag
+}
+
+// sharingPinl is the pinl with its latest releases.
+type sharingPinl struct {
+	*model.Pinl
+	Releases []*sharingRelease
+}
+
+type sharingRelease struct {
+	Pkg  *model.Pkg
+	Stat *model.Stat
+}
+
+// updated returns the latest time of the pinl and its releases.
+func (sp *sharingPinl) updated() time.Time {
+	t := sp.CreatedAt.Time()
+	for _, release := range sp.Releases {
+		if rt := release.Stat.RecordedAt.Time(); rt.After(t) {
+			t = rt
+		}
+	}
+	return t
+}
+
+// sharingPageHandler renders the pinls and tags of the share in html.
+func (s *Server) sharingPageHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := request.ParsePinlQuery(r)
+	if err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx   = r.Context()
+		user  = request.UserFrom(ctx)
+		share = request.ShareFrom(ctx)
+		pg    = request.PaginatorFrom(ctx)
+		base  = "/" + url.PathEscape(user.Login) + "/" + url.PathEscape(share.Slug)
+		tag   string
+	)
+	if len(query.Tags) > 0 {
+		tag = query.Tags[0]
+	}
+
+	page := &sharingPage{
+		User:    user,
+		Share:   share,
+		Query:   query.Query,
+		Tag:     tag,
+		PageURL: base,
+		FeedURL: base + "/atom",
+	}
+
+	tags, err := s.sharingTagTree(ctx, share, base, tag)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	page.Tags = tags
+
+	// One more is fetched to check the existence of next page.
+	spOpts := &store.SharepinOpts{
+		ShareIDs:  []string{share.ID},
+		PinlQuery: query.Query,
+		Orders:    []store.SharepinOrder{store.SharepinOrderByPinlLatest},
+		ListOpts:  pg.ToOpts(),
+	}
+	spOpts.Limit++
+
+	hasPinls := true
+	if tag != "" {
+		tagIDs, err := s.sharingTagIDs(ctx, share, []string{tag})
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		spOpts.TagIDs = tagIDs
+		hasPinls = len(tagIDs) > 0
+	}
+
+	if hasPinls {
+		spList, err := s.Sharepins.List(ctx, spOpts)
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		if int64(len(spList)) > pg.PageSize {
+			spList = spList[:pg.PageSize]
+			page.NextURL = sharingPageURL(r, pg.Page+1)
+		}
+		if pg.Page > 1 {
+			page.PrevURL = sharingPageURL(r, pg.Page-1)
+		}
+
+		page.Pinls, err = s.sharingPinls(ctx, spList)
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := sharingPageTmpl.Execute(&buf, page); err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// sharingAtomHandler renders the newly added pinls of the share and
+// their latest releases in Atom.
+func (s *Server) sharingAtomHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		user  = request.UserFrom(ctx)
+		share = request.ShareFrom(ctx)
+		base  = request.BaseURL(r, s.TrustProxy)
+		link  = base + "/" + url.PathEscape(user.Login) + "/" + url.PathEscape(share.Slug)
+	)
+
+	spList, err := s.Sharepins.List(ctx, &store.SharepinOpts{
+		ShareIDs: []string{share.ID},
+		Orders:   []store.SharepinOrder{store.SharepinOrderByPinlLatest},
+		ListOpts: store.ListOpts{Limit: sharingFeedLimit},
+	})
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	pinls, err := s.sharingPinls(ctx, spList)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	author := user.Name
+	if author == "" {
+		author = user.Login
+	}
+	f := &feed.Feed{
+		ID:          link,
+		Title:       share.Name,
+		Link:        link,
+		FeedLink:    link + "/atom",
+		Description: share.Description,
+		Author:      author,
+		Updated:     share.UpdatedAt.Time(),
+		Items:       make([]*feed.Item, 0, len(pinls)),
+	}
+	for _, pinl := range pinls {
+		var content bytes.Buffer
+		if err := sharingFeedContentTmpl.Execute(&content, pinl); err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+
+		title := pinl.Title
+		if title == "" {
+			title = pinl.URL
+		}
+		var categories []string
+		if pinl.TagNames != nil {
+			categories = *pinl.TagNames
+		}
+		updated := pinl.updated()
+		if updated.After(f.Updated) {
+			f.Updated = updated
+		}
+
+		f.Items = append(f.Items, &feed.Item{
+			ID:         "urn:pinmonl:pinl:" + pinl.ID,
+			Title:      title,
+			Link:       pinl.URL,
+			Summary:    pinl.Description,
+			Content:    content.String(),
+			Categories: categories,
+			Published:  pinl.CreatedAt.Time(),
+			Updated:    updated,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := feed.WriteAtom(&buf, f); err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", feed.AtomContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// sharingPinls loads the pinls of sharepins with tags and latest
+// releases, in the order of sharepins.
+func (s *Server) sharingPinls(ctx context.Context, spList model.SharepinList) ([]*sharingPinl, error) {
+	if len(spList) == 0 {
+		return nil, nil
+	}
+
+	pinlIDs := make([]string, len(spList))
+	for i := range spList {
+		pinlIDs[i] = spList[i].PinlID
+	}
+	pList, err := storeutils.ListPinlsWithLatestStats(ctx, s.Pinls, s.Monpkgs, s.Stats, s.Taggables, &store.PinlOpts{
+		IDs: pinlIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pinlByID := make(map[string]*model.Pinl)
+	for _, pinl := range pList {
+		pinlByID[pinl.ID] = pinl
+	}
+
+	out := make([]*sharingPinl, 0, len(spList))
+	for _, sp := range spList {
+		pinl, has := pinlByID[sp.PinlID]
+		if !has {
+			continue
+		}
+		out = append(out, &sharingPinl{
+			Pinl:     pinl,
+			Releases: latestReleases(pinl),
+		})
+	}
+	return out, nil
+}
+
+// latestReleases picks the latest release stats of the pkgs of pinl.
+func latestReleases(pinl *model.Pinl) []*sharingRelease {
+	if pinl.Pkgs == nil {
+		return nil
+	}
+
+	out := make([]*sharingRelease, 0)
+	for _, pkg := range *pinl.Pkgs {
+		if pkg.Stats == nil {
+			continue
+		}
+		for _, stat := range *pkg.Stats {
+			if !stat.IsLatest || stat.ParentID != "" || !model.IsReleaseStatKind(stat.Kind) {
+				continue
+			}
+			out = append(out, &sharingRelease{Pkg: pkg, Stat: stat})
+		}
+	}
+	return out
+}
+
+// sharingTagTree builds the tag tree of share by the parent of
+// sharetags. Tags are sorted by name in each level.
+func (s *Server) sharingTagTree(ctx context.Context, share *model.Share, base, active string) ([]*sharingTag, error) {
+	stList, err := s.Sharetags.ListWithTag(ctx, &store.SharetagOpts{
+		ShareIDs: []string{share.ID},
+		Kind:     field.NewNullValue(model.SharetagAny),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*sharingTag)
+	for _, st := range stList {
+		if st.Tag == nil {
+			continue
+		}
+		nodes[st.TagID] = &sharingTag{
+			Name:   st.Tag.Name,
+			URL:    base + "?tag=" + url.QueryEscape(st.Tag.Name),
+			Active: st.Tag.Name == active,
+		}
+	}
+
+	roots := make([]*sharingTag, 0)
+	for _, st := range stList {
+		node, has := nodes[st.TagID]
+		if !has {
+			continue
+		}
+		if parent, has := nodes[st.ParentID]; has {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	sortSharingTags(roots)
+	return roots, nil
+}
+
+func sortSharingTags(tags []*sharingTag) {
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	for _, tag := range tags {
+		sortSharingTags(tag.Children)
+	}
+}
+
+// sharingPageURL returns the url of the page with the same query.
+func sharingPageURL(r *http.Request, page int64) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.FormatInt(page, 10))
+	return r.URL.Path + "?" + q.Encode()
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/pkger"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSharingServer(t *testing.T) (*Server, *store.Stores, func()) {
+	dir, err := ioutil.TempDir("", "server")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	db, err := database.NewDB("sqlite3", filepath.Join(dir, "test.db"))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	if err := db.CheckFTS5(); err != nil {
+		t.Skip(err)
+	}
+	if !assert.Nil(t, db.Migrate.Up()) {
+		t.FailNow()
+	}
+
+	stores := store.NewStores(db)
+	s := &Server{
+		Monpkgs:   stores.Monpkgs,
+		Pinls:     stores.Pinls,
+		Sharepins: stores.Sharepins,
+		Shares:    stores.Shares,
+		Sharetags: stores.Sharetags,
+		Stats:     stores.Stats,
+		Taggables: stores.Taggables,
+		Tags:      stores.Tags,
+		Users:     stores.Users,
+	}
+	return s, stores, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// seedSharing creates a share of three pinls, which are added a day
+// after another, and the tags "lang" and its child "lang/go".
+func seedSharing(t *testing.T, stores *store.Stores) *model.Share {
+	ctx := context.TODO()
+
+	user := &model.User{Login: "alice", Name: "Alice"}
+	assert.Nil(t, stores.Users.Create(ctx, user))
+	share := &model.Share{UserID: user.ID, Slug: "go", Name: "Go", Status: model.Active}
+	assert.Nil(t, stores.Shares.Create(ctx, share))
+
+	lang := &model.Tag{UserID: user.ID, Name: "lang"}
+	assert.Nil(t, stores.Tags.Create(ctx, lang))
+	golang := &model.Tag{UserID: user.ID, Name: "lang/go", ParentID: lang.ID}
+	assert.Nil(t, stores.Tags.Create(ctx, golang))
+	for _, st := range []*model.Sharetag{
+		{ShareID: share.ID, TagID: golang.ID, Kind: model.SharetagAny, ParentID: lang.ID, Level: 1},
+		{ShareID: share.ID, TagID: lang.ID, Kind: model.SharetagAny},
+	} {
+		assert.Nil(t, stores.Sharetags.Create(ctx, st))
+	}
+
+	added := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, title := range []string{"First", "Second", "Third"} {
+		pinl := &model.Pinl{UserID: user.ID, URL: "https://example.com/" + title, Title: title}
+		assert.Nil(t, stores.Pinls.Create(ctx, pinl))
+		pinl.CreatedAt = field.Time(added.AddDate(0, 0, i))
+		pinl.UpdatedAt = pinl.CreatedAt
+		assert.Nil(t, stores.Pinls.UpdateTimestamps(ctx, pinl))
+		assert.Nil(t, stores.Sharepins.Create(ctx, &model.Sharepin{ShareID: share.ID, PinlID: pinl.ID, Status: model.Active}))
+
+		// Only the first pinl is tagged.
+		if i == 0 {
+			assert.Nil(t, stores.Taggables.Create(ctx, &model.Taggable{TagID: golang.ID, TargetID: pinl.ID, TargetName: pinl.MorphName()}))
+		}
+	}
+	return share
+}
+
+func serveSharing(s *Server, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.SharingRouter().ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	return w
+}
+
+func TestSharingPageHandler(t *testing.T) {
+	s, stores, cleanup := newTestSharingServer(t)
+	defer cleanup()
+	seedSharing(t, stores)
+
+	// The latest added pinls come first.
+	w := serveSharing(s, "/alice/go?page_size=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	third, second := strings.Index(body, ">Third<"), strings.Index(body, ">Second<")
+	assert.True(t, third >= 0 && second > third)
+	assert.NotContains(t, body, ">First<")
+	assert.Contains(t, body, `href="/alice/go?page=2&amp;page_size=2"`)
+	assert.NotContains(t, body, "Previous")
+
+	w = serveSharing(s, "/alice/go?page=2&page_size=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	body = w.Body.String()
+	assert.Contains(t, body, ">First<")
+	assert.NotContains(t, body, ">Second<")
+	assert.Contains(t, body, "Previous")
+	assert.NotContains(t, body, "Next")
+
+	// Filter by tag.
+	w = serveSharing(s, "/alice/go?tag=lang/go")
+	assert.Equal(t, http.StatusOK, w.Code)
+	body = w.Body.String()
+	assert.Contains(t, body, ">First<")
+	assert.NotContains(t, body, ">Third<")
+	assert.Contains(t, body, `<li class="active"><a href="/alice/go?tag=lang%2Fgo">lang/go</a>`)
+
+	w = serveSharing(s, "/alice/not-exist")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSharingAtomHandler(t *testing.T) {
+	s, stores, cleanup := newTestSharingServer(t)
+	defer cleanup()
+	seedSharing(t, stores)
+
+	w := serveSharing(s, "/alice/go/atom")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var atom struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID       string `xml:"id"`
+			Title    string `xml:"title"`
+			Category []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	assert.Nil(t, xml.Unmarshal(w.Body.Bytes(), &atom))
+	assert.Equal(t, "http://example.com/alice/go", atom.ID)
+	assert.Equal(t, "Go", atom.Title)
+	if assert.Len(t, atom.Entries, 3) {
+		assert.Equal(t, "Third", atom.Entries[0].Title)
+		assert.True(t, strings.HasPrefix(atom.Entries[0].ID, "urn:pinmonl:pinl:"))
+		assert.Equal(t, "First", atom.Entries[2].Title)
+		if assert.Len(t, atom.Entries[2].Category, 1) {
+			assert.Equal(t, "lang/go", atom.Entries[2].Category[0].Term)
+		}
+	}
+}
+
+func TestSharingTagTree(t *testing.T) {
+	s, stores, cleanup := newTestSharingServer(t)
+	defer cleanup()
+	share := seedSharing(t, stores)
+
+	tags, err := s.sharingTagTree(context.TODO(), share, "/alice/go", "lang/go")
+	assert.Nil(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "lang", tags[0].Name)
+		assert.Equal(t, "/alice/go?tag=lang", tags[0].URL)
+		assert.False(t, tags[0].Active)
+		if assert.Len(t, tags[0].Children, 1) {
+			assert.Equal(t, "lang/go", tags[0].Children[0].Name)
+			assert.True(t, tags[0].Children[0].Active)
+		}
+	}
+}
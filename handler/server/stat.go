@@ -40,6 +40,10 @@ func (s *Server) statListHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	stats, err := s.Stats.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		response.JSON(w, err, http.StatusInternalServerError)
 		return
@@ -51,5 +55,7 @@ func (s *Server) statListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.ListJSON(w, stats, pg.ToPageInfo(count), http.StatusOK)
+	response.ListJSON(w, stats, pg.ToCursorPageInfo(count, len(stats), func(i int) string {
+		return store.RecordedCursor(stats[i])
+	}), http.StatusOK)
 }
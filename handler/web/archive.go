@@ -0,0 +1,90 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/queue/job"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+func (s *Server) archiveStores() storeutils.ArchiveStores {
+	return storeutils.ArchiveStores{
+		Images:    s.Images,
+		Pinls:     s.Pinls,
+		Presets:   s.Presets,
+		Shares:    s.Shares,
+		Sharetags: s.Sharetags,
+		Taggables: s.Taggables,
+		Tags:      s.Tags,
+	}
+}
+
+func (s *Server) exportHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+	)
+
+	archive, err := storeutils.ExportArchive(ctx, s.archiveStores(), user.ID)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("pinmonl-%s.json", time.Now().Format("20060102"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	response.JSON(w, archive, http.StatusOK)
+}
+
+func (s *Server) archiveImportHandler(w http.ResponseWriter, r *http.Request) {
+	file, err := openImportFile(w, r, maxArchiveImportSize)
+	if err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var archive storeutils.Archive
+	if err := json.NewDecoder(file).Decode(&archive); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := archive.Validate(); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		user   = request.AuthedFrom(ctx)
+		result *storeutils.ArchiveImportResult
+		code   int
+		outerr error
+	)
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		result2, err := storeutils.ImportArchive(ctx, s.archiveStores(), user.ID, &archive)
+		if err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		result = result2
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+
+	for _, pinlID := range result.CreatedPinlIDs {
+		s.Queue.Add(job.NewPinlUpdated(pinlID))
+	}
+	response.JSON(w, result, http.StatusOK)
+}
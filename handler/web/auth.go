@@ -41,6 +41,37 @@ func (s *Server) authorize() func(http.Handler) http.Handler {
 	return request.Authorize()
 }
 
+// authorizeAdmin checks the request is from the default user or the
+// admins, it follows authorize.
+func (s *Server) authorizeAdmin() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			user := request.AuthedFrom(r.Context())
+			if !s.isAdmin(user) {
+				response.JSON(w, nil, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func (s *Server) isAdmin(user *model.User) bool {
+	if user == nil {
+		return false
+	}
+	if s.hasDefaultUser() {
+		return user.ID == s.DefaultUserID
+	}
+	for _, login := range s.Admins {
+		if login == user.Login {
+			return true
+		}
+	}
+	return false
+}
+
 type loginBody struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
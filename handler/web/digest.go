@@ -0,0 +1,124 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+)
+
+func (s *Server) digestHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+	)
+
+	digest, err := s.Digests.FindUser(ctx, user.ID)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	if digest == nil {
+		response.JSON(w, nil, http.StatusNotFound)
+		return
+	}
+	response.JSON(w, digest, http.StatusOK)
+}
+
+type digestBody struct {
+	Email     string                `json:"email"`
+	Frequency model.DigestFrequency `json:"frequency"`
+	Enabled   *bool                 `json:"enabled"`
+}
+
+func (s *Server) digestSaveHandler(w http.ResponseWriter, r *http.Request) {
+	var in digestBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		user   = request.AuthedFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	digest, err := s.Digests.FindUser(ctx, user.ID)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	if digest == nil {
+		digest = &model.Digest{
+			UserID:  user.ID,
+			Enabled: true,
+		}
+	}
+	digest.Email = in.Email
+	digest.Frequency = in.Frequency
+	if in.Enabled != nil {
+		digest.Enabled = *in.Enabled
+	}
+	if err := digest.Validate(); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		var err error
+		if digest.ID == "" {
+			err = s.Digests.Create(ctx, digest)
+		} else {
+			err = s.Digests.Update(ctx, digest)
+		}
+		if err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, digest, http.StatusOK)
+}
+
+func (s *Server) digestDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = request.AuthedFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	digest, err := s.Digests.FindUser(ctx, user.ID)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	if digest == nil {
+		response.JSON(w, nil, http.StatusNotFound)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if _, err := s.Digests.Delete(ctx, digest.ID); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, nil, http.StatusNoContent)
+}
@@ -0,0 +1,180 @@
+package web
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pinmonl/pinmonl/pkgs/feed"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/pkgs/tagutils"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+// feedLimit is the number of releases in the feed.
+const feedLimit = 50
+
+var feedContentTmpl = template.Must(template.New("release").Parse(
+	`<p><strong>{{.Stat.Value}}</strong> ({{.Stat.Kind}}) of {{.Pkg.Provider}} <a href="{{.Pkg.URL}}">{{.Pkg.ProviderURI}}</a></p>
+<ul>
+{{range .Pinls}}<li><a href="{{.URL}}">{{if .Title}}{{.Title}}{{else}}{{.URL}}{{end}}</a></li>
+{{end}}</ul>{{if .Stat.Body}}
+<pre>{{.Stat.Body}}</pre>{{end}}{{if .Stat.Assets}}
+<ul>
+{{range .Stat.Assets}}<li><a href="{{.URL}}">{{.Name}}</a></li>
+{{end}}</ul>{{end}}`))
+
+// feedAuthenticate accepts the feed token in addition to the
+// access token.
+func (s *Server) feedAuthenticate() func(http.Handler) http.Handler {
+	return request.AuthenticateFeed(s.TokenSecret, s.Users)
+}
+
+type feedTokenResponse struct {
+	Token string `json:"token"`
+	Atom  string `json:"atom"`
+	RSS   string `json:"rss"`
+	JSON  string `json:"json"`
+}
+
+// feedTokenHandler creates the token for feed readers which
+// cannot send the authorization header.
+func (s *Server) feedTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+	)
+
+	token, err := request.GenerateFeedToken(s.TokenIssuer, s.FeedTokenExpire, s.TokenSecret, user)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	base := request.BaseURL(r, s.TrustProxy) + "/api/feed"
+	response.JSON(w, feedTokenResponse{
+		Token: token,
+		Atom:  base + "/atom?token=" + token,
+		RSS:   base + "/rss?token=" + token,
+		JSON:  base + "/json?token=" + token,
+	}, http.StatusOK)
+}
+
+func (s *Server) feedAtomHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeReleaseFeed(w, r, feed.AtomContentType, feed.WriteAtom)
+}
+
+func (s *Server) feedRSSHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeReleaseFeed(w, r, feed.RSSContentType, feed.WriteRSS)
+}
+
+func (s *Server) feedJSONHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeReleaseFeed(w, r, feed.JSONContentType, feed.WriteJSON)
+}
+
+func (s *Server) writeReleaseFeed(w http.ResponseWriter, r *http.Request, contentType string, write func(io.Writer, *feed.Feed) error) {
+	f, err := s.releaseFeed(r)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, f); err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// releaseFeed builds the feed of the latest releases of the user's
+// pinls, filtered by the tags in query parameter "tag".
+func (s *Server) releaseFeed(r *http.Request) (*feed.Feed, error) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+		tags = request.QueryCsv(r, "tag")
+	)
+
+	opts := &store.PinlOpts{
+		UserID: user.ID,
+	}
+	if len(tags) > 0 {
+		opts.TagNamePatterns = make([]string, len(tags))
+		for i := range tags {
+			opts.TagNamePatterns[i] = tagutils.ToNamePattern(tags[i])
+		}
+	}
+
+	releases, err := storeutils.ListFeedReleases(ctx, storeutils.FeedStores{
+		Monpkgs: s.Monpkgs,
+		Pinls:   s.Pinls,
+		Stats:   s.Stats,
+	}, opts, feedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	base := request.BaseURL(r, s.TrustProxy)
+	f := &feed.Feed{
+		ID:          "urn:pinmonl:feed:" + user.ID,
+		Title:       "Pinmonl releases",
+		Link:        base + "/",
+		FeedLink:    base + r.URL.RequestURI(),
+		Description: "Latest releases of the pinls",
+		Author:      user.Name,
+		Updated:     time.Now(),
+		Items:       make([]*feed.Item, 0, len(releases)),
+	}
+	if len(releases) > 0 {
+		f.Updated = releases[0].ReleasedAt()
+	}
+	for _, release := range releases {
+		item, err := releaseFeedItem(release)
+		if err != nil {
+			return nil, err
+		}
+		f.Items = append(f.Items, item)
+	}
+	return f, nil
+}
+
+func releaseFeedItem(release *storeutils.FeedRelease) (*feed.Item, error) {
+	var content bytes.Buffer
+	if err := feedContentTmpl.Execute(&content, release); err != nil {
+		return nil, err
+	}
+
+	name := release.Pkg.ProviderURI
+	link := release.Pkg.URL
+	if len(release.Pinls) > 0 {
+		if title := release.Pinls[0].Title; title != "" {
+			name = title
+		}
+		link = release.Pinls[0].URL
+	}
+
+	return &feed.Item{
+		ID:         releaseFeedID(release),
+		Title:      name + " " + release.Stat.Value,
+		Link:       link,
+		Content:    content.String(),
+		Categories: []string{string(release.Stat.Kind)},
+		Published:  release.ReleasedAt(),
+	}, nil
+}
+
+// releaseFeedID identifies the release by pkg, kind and value, which
+// stays the same across syncs.
+func releaseFeedID(release *storeutils.FeedRelease) string {
+	return "urn:pinmonl:release:" + url.PathEscape(release.Stat.PkgID) +
+		":" + url.PathEscape(string(release.Stat.Kind)) +
+		":" + url.PathEscape(release.Stat.Value)
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pinmonl/pinmonl/pkgs/bookmark"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/queue/job"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+// Size limits of the uploaded import file.
+const (
+	maxBookmarkImportSize = 32 << 20
+	maxArchiveImportSize  = 256 << 20
+)
+
+type bookmarkImportResponse struct {
+	Created   int                         `json:"created"`
+	Duplicate int                         `json:"duplicate"`
+	Invalid   int                         `json:"invalid"`
+	Results   storeutils.ImportResultList `json:"results"`
+}
+
+// openImportFile returns the uploaded file from multipart form
+// or the request body otherwise.
+func openImportFile(w http.ResponseWriter, r *http.Request, maxSize int64) (io.ReadCloser, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+	return r.Body, nil
+}
+
+func (s *Server) bookmarkImportHandler(w http.ResponseWriter, r *http.Request) {
+	file, err := openImportFile(w, r, maxBookmarkImportSize)
+	if err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	bookmarks, err := bookmark.ParseNetscape(file)
+	if err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx     = r.Context()
+		user    = request.AuthedFrom(ctx)
+		results storeutils.ImportResultList
+		code    int
+		outerr  error
+	)
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		results2, err := storeutils.ImportBookmarks(ctx, s.Pinls, s.Tags, s.Taggables, user.ID, bookmarks)
+		if err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		results = results2
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+
+	for _, pinlID := range results.PinlIDs() {
+		s.Queue.Add(job.NewPinlUpdated(pinlID))
+	}
+	response.JSON(w, bookmarkImportResponse{
+		Created:   results.Count(storeutils.ImportCreated),
+		Duplicate: results.Count(storeutils.ImportDuplicate),
+		Invalid:   results.Count(storeutils.ImportInvalid),
+		Results:   results,
+	}, http.StatusOK)
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/queue"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+func (s *Server) jobListHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := request.ParseJobQuery(r)
+	if err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx = r.Context()
+		pg  = request.PaginatorFrom(ctx)
+	)
+
+	opts := &store.JobOpts{
+		ListOpts:   pg.ToOpts(),
+		Names:      query.Names,
+		Status:     query.Status,
+		TargetIDs:  query.TargetIDs,
+		TargetName: query.TargetName,
+		Orders:     []store.JobOrder{store.JobOrderByLatest},
+	}
+
+	jList, err := s.Jobs.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	count, err := s.Jobs.Count(ctx, opts)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.ListJSON(w, jList, pg.ToCursorPageInfo(count, len(jList), func(i int) string {
+		return store.CreatedCursor(jList[i].CreatedAt, jList[i].ID)
+	}), http.StatusOK)
+}
+
+func (s *Server) jobRequeueHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		jobID = chi.URLParam(r, "job")
+	)
+
+	job, err := s.Jobs.Find(ctx, jobID)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		response.JSON(w, nil, http.StatusNotFound)
+		return
+	}
+
+	err = s.Queue.Requeue(ctx, job)
+	switch err {
+	case nil:
+	case queue.ErrJobNotDead, queue.ErrJobPending:
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	default:
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.JSON(w, job, http.StatusOK)
+}
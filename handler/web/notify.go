@@ -0,0 +1,257 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/generate"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// notifySecretLength is the length of the generated webhook secret.
+const notifySecretLength = 32
+
+func (s *Server) bindNotifyRule() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			var (
+				ctx    = r.Context()
+				user   = request.AuthedFrom(ctx)
+				ruleID = chi.URLParam(r, "rule")
+			)
+
+			rule, err := s.NotifyRules.Find(ctx, ruleID)
+			if err != nil {
+				response.JSON(w, err, http.StatusInternalServerError)
+				return
+			}
+			if rule == nil || rule.UserID != user.ID {
+				response.JSON(w, nil, http.StatusNotFound)
+				return
+			}
+
+			ctx = request.WithNotifyRule(ctx, rule)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func (s *Server) notifyRuleListHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+		pg   = request.PaginatorFrom(ctx)
+	)
+
+	opts := &store.NotifyRuleOpts{
+		ListOpts: pg.ToOpts(),
+		UserID:   user.ID,
+	}
+
+	nList, err := s.NotifyRules.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	count, err := s.NotifyRules.Count(ctx, opts)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.ListJSON(w, nList, pg.ToCursorPageInfo(count, len(nList), func(i int) string {
+		return store.CreatedCursor(nList[i].CreatedAt, nList[i].ID)
+	}), http.StatusOK)
+}
+
+func (s *Server) notifyRuleHandler(w http.ResponseWriter, r *http.Request) {
+	rule := request.NotifyRuleFrom(r.Context())
+	response.JSON(w, rule, http.StatusOK)
+}
+
+type notifyRuleBody struct {
+	Name              string             `json:"name"`
+	URL               string             `json:"url"`
+	Secret            string             `json:"secret"`
+	TagNames          field.StringList   `json:"tags"`
+	PinlIDs           field.StringList   `json:"pinlIds"`
+	PkgIDs            field.StringList   `json:"pkgIds"`
+	Kinds             field.StringList   `json:"kinds"`
+	Constraint        string             `json:"constraint"`
+	Level             model.ReleaseLevel `json:"level"`
+	ExcludePrerelease bool               `json:"excludePrerelease"`
+	Enabled           *bool              `json:"enabled"`
+}
+
+func (in notifyRuleBody) apply(rule *model.NotifyRule) error {
+	if in.Name == "" {
+		return errors.New("name is required")
+	}
+
+	rule.Name = in.Name
+	rule.URL = in.URL
+	rule.TagNames = in.TagNames
+	rule.PinlIDs = in.PinlIDs
+	rule.PkgIDs = in.PkgIDs
+	rule.Kinds = in.Kinds
+	rule.Constraint = in.Constraint
+	rule.Level = in.Level
+	rule.ExcludePrerelease = in.ExcludePrerelease
+	if in.Secret != "" {
+		rule.Secret = in.Secret
+	}
+	if rule.Secret == "" {
+		rule.Secret = generate.AlphaNum(notifySecretLength)
+	}
+	if in.Enabled != nil {
+		rule.Enabled = *in.Enabled
+	}
+	return rule.Validate()
+}
+
+func (s *Server) notifyRuleCreateHandler(w http.ResponseWriter, r *http.Request) {
+	var in notifyRuleBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		user   = request.AuthedFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	rule := &model.NotifyRule{
+		UserID:  user.ID,
+		Enabled: true,
+	}
+	if err := in.apply(rule); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if err := s.NotifyRules.Create(ctx, rule); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, rule, http.StatusOK)
+}
+
+func (s *Server) notifyRuleUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	var in notifyRuleBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		rule   = request.NotifyRuleFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	if err := in.apply(rule); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if err := s.NotifyRules.Update(ctx, rule); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, rule, http.StatusOK)
+}
+
+func (s *Server) notifyRuleDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		rule   = request.NotifyRuleFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if _, err := s.NotifyDeliveries.DeleteByRule(ctx, rule.ID); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		if _, err := s.NotifyRules.Delete(ctx, rule.ID); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, nil, http.StatusNoContent)
+}
+
+func (s *Server) notifyDeliveryListHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		rule = request.NotifyRuleFrom(ctx)
+		pg   = request.PaginatorFrom(ctx)
+	)
+
+	opts := &store.NotifyDeliveryOpts{
+		ListOpts: pg.ToOpts(),
+		RuleIDs:  []string{rule.ID},
+		Orders:   []store.NotifyDeliveryOrder{store.NotifyDeliveryOrderByLatest},
+	}
+
+	dList, err := s.NotifyDeliveries.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	count, err := s.NotifyDeliveries.Count(ctx, opts)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.ListJSON(w, dList, pg.ToCursorPageInfo(count, len(dList), func(i int) string {
+		return store.CreatedCursor(dList[i].CreatedAt, dList[i].ID)
+	}), http.StatusOK)
+}
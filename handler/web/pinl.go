@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/pinmonl/pinmonl/handler/common"
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/pkgs/monlutils"
+	"github.com/pinmonl/pinmonl/pkgs/pinlquery"
 	"github.com/pinmonl/pinmonl/pkgs/pinlutils"
 	"github.com/pinmonl/pinmonl/pkgs/request"
 	"github.com/pinmonl/pinmonl/pkgs/response"
@@ -36,20 +38,43 @@ func (s *Server) pinlListHandler(w http.ResponseWriter, r *http.Request) {
 	)
 
 	opts := &store.PinlOpts{
-		UserID:   user.ID,
-		Query:    query.Query,
-		ListOpts: pg.ToOpts(),
-		NoTag:    query.NoTag,
-		Orders:   []store.PinlOrder{store.PinlOrderByLatest},
-	}
-	if len(query.Tags) > 0 {
-		opts.TagNamePatterns = make([]string, len(query.Tags))
-		for i := range query.Tags {
-			opts.TagNamePatterns[i] = tagutils.ToNamePattern(query.Tags[i])
+		UserID:        user.ID,
+		SearchContent: query.Content.Valid && query.Content.Value(),
+		ListOpts:      pg.ToOpts(),
+		NoTag:         query.NoTag,
+	}
+	tagNames := query.Tags
+	if query.PresetID != "" {
+		preset, err := s.findPreset(ctx, user.ID, query.PresetID)
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		if preset == nil {
+			response.JSON(w, nil, http.StatusNotFound)
+			return
 		}
+		if err := applyPinlSearch(opts, preset.Query, preset.TagNames, preset.Sort); err != nil {
+			response.JSON(w, err, http.StatusBadRequest)
+			return
+		}
+		tagNames = append(preset.TagNames, tagNames...)
+	}
+	if err := applyPinlSearch(opts, query.Query, query.Tags, query.Sort); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	setDefaultPinlOrders(opts)
+	if err := s.setPinlPositionTag(ctx, opts, user.ID, tagNames); err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
 	}
 
 	pList, err := storeutils.ListPinlsWithLatestStats(ctx, s.Pinls, s.Monpkgs, s.Stats, s.Taggables, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		response.JSON(w, err, http.StatusInternalServerError)
 		return
@@ -61,7 +86,81 @@ func (s *Server) pinlListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.ListJSON(w, pList, pg.ToPageInfo(count), http.StatusOK)
+	response.ListJSON(w, pList, pinlPageInfo(pg, pList, count), http.StatusOK)
+}
+
+// setPinlPositionTag sets the tag of manual position, which requires
+// the pinls to be selected by exactly one tag.
+func (s *Server) setPinlPositionTag(ctx context.Context, opts *store.PinlOpts, userID string, tagNames []string) error {
+	sortByPosition := false
+	for _, order := range opts.Orders {
+		if order == store.PinlOrderByPosition || order == store.PinlOrderByPositionDesc {
+			sortByPosition = true
+		}
+	}
+	if !sortByPosition || len(tagNames) != 1 {
+		return nil
+	}
+
+	tag, err := s.Tags.FindName(ctx, userID, tagNames[0])
+	if err != nil {
+		return err
+	}
+	if tag != nil {
+		opts.PositionTagID = tag.ID
+	}
+	return nil
+}
+
+// pinlPageInfo returns the page info with the cursors of the pages
+// around pList.
+func pinlPageInfo(pg *request.Paginator, pList model.PinlList, count int64) *response.PageInfo {
+	return pg.ToCursorPageInfo(count, len(pList), func(i int) string {
+		return pList[i].Cursor
+	})
+}
+
+// applyPinlSearch adds the search query, tags and sort to opts, so
+// that the query of preset and request can be combined.
+func applyPinlSearch(opts *store.PinlOpts, q string, tags []string, sort string) error {
+	search, err := pinlquery.Parse(q)
+	if err != nil {
+		return err
+	}
+	if search.Text != "" {
+		opts.Query = strings.TrimSpace(opts.Query + " " + search.Text)
+	}
+	if search.Filter != nil {
+		if opts.Filter == nil {
+			opts.Filter = search.Filter
+		} else {
+			opts.Filter = store.PinlFilterAnd{opts.Filter, search.Filter}
+		}
+	}
+	for _, tag := range tags {
+		opts.TagNamePatterns = append(opts.TagNamePatterns, tagutils.ToNamePattern(tag))
+	}
+	if sort != "" {
+		orders, err := request.ParsePinlSort(sort)
+		if err != nil {
+			return err
+		}
+		opts.Orders = orders
+	}
+	return nil
+}
+
+// setDefaultPinlOrders sorts by relevance for search, otherwise by
+// latest.
+func setDefaultPinlOrders(opts *store.PinlOpts) {
+	if len(opts.Orders) > 0 {
+		return
+	}
+	if opts.Query != "" {
+		opts.Orders = []store.PinlOrder{store.PinlOrderByRelevance, store.PinlOrderByLatest}
+	} else {
+		opts.Orders = []store.PinlOrder{store.PinlOrderByLatest}
+	}
 }
 
 func (s *Server) pinlHandler(w http.ResponseWriter, r *http.Request) {
@@ -180,6 +279,9 @@ func (s *Server) pinlCreateHandler(w http.ResponseWriter, r *http.Request) {
 	if s.ExchangeEnabled && monl.FetchedAt.Time().IsZero() {
 		s.Queue.Add(job.NewFetchMonl(monl.ID))
 	}
+	if s.SearchContent {
+		s.Queue.Add(job.NewFetchPinlContent(pinl.ID))
+	}
 	s.Pubsub.Broadcast(message.NewPinlUpdated(pinl))
 	response.JSON(w, pinl, http.StatusOK)
 }
@@ -203,6 +305,7 @@ func (s *Server) pinlUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		monl   *model.Monl
 		code   int
 		outerr error
+		oldURL = pinl.URL
 	)
 	pinl.MonlID = ""
 	pinl.URL = in.URL
@@ -228,6 +331,9 @@ func (s *Server) pinlUpdateHandler(w http.ResponseWriter, r *http.Request) {
 	if s.ExchangeEnabled && monl.FetchedAt.Time().IsZero() {
 		s.Queue.Add(job.NewFetchMonl(monl.ID))
 	}
+	if s.SearchContent && pinl.URL != oldURL {
+		s.Queue.Add(job.NewFetchPinlContent(pinl.ID))
+	}
 	s.Pubsub.Broadcast(message.NewPinlUpdated(pinl))
 	response.JSON(w, pinl, http.StatusOK)
 }
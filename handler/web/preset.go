@@ -0,0 +1,209 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/pinlquery"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+func (s *Server) bindPreset() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			var (
+				ctx  = r.Context()
+				user = request.AuthedFrom(ctx)
+			)
+
+			preset, err := s.findPreset(ctx, user.ID, chi.URLParam(r, "preset"))
+			if err != nil {
+				response.JSON(w, err, http.StatusInternalServerError)
+				return
+			}
+			if preset == nil {
+				response.JSON(w, nil, http.StatusNotFound)
+				return
+			}
+
+			ctx = request.WithPreset(ctx, preset)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// findPreset finds the preset owned by user. Nil is returned if not
+// found.
+func (s *Server) findPreset(ctx context.Context, userID, presetID string) (*model.Preset, error) {
+	preset, err := s.Presets.Find(ctx, presetID)
+	if err != nil {
+		return nil, err
+	}
+	if preset == nil || preset.UserID != userID {
+		return nil, nil
+	}
+	return preset, nil
+}
+
+func (s *Server) presetListHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = request.AuthedFrom(ctx)
+		pg   = request.PaginatorFrom(ctx)
+	)
+
+	opts := &store.PresetOpts{
+		ListOpts: pg.ToOpts(),
+		UserID:   user.ID,
+	}
+
+	pList, err := s.Presets.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	count, err := s.Presets.Count(ctx, opts)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.ListJSON(w, pList, pg.ToPageInfo(count), http.StatusOK)
+}
+
+func (s *Server) presetHandler(w http.ResponseWriter, r *http.Request) {
+	preset := request.PresetFrom(r.Context())
+	response.JSON(w, preset, http.StatusOK)
+}
+
+type presetBody struct {
+	Name  string           `json:"name"`
+	Query string           `json:"query"`
+	Tags  field.StringList `json:"tags"`
+	Sort  string           `json:"sort"`
+}
+
+func (in presetBody) apply(preset *model.Preset) error {
+	if _, err := pinlquery.Parse(in.Query); err != nil {
+		return err
+	}
+	if _, err := request.ParsePinlSort(in.Sort); err != nil {
+		return err
+	}
+
+	preset.Name = in.Name
+	preset.Query = in.Query
+	preset.TagNames = in.Tags
+	preset.Sort = in.Sort
+	return preset.Validate()
+}
+
+func (s *Server) presetCreateHandler(w http.ResponseWriter, r *http.Request) {
+	var in presetBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		user   = request.AuthedFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	preset := &model.Preset{UserID: user.ID}
+	if err := in.apply(preset); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if err := s.Presets.Create(ctx, preset); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, preset, http.StatusOK)
+}
+
+func (s *Server) presetUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	var in presetBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		preset = request.PresetFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	if err := in.apply(preset); err != nil {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if err := s.Presets.Update(ctx, preset); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, preset, http.StatusOK)
+}
+
+func (s *Server) presetDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		preset = request.PresetFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if _, err := s.Shares.ClearPreset(ctx, preset.ID); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		if _, err := s.Presets.Delete(ctx, preset.ID); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, nil, http.StatusNoContent)
+}
@@ -35,6 +35,10 @@ func (s *Server) shareListHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sList, err := s.Shares.List(ctx, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		response.JSON(w, err, http.StatusInternalServerError)
 		return
@@ -56,7 +60,9 @@ func (s *Server) shareListHandler(w http.ResponseWriter, r *http.Request) {
 	sList.SetMustTagNames(stList.GetKind(model.SharetagMust).TagsByShare())
 	sList.SetAnyTagNames(stList.GetKind(model.SharetagAny).TagsByShare())
 
-	response.ListJSON(w, sList, pg.ToPageInfo(count), http.StatusOK)
+	response.ListJSON(w, sList, pg.ToCursorPageInfo(count, len(sList), func(i int) string {
+		return store.CreatedCursor(sList[i].CreatedAt, sList[i].ID)
+	}), http.StatusOK)
 }
 
 // shareHandler finds share by id.
@@ -84,6 +90,7 @@ type shareBody struct {
 	Description string   `json:"description"`
 	MustTags    []string `json:"mustTags"`
 	AnyTags     []string `json:"anyTags"`
+	PresetID    string   `json:"presetId"`
 }
 
 // shareCreateHandler creates share.
@@ -100,8 +107,8 @@ func (s *Server) shareCreateHandler(w http.ResponseWriter, r *http.Request) {
 		response.JSON(w, err, http.StatusBadRequest)
 		return
 	}
-	if len(in.MustTags) == 0 {
-		response.JSON(w, errors.New("must tag is required"), http.StatusBadRequest)
+	if len(in.MustTags) == 0 && in.PresetID == "" {
+		response.JSON(w, errors.New("must tag or preset is required"), http.StatusBadRequest)
 		return
 	}
 	in.AnyTags = cleanupAnyTags(in.MustTags, in.AnyTags)
@@ -113,6 +120,18 @@ func (s *Server) shareCreateHandler(w http.ResponseWriter, r *http.Request) {
 		outerr error
 	)
 
+	if in.PresetID != "" {
+		preset, err := s.findPreset(ctx, user.ID, in.PresetID)
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		if preset == nil {
+			response.JSON(w, errors.New("preset is not found"), http.StatusBadRequest)
+			return
+		}
+	}
+
 	share, err := s.Shares.FindSlug(ctx, user.ID, slug)
 	if err != nil {
 		response.JSON(w, err, http.StatusInternalServerError)
@@ -126,6 +145,7 @@ func (s *Server) shareCreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	share.Name = in.Name
 	share.Description = in.Description
+	share.PresetID = in.PresetID
 
 	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
 		var err error
@@ -276,6 +296,75 @@ func (s *Server) sharetagListHandler(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, stList.ViewTags(), http.StatusOK)
 }
 
+// sharePinlListHandler lists the pinls selected by the must tags
+// and the preset of share.
+func (s *Server) sharePinlListHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		share = request.ShareFrom(ctx)
+		pg    = request.PaginatorFrom(ctx)
+	)
+
+	stList, err := s.Sharetags.ListWithTag(ctx, &store.SharetagOpts{
+		ShareIDs: []string{share.ID},
+		Kind:     field.NewNullValue(model.SharetagMust),
+	})
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	opts := &store.PinlOpts{
+		UserID:   share.UserID,
+		TagNames: stList.Tags().Names(),
+		ListOpts: pg.ToOpts(),
+	}
+	var preset *model.Preset
+	if share.PresetID != "" {
+		preset, err = s.findPreset(ctx, share.UserID, share.PresetID)
+		if err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+	// Nothing is selected without must tag and preset.
+	if len(opts.TagNames) == 0 && preset == nil {
+		response.ListJSON(w, model.PinlList{}, pg.ToPageInfo(0), http.StatusOK)
+		return
+	}
+	tagNames := opts.TagNames
+	if preset != nil {
+		if err := applyPinlSearch(opts, preset.Query, preset.TagNames, preset.Sort); err != nil {
+			response.JSON(w, err, http.StatusInternalServerError)
+			return
+		}
+		tagNames = append(tagNames, preset.TagNames...)
+	}
+	setDefaultPinlOrders(opts)
+	if err := s.setPinlPositionTag(ctx, opts, share.UserID, tagNames); err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	pList, err := storeutils.ListPinlsWithLatestStats(ctx, s.Pinls, s.Monpkgs, s.Stats, s.Taggables, opts)
+	if errors.Is(err, store.ErrInvalidCursor) {
+		response.JSON(w, err, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	count, err := s.Pinls.Count(ctx, opts)
+	if err != nil {
+		response.JSON(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.ListJSON(w, pList, pinlPageInfo(pg, pList, count), http.StatusOK)
+}
+
 // sharetagCreateAnyKindHandler creates sharetag with any kind.
 func (s *Server) sharetagCreateAnyKindHandler(w http.ResponseWriter, r *http.Request) {
 	var in shareBody
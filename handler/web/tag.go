@@ -1,9 +1,13 @@
 package web
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/pinmonl/pinmonl/handler/common"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/request"
+	"github.com/pinmonl/pinmonl/pkgs/response"
 )
 
 func (s *Server) bindTag() func(http.Handler) http.Handler {
@@ -34,3 +38,39 @@ func (s *Server) tagDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	h := common.TagDeleteHandler(s.Txer, s.Tags, s.Taggables)
 	h.ServeHTTP(w, r)
 }
+
+type tagPositionBody struct {
+	PinlIDs []string `json:"pinlIds"`
+}
+
+// tagPositionHandler saves the manual positions of pinls under tag,
+// which are sorted by "position".
+func (s *Server) tagPositionHandler(w http.ResponseWriter, r *http.Request) {
+	var in tagPositionBody
+	err := request.JSON(r, &in)
+	if err != nil {
+		response.JSON(w, nil, http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx    = r.Context()
+		tag    = request.TagFrom(ctx)
+		code   int
+		outerr error
+	)
+
+	s.Txer.TxFunc(ctx, func(ctx context.Context) bool {
+		if err := s.Taggables.SetPositions(ctx, tag, model.Pinl{}.MorphName(), in.PinlIDs); err != nil {
+			outerr, code = err, http.StatusInternalServerError
+			return false
+		}
+		return true
+	})
+
+	if outerr != nil || response.IsError(code) {
+		response.JSON(w, outerr, code)
+		return
+	}
+	response.JSON(w, nil, http.StatusNoContent)
+}
@@ -31,21 +31,38 @@ type Server struct {
 	Pubsub      pubsub.Pubsuber
 
 	ExchangeEnabled bool
+	SearchContent   bool
 	DefaultUserID   string
 	DevServer       string
 
-	Images    *store.Images
-	Monls     *store.Monls
-	Monpkgs   *store.Monpkgs
-	Pinls     *store.Pinls
-	Pkgs      *store.Pkgs
-	Sharepins *store.Sharepins
-	Shares    *store.Shares
-	Sharetags *store.Sharetags
-	Stats     *store.Stats
-	Taggables *store.Taggables
-	Tags      *store.Tags
-	Users     *store.Users
+	// Admins are the logins of users who manage the jobs, which are
+	// shared by all users. The default user is always admin.
+	Admins []string
+
+	// TrustProxy honors the forwarded headers in the links of response.
+	TrustProxy bool
+
+	// FeedTokenExpire is the lifetime of feed token, zero means that the
+	// token is valid until the password is changed.
+	FeedTokenExpire time.Duration
+
+	Digests          *store.Digests
+	Images           *store.Images
+	Jobs             *store.Jobs
+	Monls            *store.Monls
+	Monpkgs          *store.Monpkgs
+	NotifyDeliveries *store.NotifyDeliveries
+	NotifyRules      *store.NotifyRules
+	Pinls            *store.Pinls
+	Pkgs             *store.Pkgs
+	Presets          *store.Presets
+	Sharepins        *store.Sharepins
+	Shares           *store.Shares
+	Sharetags        *store.Sharetags
+	Stats            *store.Stats
+	Taggables        *store.Taggables
+	Tags             *store.Tags
+	Users            *store.Users
 }
 
 func (s *Server) Handler() http.Handler {
@@ -80,6 +97,14 @@ func (s *Server) APIRouter() chi.Router {
 		})
 	})
 
+	r.With(s.authorize()).
+		Get("/export", s.exportHandler)
+	r.Route("/import", func(r chi.Router) {
+		r.Use(s.authorize())
+		r.Post("/", s.archiveImportHandler)
+		r.Post("/bookmark", s.bookmarkImportHandler)
+	})
+
 	r.Get("/card", s.fetchCardHandler)
 
 	r.Route("/tag", func(r chi.Router) {
@@ -92,6 +117,7 @@ func (s *Server) APIRouter() chi.Router {
 			r.Get("/", s.tagHandler)
 			r.Put("/", s.tagUpdateHandler)
 			r.Delete("/", s.tagDeleteHandler)
+			r.Put("/position", s.tagPositionHandler)
 		})
 	})
 
@@ -105,6 +131,60 @@ func (s *Server) APIRouter() chi.Router {
 			Get("/", s.statListHandler)
 	})
 
+	r.Route("/job", func(r chi.Router) {
+		r.Use(s.authorize(), s.authorizeAdmin())
+		r.With(s.pagination()).
+			Get("/", s.jobListHandler)
+		r.Post("/{job}/requeue", s.jobRequeueHandler)
+	})
+
+	r.Route("/digest", func(r chi.Router) {
+		r.Use(s.authorize())
+		r.Get("/", s.digestHandler)
+		r.Put("/", s.digestSaveHandler)
+		r.Delete("/", s.digestDeleteHandler)
+	})
+
+	r.Route("/feed", func(r chi.Router) {
+		r.With(s.authorize()).
+			Post("/token", s.feedTokenHandler)
+		r.Group(func(r chi.Router) {
+			r.Use(s.feedAuthenticate())
+			r.Use(s.authorize())
+			r.Get("/atom", s.feedAtomHandler)
+			r.Get("/rss", s.feedRSSHandler)
+			r.Get("/json", s.feedJSONHandler)
+		})
+	})
+
+	r.Route("/notify", func(r chi.Router) {
+		r.Use(s.authorize())
+		r.With(s.pagination()).
+			Get("/", s.notifyRuleListHandler)
+		r.Post("/", s.notifyRuleCreateHandler)
+		r.Route("/{rule}", func(r chi.Router) {
+			r.Use(s.bindNotifyRule())
+			r.Get("/", s.notifyRuleHandler)
+			r.Put("/", s.notifyRuleUpdateHandler)
+			r.Delete("/", s.notifyRuleDeleteHandler)
+			r.With(s.pagination()).
+				Get("/delivery", s.notifyDeliveryListHandler)
+		})
+	})
+
+	r.Route("/preset", func(r chi.Router) {
+		r.Use(s.authorize())
+		r.With(s.pagination()).
+			Get("/", s.presetListHandler)
+		r.Post("/", s.presetCreateHandler)
+		r.Route("/{preset}", func(r chi.Router) {
+			r.Use(s.bindPreset())
+			r.Get("/", s.presetHandler)
+			r.Put("/", s.presetUpdateHandler)
+			r.Delete("/", s.presetDeleteHandler)
+		})
+	})
+
 	r.Route("/share", func(r chi.Router) {
 		r.Use(s.authorize())
 		r.With(s.pagination()).
@@ -119,6 +199,8 @@ func (s *Server) APIRouter() chi.Router {
 
 				r.With(s.pagination()).
 					Get("/tag", s.sharetagListHandler)
+				r.With(s.pagination()).
+					Get("/pinl", s.sharePinlListHandler)
 			})
 		})
 	})
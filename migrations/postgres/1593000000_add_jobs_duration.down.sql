@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ix_jobs_status;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS duration;
+ALTER TABLE jobs DROP COLUMN IF EXISTS started_at;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS ix_jobs_run;
+DROP INDEX IF EXISTS ix_jobs_key;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS run_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS payload;
+ALTER TABLE jobs DROP COLUMN IF EXISTS job_key;
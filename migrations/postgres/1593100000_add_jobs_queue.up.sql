@@ -0,0 +1,6 @@
+ALTER TABLE jobs ADD COLUMN job_key VARCHAR(500) DEFAULT '';
+ALTER TABLE jobs ADD COLUMN payload TEXT DEFAULT '';
+ALTER TABLE jobs ADD COLUMN run_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS ix_jobs_key ON jobs (job_key);
+CREATE INDEX IF NOT EXISTS ix_jobs_run ON jobs (status, run_at);
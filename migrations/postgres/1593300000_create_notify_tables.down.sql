@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notify_deliveries;
+DROP TABLE IF EXISTS notify_rules;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS notify_rules (
+  id                 VARCHAR(50) PRIMARY KEY,
+  user_id            VARCHAR(50),
+  name               VARCHAR(250),
+  url                TEXT,
+  secret             VARCHAR(250),
+  tag_names          TEXT,
+  pinl_ids           TEXT,
+  pkg_ids            TEXT,
+  kinds              TEXT,
+  constraint_expr    VARCHAR(250),
+  level              VARCHAR(50),
+  exclude_prerelease BOOLEAN,
+  enabled            BOOLEAN,
+  created_at         TIMESTAMP,
+  updated_at         TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_notify_rules_user ON notify_rules (user_id);
+
+CREATE TABLE IF NOT EXISTS notify_deliveries (
+  id            VARCHAR(50) PRIMARY KEY,
+  rule_id       VARCHAR(50),
+  stat_id       VARCHAR(50),
+  event         VARCHAR(100),
+  payload       TEXT,
+  status        INTEGER,
+  attempts      INTEGER,
+  response_code INTEGER,
+  message       TEXT,
+  created_at    TIMESTAMP,
+  delivered_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_notify_deliveries_rule ON notify_deliveries (rule_id);
+CREATE INDEX IF NOT EXISTS ix_notify_deliveries_stat ON notify_deliveries (stat_id);
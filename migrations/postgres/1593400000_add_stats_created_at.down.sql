@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_stats_created;
+
+ALTER TABLE stats DROP COLUMN IF EXISTS created_at;
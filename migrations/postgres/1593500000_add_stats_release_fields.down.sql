@@ -0,0 +1,3 @@
+ALTER TABLE stats DROP COLUMN IF EXISTS assets;
+
+ALTER TABLE stats DROP COLUMN IF EXISTS body;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS ix_pinls_search;
+
+DROP TRIGGER IF EXISTS tr_pinls_search_vector ON pinls;
+DROP FUNCTION IF EXISTS fn_pinls_search_vector();
+
+ALTER TABLE pinls DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE pinls DROP COLUMN IF EXISTS content;
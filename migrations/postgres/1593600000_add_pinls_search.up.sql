@@ -0,0 +1,20 @@
+ALTER TABLE pinls ADD COLUMN content TEXT NOT NULL DEFAULT '';
+ALTER TABLE pinls ADD COLUMN search_vector TSVECTOR;
+
+CREATE OR REPLACE FUNCTION fn_pinls_search_vector() RETURNS TRIGGER AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'B') ||
+    setweight(to_tsvector('simple', COALESCE(NEW.url, '')), 'C') ||
+    setweight(to_tsvector('simple', COALESCE(NEW.content, '')), 'D');
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tr_pinls_search_vector BEFORE INSERT OR UPDATE ON pinls
+  FOR EACH ROW EXECUTE PROCEDURE fn_pinls_search_vector();
+
+UPDATE pinls SET content = content;
+
+CREATE INDEX IF NOT EXISTS ix_pinls_search ON pinls USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE shares DROP COLUMN IF EXISTS preset_id;
+
+DROP TABLE IF EXISTS presets;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS presets (
+  id          VARCHAR(50) PRIMARY KEY,
+  user_id     VARCHAR(50),
+  name        VARCHAR(250),
+  query       TEXT,
+  tags        TEXT,
+  sort        VARCHAR(250),
+  created_at  TIMESTAMP,
+  updated_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_presets_user ON presets (user_id);
+
+ALTER TABLE shares ADD COLUMN preset_id VARCHAR(50) NOT NULL DEFAULT '';
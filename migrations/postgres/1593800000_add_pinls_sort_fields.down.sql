@@ -0,0 +1,4 @@
+ALTER TABLE taggables DROP COLUMN IF EXISTS position;
+
+DROP INDEX IF EXISTS ix_pinls_host;
+ALTER TABLE pinls DROP COLUMN IF EXISTS host;
@@ -0,0 +1,7 @@
+ALTER TABLE pinls ADD COLUMN host VARCHAR(250) NOT NULL DEFAULT '';
+
+UPDATE pinls SET host = lower(COALESCE(substring(url from '^[^:/?#]+://(?:[^@/?#]*@)?([^:/?#]*)'), ''));
+
+CREATE INDEX IF NOT EXISTS ix_pinls_host ON pinls (host);
+
+ALTER TABLE taggables ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DELETE FROM stats WHERE parent_id IN (SELECT id FROM stats WHERE kind = 'release');
+DELETE FROM stats WHERE kind = 'release';
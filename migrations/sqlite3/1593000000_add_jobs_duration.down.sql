@@ -0,0 +1,24 @@
+DROP INDEX IF EXISTS ix_jobs_status;
+
+CREATE TABLE IF NOT EXISTS jobs_old (
+  id          VARCHAR(50) PRIMARY KEY,
+  name        VARCHAR(100),
+  describe    VARCHAR(250),
+  target_id   VARCHAR(50),
+  target_name VARCHAR(100),
+  status      INTEGER,
+  message     TEXT,
+  created_at  TIMESTAMP,
+  ended_at    TIMESTAMP
+);
+
+INSERT INTO jobs_old
+  SELECT id, name, describe, target_id, target_name, status, message, created_at, ended_at
+  FROM jobs;
+
+DROP TABLE jobs;
+ALTER TABLE jobs_old RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS ix_jobs_name ON jobs (name);
+CREATE INDEX IF NOT EXISTS ix_jobs_target ON jobs (target_id, target_name);
+CREATE INDEX IF NOT EXISTS ix_jobs_ended ON jobs (ended_at);
@@ -0,0 +1,4 @@
+ALTER TABLE jobs ADD COLUMN started_at TIMESTAMP;
+ALTER TABLE jobs ADD COLUMN duration BIGINT DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS ix_jobs_status ON jobs (status);
@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN attempts INTEGER DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE stats ADD COLUMN created_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS ix_stats_created ON stats (created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS digests (
+  id           VARCHAR(50) PRIMARY KEY,
+  user_id      VARCHAR(50),
+  email        VARCHAR(250),
+  frequency    VARCHAR(50),
+  enabled      BOOLEAN,
+  last_sent_at TIMESTAMP,
+  created_at   TIMESTAMP,
+  updated_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS ix_digests_user ON digests (user_id);
@@ -0,0 +1,36 @@
+DROP INDEX IF EXISTS ix_stats_created;
+DROP INDEX IF EXISTS ix_stats_pkg;
+DROP INDEX IF EXISTS ix_stats_latest;
+DROP INDEX IF EXISTS ix_stats_parent;
+DROP INDEX IF EXISTS ix_stats_kind;
+DROP INDEX IF EXISTS ix_stats_value_type;
+
+CREATE TABLE IF NOT EXISTS stats_old (
+  id           VARCHAR(50) PRIMARY KEY,
+  pkg_id       VARCHAR(50),
+  parent_id    VARCHAR(50),
+  recorded_at  TIMESTAMP,
+  kind         VARCHAR(50),
+  name         VARCHAR(250),
+  value        VARCHAR(250),
+  value_type   INTEGER,
+  checksum     VARCHAR(500),
+  weight       INTEGER,
+  is_latest    BOOLEAN,
+  has_children BOOLEAN,
+  created_at   TIMESTAMP
+);
+
+INSERT INTO stats_old
+  SELECT id, pkg_id, parent_id, recorded_at, kind, name, value, value_type, checksum, weight, is_latest, has_children, created_at
+  FROM stats;
+
+DROP TABLE stats;
+ALTER TABLE stats_old RENAME TO stats;
+
+CREATE INDEX IF NOT EXISTS ix_stats_pkg ON stats (pkg_id);
+CREATE INDEX IF NOT EXISTS ix_stats_latest ON stats (is_latest);
+CREATE INDEX IF NOT EXISTS ix_stats_parent ON stats (parent_id);
+CREATE INDEX IF NOT EXISTS ix_stats_kind ON stats (kind);
+CREATE INDEX IF NOT EXISTS ix_stats_value_type ON stats (value_type);
+CREATE INDEX IF NOT EXISTS ix_stats_created ON stats (created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE stats ADD COLUMN body TEXT NOT NULL DEFAULT '';
+ALTER TABLE stats ADD COLUMN assets TEXT NOT NULL DEFAULT '';
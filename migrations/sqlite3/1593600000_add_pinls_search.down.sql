@@ -0,0 +1,30 @@
+DROP TRIGGER IF EXISTS tr_pinls_fts_insert;
+DROP TRIGGER IF EXISTS tr_pinls_fts_update;
+DROP TRIGGER IF EXISTS tr_pinls_fts_delete;
+DROP TABLE IF EXISTS pinls_fts;
+
+DROP INDEX IF EXISTS ix_pinls_user;
+DROP INDEX IF EXISTS ix_pinls_monl;
+
+CREATE TABLE IF NOT EXISTS pinls_old (
+  id          VARCHAR(50) PRIMARY KEY,
+  user_id     VARCHAR(50),
+  monl_id     VARCHAR(50),
+  url         VARCHAR(2000),
+  title       VARCHAR(250),
+  description TEXT,
+  image_id    VARCHAR(50),
+  status      INTEGER,
+  created_at  TIMESTAMP,
+  updated_at  TIMESTAMP
+);
+
+INSERT INTO pinls_old
+  SELECT id, user_id, monl_id, url, title, description, image_id, status, created_at, updated_at
+  FROM pinls;
+
+DROP TABLE pinls;
+ALTER TABLE pinls_old RENAME TO pinls;
+
+CREATE INDEX IF NOT EXISTS ix_pinls_user ON pinls (user_id);
+CREATE INDEX IF NOT EXISTS ix_pinls_monl ON pinls (monl_id);
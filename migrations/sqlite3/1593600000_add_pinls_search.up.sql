@@ -0,0 +1,34 @@
+ALTER TABLE pinls ADD COLUMN content TEXT NOT NULL DEFAULT '';
+
+CREATE VIRTUAL TABLE IF NOT EXISTS pinls_fts USING fts5 (
+  pinl_id UNINDEXED,
+  title,
+  description,
+  url,
+  content
+);
+
+INSERT INTO pinls_fts (pinl_id, title, description, url, content)
+  SELECT id, COALESCE(title, ''), COALESCE(description, ''), COALESCE(url, ''), content
+  FROM pinls;
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_insert AFTER INSERT ON pinls
+BEGIN
+  INSERT INTO pinls_fts (pinl_id, title, description, url, content)
+    VALUES (new.id, COALESCE(new.title, ''), COALESCE(new.description, ''), COALESCE(new.url, ''), new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_update AFTER UPDATE ON pinls
+BEGIN
+  UPDATE pinls_fts
+    SET title = COALESCE(new.title, ''),
+        description = COALESCE(new.description, ''),
+        url = COALESCE(new.url, ''),
+        content = new.content
+    WHERE pinl_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_delete AFTER DELETE ON pinls
+BEGIN
+  DELETE FROM pinls_fts WHERE pinl_id = old.id;
+END;
@@ -0,0 +1,26 @@
+DROP INDEX IF EXISTS ix_shares_user;
+DROP INDEX IF EXISTS ix_shares_slug;
+
+CREATE TABLE IF NOT EXISTS shares_old (
+  id          VARCHAR(50) PRIMARY KEY,
+  user_id     VARCHAR(50),
+  slug        VARCHAR(250),
+  name        VARCHAR(250),
+  description TEXT,
+  image_id    VARCHAR(50),
+  status      INTEGER,
+  created_at  TIMESTAMP,
+  updated_at  TIMESTAMP
+);
+
+INSERT INTO shares_old
+  SELECT id, user_id, slug, name, description, image_id, status, created_at, updated_at
+  FROM shares;
+
+DROP TABLE shares;
+ALTER TABLE shares_old RENAME TO shares;
+
+CREATE INDEX IF NOT EXISTS ix_shares_user ON shares (user_id);
+CREATE INDEX IF NOT EXISTS ix_shares_slug ON shares (slug);
+
+DROP TABLE IF EXISTS presets;
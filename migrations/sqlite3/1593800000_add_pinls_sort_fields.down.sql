@@ -0,0 +1,68 @@
+DROP INDEX IF EXISTS ix_taggables_tag;
+DROP INDEX IF EXISTS ix_taggables_target;
+
+CREATE TABLE IF NOT EXISTS taggables_old (
+  id          VARCHAR(50) PRIMARY KEY,
+  tag_id      VARCHAR(50),
+  target_id   VARCHAR(50),
+  target_name VARCHAR(100)
+);
+
+INSERT INTO taggables_old
+  SELECT id, tag_id, target_id, target_name
+  FROM taggables;
+
+DROP TABLE taggables;
+ALTER TABLE taggables_old RENAME TO taggables;
+
+CREATE INDEX IF NOT EXISTS ix_taggables_tag ON taggables (tag_id);
+CREATE INDEX IF NOT EXISTS ix_taggables_target ON taggables (target_id, target_name);
+
+DROP INDEX IF EXISTS ix_pinls_host;
+DROP INDEX IF EXISTS ix_pinls_user;
+DROP INDEX IF EXISTS ix_pinls_monl;
+
+CREATE TABLE IF NOT EXISTS pinls_old (
+  id          VARCHAR(50) PRIMARY KEY,
+  user_id     VARCHAR(50),
+  monl_id     VARCHAR(50),
+  url         VARCHAR(2000),
+  title       VARCHAR(250),
+  description TEXT,
+  image_id    VARCHAR(50),
+  status      INTEGER,
+  created_at  TIMESTAMP,
+  updated_at  TIMESTAMP,
+  content     TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO pinls_old
+  SELECT id, user_id, monl_id, url, title, description, image_id, status, created_at, updated_at, content
+  FROM pinls;
+
+DROP TABLE pinls;
+ALTER TABLE pinls_old RENAME TO pinls;
+
+CREATE INDEX IF NOT EXISTS ix_pinls_user ON pinls (user_id);
+CREATE INDEX IF NOT EXISTS ix_pinls_monl ON pinls (monl_id);
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_insert AFTER INSERT ON pinls
+BEGIN
+  INSERT INTO pinls_fts (pinl_id, title, description, url, content)
+    VALUES (new.id, COALESCE(new.title, ''), COALESCE(new.description, ''), COALESCE(new.url, ''), new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_update AFTER UPDATE ON pinls
+BEGIN
+  UPDATE pinls_fts
+    SET title = COALESCE(new.title, ''),
+        description = COALESCE(new.description, ''),
+        url = COALESCE(new.url, ''),
+        content = new.content
+    WHERE pinl_id = old.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS tr_pinls_fts_delete AFTER DELETE ON pinls
+BEGIN
+  DELETE FROM pinls_fts WHERE pinl_id = old.id;
+END;
@@ -0,0 +1,13 @@
+ALTER TABLE pinls ADD COLUMN host VARCHAR(250) NOT NULL DEFAULT '';
+
+UPDATE pinls SET host = substr(url, instr(url, '://') + 3) WHERE instr(url, '://') > 0;
+UPDATE pinls SET host = substr(host, 1, instr(host, '/') - 1) WHERE instr(host, '/') > 0;
+UPDATE pinls SET host = substr(host, 1, instr(host, '?') - 1) WHERE instr(host, '?') > 0;
+UPDATE pinls SET host = substr(host, 1, instr(host, '#') - 1) WHERE instr(host, '#') > 0;
+UPDATE pinls SET host = substr(host, instr(host, '@') + 1) WHERE instr(host, '@') > 0;
+UPDATE pinls SET host = substr(host, 1, instr(host, ':') - 1) WHERE instr(host, ':') > 0;
+UPDATE pinls SET host = lower(host);
+
+CREATE INDEX IF NOT EXISTS ix_pinls_host ON pinls (host);
+
+ALTER TABLE taggables ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
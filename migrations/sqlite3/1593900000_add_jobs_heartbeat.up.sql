@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN heartbeat_at TIMESTAMP;
@@ -0,0 +1 @@
+-- Release stats are merged into tag stats and not restored.
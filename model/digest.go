@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+// Digest sends the new releases of the user's pinls by email
+// periodically.
+type Digest struct {
+	ID         string          `json:"id"`
+	UserID     string          `json:"userId"`
+	Email      string          `json:"email"`
+	Frequency  DigestFrequency `json:"frequency"`
+	Enabled    bool            `json:"enabled"`
+	LastSentAt field.Time      `json:"lastSentAt"`
+	CreatedAt  field.Time      `json:"createdAt"`
+	UpdatedAt  field.Time      `json:"updatedAt"`
+}
+
+func (d Digest) MorphKey() string  { return d.ID }
+func (d Digest) MorphName() string { return "digest" }
+
+// Validate checks the email and frequency of the digest.
+func (d Digest) Validate() error {
+	if _, err := mail.ParseAddress(d.Email); err != nil {
+		return errors.New("email is invalid")
+	}
+	if d.Frequency.Period() == 0 {
+		return errors.New("frequency must be daily or weekly")
+	}
+	return nil
+}
+
+// Since reports the watermark of the next digest, which is the
+// last sent time or the creation time for the first digest.
+func (d Digest) Since() time.Time {
+	if t := d.LastSentAt.Time(); !t.IsZero() {
+		return t
+	}
+	return d.CreatedAt.Time()
+}
+
+// IsDue reports whether the digest should be sent at now.
+func (d Digest) IsDue(now time.Time) bool {
+	if !d.Enabled {
+		return false
+	}
+	return !d.Since().Add(d.Frequency.Period()).After(now)
+}
+
+type DigestFrequency string
+
+const (
+	DailyDigest  = DigestFrequency("daily")
+	WeeklyDigest = DigestFrequency("weekly")
+)
+
+// Period reports the interval between digests.
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DailyDigest:
+		return 24 * time.Hour
+	case WeeklyDigest:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+type DigestList []*Digest
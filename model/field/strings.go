@@ -0,0 +1,61 @@
+package field
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList stores string slice as JSON array.
+type StringList []string
+
+// Scan implements sql.Scanner interface.
+func (sl *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*sl = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("field: cannot scan %T into StringList", value)
+	}
+	if len(data) == 0 {
+		*sl = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(sl))
+}
+
+// Value implements driver.Valuer interface.
+func (sl StringList) Value() (driver.Value, error) {
+	if len(sl) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal([]string(sl))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (sl StringList) MarshalJSON() ([]byte, error) {
+	if sl == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(sl))
+}
+
+// Contains reports whether s is in the list.
+func (sl StringList) Contains(s string) bool {
+	for _, v := range sl {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
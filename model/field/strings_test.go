@@ -0,0 +1,67 @@
+package field
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringListScan(t *testing.T) {
+	tests := []struct {
+		v     interface{}
+		wants StringList
+	}{
+		{
+			v:     `["a","b/c"]`,
+			wants: StringList{"a", "b/c"},
+		},
+		{
+			v:     []byte(`["a"]`),
+			wants: StringList{"a"},
+		},
+		{
+			v:     "",
+			wants: nil,
+		},
+		{
+			v:     nil,
+			wants: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var sl StringList
+		err := sl.Scan(test.v)
+		if err != nil {
+			t.Errorf("scan: error(%s)", err)
+		}
+		if !reflect.DeepEqual(sl, test.wants) {
+			t.Errorf("scan: expects %v, got %v", test.wants, sl)
+		}
+	}
+}
+
+func TestStringListValue(t *testing.T) {
+	tests := []struct {
+		v     StringList
+		wants string
+	}{
+		{
+			v:     StringList{"a", "b,c"},
+			wants: `["a","b,c"]`,
+		},
+		{
+			v:     nil,
+			wants: "",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.v.Value()
+		if err != nil {
+			t.Errorf("value: error(%s)", err)
+		}
+		if got != test.wants {
+			t.Errorf("value: expects %q, got %q", test.wants, got)
+		}
+	}
+}
@@ -1,17 +1,33 @@
 package model
 
-import "github.com/pinmonl/pinmonl/model/field"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pinmonl/pinmonl/model/field"
+)
 
 type Job struct {
 	ID         string     `json:"id"`
 	Name       string     `json:"name"`
 	Describe   string     `json:"describe"`
+	Key        string     `json:"key"`
+	Payload    string     `json:"-"`
 	TargetID   string     `json:"targetId"`
 	TargetName string     `json:"targetName"`
 	Status     JobStatus  `json:"status"`
 	Message    string     `json:"message"`
+	Attempts   int        `json:"attempts"`
+	RunAt      field.Time `json:"runAt"`
 	CreatedAt  field.Time `json:"createdAt"`
+	StartedAt  field.Time `json:"startedAt"`
 	EndedAt    field.Time `json:"endedAt"`
+
+	// HeartbeatAt is updated periodically while the job is running.
+	HeartbeatAt field.Time `json:"heartbeatAt"`
+
+	// Duration is the running time in milliseconds.
+	Duration int64 `json:"duration"`
 }
 
 type JobStatus int
@@ -20,6 +36,39 @@ const (
 	JobInProgress JobStatus = iota
 	JobCompleted
 	JobFailed
+	JobQueued
+	// JobDead is the job which fails after all attempts.
+	JobDead
 )
 
+var jobStatusNames = map[JobStatus]string{
+	JobInProgress: "in_progress",
+	JobCompleted:  "completed",
+	JobFailed:     "failed",
+	JobQueued:     "queued",
+	JobDead:       "dead",
+}
+
+func (js JobStatus) String() string {
+	if name, ok := jobStatusNames[js]; ok {
+		return name
+	}
+	return strconv.Itoa(int(js))
+}
+
+// ParseJobStatus parses status from its name or number.
+func ParseJobStatus(s string) (JobStatus, error) {
+	for js, name := range jobStatusNames {
+		if name == s {
+			return js, nil
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := jobStatusNames[JobStatus(n)]; ok {
+			return JobStatus(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid job status %q", s)
+}
+
 type JobList []*Job
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+// NotifyRule posts webhook to URL when new release of the
+// matched pinls is found.
+type NotifyRule struct {
+	ID                string           `json:"id"`
+	UserID            string           `json:"userId"`
+	Name              string           `json:"name"`
+	URL               string           `json:"url"`
+	Secret            string           `json:"secret"`
+	TagNames          field.StringList `json:"tags"`
+	PinlIDs           field.StringList `json:"pinlIds"`
+	PkgIDs            field.StringList `json:"pkgIds"`
+	Kinds             field.StringList `json:"kinds"`
+	Constraint        string           `json:"constraint"`
+	Level             ReleaseLevel     `json:"level"`
+	ExcludePrerelease bool             `json:"excludePrerelease"`
+	Enabled           bool             `json:"enabled"`
+	CreatedAt         field.Time       `json:"createdAt"`
+	UpdatedAt         field.Time       `json:"updatedAt"`
+}
+
+// ReleaseLevel filters the release by the semantic version part.
+type ReleaseLevel string
+
+const (
+	AnyRelease   = ReleaseLevel("")
+	MajorRelease = ReleaseLevel("major")
+	MinorRelease = ReleaseLevel("minor")
+)
+
+// Validate checks the url, level and constraint of the rule.
+func (r NotifyRule) Validate() error {
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be http or https")
+	}
+	switch r.Level {
+	case AnyRelease, MajorRelease, MinorRelease:
+	default:
+		return errors.New("level must be major or minor")
+	}
+	for _, k := range r.Kinds {
+		if !IsReleaseStatKind(StatKind(k)) {
+			return errors.New("kind must be release kind")
+		}
+	}
+	if r.Constraint != "" {
+		if _, err := semver.NewConstraint(r.Constraint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchStat reports whether the release passes the kind and
+// version filters of the rule. Release which is not semantic
+// version only matches when there is no version filter.
+func (r NotifyRule) MatchStat(stat *Stat) bool {
+	if !IsReleaseStatKind(stat.Kind) {
+		return false
+	}
+	if len(r.Kinds) > 0 && !r.Kinds.Contains(string(stat.Kind)) {
+		return false
+	}
+	if r.Constraint == "" && r.Level == AnyRelease && !r.ExcludePrerelease {
+		return true
+	}
+
+	v, err := semver.NewVersion(stat.Value)
+	if err != nil {
+		return false
+	}
+	if r.ExcludePrerelease && v.Prerelease() != "" {
+		return false
+	}
+	switch r.Level {
+	case MajorRelease:
+		if v.Minor() != 0 || v.Patch() != 0 {
+			return false
+		}
+	case MinorRelease:
+		if v.Patch() != 0 {
+			return false
+		}
+	}
+	if r.Constraint != "" {
+		c, err := semver.NewConstraint(r.Constraint)
+		if err != nil || !c.Check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+type NotifyRuleList []*NotifyRule
+
+func (nl NotifyRuleList) Keys() []string {
+	keys := make([]string, len(nl))
+	for i := range nl {
+		keys[i] = nl[i].ID
+	}
+	return keys
+}
+
+// NotifyDelivery records the webhook request of a rule.
+type NotifyDelivery struct {
+	ID           string         `json:"id"`
+	RuleID       string         `json:"ruleId"`
+	StatID       string         `json:"statId"`
+	Event        string         `json:"event"`
+	Payload      string         `json:"payload"`
+	Status       DeliveryStatus `json:"status"`
+	Attempts     int            `json:"attempts"`
+	ResponseCode int            `json:"responseCode"`
+	Message      string         `json:"message"`
+	CreatedAt    field.Time     `json:"createdAt"`
+	DeliveredAt  field.Time     `json:"deliveredAt"`
+}
+
+func (n NotifyDelivery) MorphKey() string  { return n.ID }
+func (n NotifyDelivery) MorphName() string { return "notify_delivery" }
+
+type DeliveryStatus int
+
+const (
+	DeliveryPending DeliveryStatus = iota
+	DeliveryDelivered
+	DeliveryFailed
+)
+
+type NotifyDeliveryList []*NotifyDelivery
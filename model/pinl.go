@@ -14,6 +14,13 @@ type Pinl struct {
 	CreatedAt   field.Time `json:"createdAt"`
 	UpdatedAt   field.Time `json:"updatedAt"`
 
+	// Snippet is the highlighted text of search result.
+	Snippet string `json:"snippet,omitempty"`
+
+	// Cursor is the position in the sorted list, which is used to
+	// list the next page.
+	Cursor string `json:"-"`
+
 	Tags     *TagList  `json:"-"`
 	TagNames *[]string `json:"tags,omitempty"`
 	Pkgs     *PkgList  `json:"pkgs,omitempty"`
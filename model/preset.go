@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+// Preset is the saved search of pinls, which can be used by the
+// listing and as the selection of share.
+type Preset struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"userId"`
+	Name      string           `json:"name"`
+	Query     string           `json:"query"`
+	TagNames  field.StringList `json:"tags"`
+	Sort      string           `json:"sort"`
+	CreatedAt field.Time       `json:"createdAt"`
+	UpdatedAt field.Time       `json:"updatedAt"`
+}
+
+func (p Preset) MorphKey() string  { return p.ID }
+func (p Preset) MorphName() string { return "preset" }
+
+// Validate checks the name of the preset.
+func (p Preset) Validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type PresetList []*Preset
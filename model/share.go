@@ -12,6 +12,7 @@ type Share struct {
 	Description string     `json:"description"`
 	ImageID     string     `json:"imageId"`
 	Status      Status     `json:"status"`
+	PresetID    string     `json:"presetId"`
 	CreatedAt   field.Time `json:"createdAt"`
 	UpdatedAt   field.Time `json:"updatedAt"`
 
@@ -1,6 +1,10 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
 	"github.com/Masterminds/semver/v3"
 	"github.com/pinmonl/pinmonl/model/field"
 )
@@ -18,6 +22,9 @@ type Stat struct {
 	Weight      int           `json:"weight"`
 	IsLatest    bool          `json:"isLatest"`
 	HasChildren bool          `json:"hasChildren"`
+	Body        string        `json:"body"`
+	Assets      StatAssetList `json:"assets"`
+	CreatedAt   field.Time    `json:"createdAt"`
 
 	Substats *StatList `json:"substats,omitempty"`
 }
@@ -30,6 +37,7 @@ type StatKind string
 const (
 	AnyStat             = StatKind("")
 	AliasStat           = StatKind("alias")
+	AppVersionStat      = StatKind("app_version")
 	ChannelStat         = StatKind("channel")
 	DownloadCountStat   = StatKind("download_count")
 	FileCountStat       = StatKind("file_count")
@@ -37,6 +45,7 @@ const (
 	FundingStat         = StatKind("funding")
 	LangStat            = StatKind("lang")
 	LicenseStat         = StatKind("license")
+	LinkStat            = StatKind("link")
 	ManifestStat        = StatKind("manifest")
 	OpenIssueCountStat  = StatKind("open_issue_count")
 	PullCountStat       = StatKind("pull_count")
@@ -180,7 +189,12 @@ func (sl StatBySemver) Less(i, j int) bool {
 		// If error occurs, sort to top.
 		return false
 	}
-	return iv.Compare(ij) < 0
+	if c := iv.Compare(ij); c != 0 {
+		return c < 0
+	}
+	// Versions differ in build metadata only, e.g. "+incompatible" of
+	// go module.
+	return sl[i].Value < sl[j].Value
 }
 
 type StatByRecordedAt StatList
@@ -194,3 +208,56 @@ func (sl StatByRecordedAt) Less(i, j int) bool {
 	jr := sl[j].RecordedAt.Time()
 	return ir.Before(jr)
 }
+
+// StatAsset is the downloadable file attached to a release.
+type StatAsset struct {
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	ContentType   string `json:"contentType"`
+	Size          int64  `json:"size"`
+	DownloadCount int64  `json:"downloadCount"`
+}
+
+// StatAssetList stores assets as JSON array.
+type StatAssetList []*StatAsset
+
+// Scan implements sql.Scanner interface.
+func (al *StatAssetList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*al = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("model: cannot scan %T into StatAssetList", value)
+	}
+	if len(data) == 0 {
+		*al = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*[]*StatAsset)(al))
+}
+
+// Value implements driver.Valuer interface.
+func (al StatAssetList) Value() (driver.Value, error) {
+	if len(al) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal([]*StatAsset(al))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (al StatAssetList) MarshalJSON() ([]byte, error) {
+	if al == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]*StatAsset(al))
+}
@@ -5,6 +5,7 @@ type Taggable struct {
 	TagID      string `json:"tagId"`
 	TargetID   string `json:"targetId"`
 	TargetName string `json:"targetName"`
+	Position   int    `json:"position"`
 
 	Tag  *Tag  `json:"tag,omitempty"`
 	Pinl *Pinl `json:"pinl,omitempty"`
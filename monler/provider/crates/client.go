@@ -0,0 +1,77 @@
+package crates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// Crates api settings.
+var (
+	DefaultRegistryURL = "https://crates.io"
+	// UserAgent is required by the crates.io crawler policy.
+	UserAgent = "pinmonl (https://github.com/pinmonl/pinmonl)"
+)
+
+type Client struct {
+	client      *http.Client
+	registryURL string
+}
+
+func (c *Client) get(dest string, out interface{}) error {
+	req, err := http.NewRequest("GET", dest, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("crates: api response got %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) Crate(name string) (*CrateResponse, error) {
+	dest := c.registryURL + "/api/v1/crates/" + name
+
+	var out CrateResponse
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type CrateResponse struct {
+	Crate    *Crate     `json:"crate"`
+	Versions []*Version `json:"versions"`
+}
+
+type Crate struct {
+	Name            string `json:"name"`
+	Downloads       uint64 `json:"downloads"`
+	RecentDownloads uint64 `json:"recent_downloads"`
+	MaxVersion      string `json:"max_version"`
+	Homepage        string `json:"homepage"`
+	Repository      string `json:"repository"`
+}
+
+type Version struct {
+	Num       string    `json:"num"`
+	CreatedAt time.Time `json:"created_at"`
+	Downloads uint64    `json:"downloads"`
+	Yanked    bool      `json:"yanked"`
+	License   string    `json:"license"`
+	Checksum  string    `json:"checksum"`
+}
@@ -0,0 +1,159 @@
+package crates
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+type Provider struct {
+	client *Client
+}
+
+func NewProvider() (*Provider, error) {
+	return newProvider(DefaultRegistryURL), nil
+}
+
+func newProvider(registryURL string) *Provider {
+	return &Provider{
+		client: &Client{
+			client:      &http.Client{},
+			registryURL: registryURL,
+		},
+	}
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.CratesProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseCrates(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseCrates(rawurl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.client.Crate(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu        *pkguri.PkgURI
+	client    *Client
+	lastCrate *CrateResponse
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	res, err := r.client.Crate(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("crates:", err)
+		return nil, err
+	}
+
+	now := field.Now()
+	stats := []*model.Stat{
+		{
+			Kind:       model.DownloadCountStat,
+			Value:      strconv.FormatUint(res.Crate.Downloads, 10),
+			IsLatest:   true,
+			RecordedAt: now,
+		},
+	}
+
+	tags := make([]*model.Stat, 0, len(res.Versions))
+	for _, version := range res.Versions {
+		if version.Yanked {
+			continue
+		}
+		isLatest := version.Num == res.Crate.MaxVersion
+		if isLatest && version.License != "" {
+			stats = append(stats, &model.Stat{
+				Kind:       model.LicenseStat,
+				Value:      strings.ToLower(version.License),
+				IsLatest:   true,
+				RecordedAt: now,
+			})
+		}
+
+		tags = append(tags, &model.Stat{
+			Kind:       model.TagStat,
+			Value:      version.Num,
+			Checksum:   version.Checksum,
+			RecordedAt: field.Time(version.CreatedAt),
+			IsLatest:   isLatest,
+		})
+	}
+	sort.Sort(model.StatBySemver(tags))
+
+	r.lastCrate = res
+	return newReport(r.pu, stats, tags)
+}
+
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastCrate == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	repository := strings.TrimSuffix(strings.TrimSuffix(r.lastCrate.Crate.Repository, "/"), ".git")
+	if repository == "" {
+		return []string{}, nil
+	}
+	return []string{repository}, nil
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
@@ -0,0 +1,86 @@
+package crates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != UserAgent {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/crates/sample":
+			fmt.Fprint(w, `{
+  "crate": {"name": "sample", "downloads": 1200, "max_version": "1.1.0", "repository": "https://github.com/owner/sample.git"},
+  "versions": [
+    {"num": "1.1.0", "created_at": "2020-03-01T10:00:00.000000+00:00", "yanked": false, "license": "MIT OR Apache-2.0", "checksum": "c110"},
+    {"num": "1.0.1", "created_at": "2020-02-01T10:00:00.000000+00:00", "yanked": true, "license": "MIT", "checksum": "c101"},
+    {"num": "1.0.0", "created_at": "2020-01-01T10:00:00.000000+00:00", "yanked": false, "license": "MIT", "checksum": "c100"}
+  ]
+}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	assert.Nil(t, p.Ping("https://crates.io/crates/sample"))
+	assert.NotNil(t, p.Ping("https://crates.io/crates/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/owner/sample"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	repo, err := p.Open("https://crates.io/crates/sample")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+	}
+	assert.Equal(t, map[model.StatKind]string{
+		model.DownloadCountStat: "1200",
+		model.LicenseStat:       "mit or apache-2.0",
+	}, values)
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags = append(tags, tag)
+	}
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "1.0.0", tags[0].Value)
+		assert.Equal(t, "c100", tags[0].Checksum)
+		assert.False(t, tags[0].IsLatest)
+		assert.Equal(t, "1.1.0", tags[1].Value)
+		assert.True(t, tags[1].IsLatest)
+		assert.Equal(t, 2020, tags[1].RecordedAt.Time().Year())
+	}
+
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/owner/sample"}, derived)
+}
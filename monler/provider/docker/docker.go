@@ -3,7 +3,6 @@ package docker
 import (
 	"fmt"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -141,64 +140,11 @@ func newReport(client *Client, pu *pkguri.PkgURI) (*Report, error) {
 	if err != nil {
 		return nil, err
 	}
-	bucket, err := newTagBucket(rawtags)
+	tags, err := GroupTags(rawtags)
 	if err != nil {
 		return nil, err
 	}
 
-	tags := make([]*model.Stat, 0)
-	for _, tag := range bucket.semvers {
-		if tag == bucket.latest {
-			tag.IsLatest = true
-		}
-
-		substats := *tag.Substats
-		for _, child := range bucket.children[tag] {
-			substats = append(substats, &model.Stat{
-				Kind:  model.AliasStat,
-				Name:  tag.Value,
-				Value: child.Value,
-			})
-		}
-		tag.Substats = &substats
-
-		delete(bucket.children, tag)
-		tags = append(tags, tag)
-	}
-	for _, tag := range bucket.channels {
-		tag.IsLatest = tag.Value == "latest"
-		tag.Kind = model.ChannelStat
-
-		substats := *tag.Substats
-		for _, child := range bucket.children[tag] {
-			substats = append(substats, &model.Stat{
-				Kind:  model.AliasStat,
-				Name:  tag.Value,
-				Value: child.Value,
-			})
-		}
-		tag.Substats = &substats
-
-		delete(bucket.children, tag)
-		tags = append(tags, tag)
-	}
-	for tag, children := range bucket.children {
-		tag.Kind = model.AliasStat
-		substats := *tag.Substats
-		for _, child := range children {
-			substats = append(substats, &model.Stat{
-				Kind:  model.AliasStat,
-				Name:  tag.Value,
-				Value: child.Value,
-			})
-		}
-		tag.Substats = &substats
-
-		tags = append(tags, tag)
-	}
-
-	sort.Sort(TagBySemver(tags))
-
 	report := prvdutils.NewStaticReport(pu, stats, tags)
 	return &Report{report}, nil
 }
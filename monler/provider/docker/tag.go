@@ -21,6 +21,76 @@ var (
 
 var tagDigestStdout = ioutil.Discard
 
+// Errors.
+var (
+	ErrNoLatestTag = errors.New("docker: do not have latest tag")
+)
+
+// GroupTags groups the tags sharing manifest digests. The semver tags
+// and channels are reported with their aliases in substats, and the
+// rest of tags which alias others become AliasStat. Manifests of tag
+// are read from the ManifestStat substats.
+func GroupTags(rawtags []*model.Stat) ([]*model.Stat, error) {
+	bucket, err := newTagBucket(rawtags)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*model.Stat, 0)
+	for _, tag := range bucket.semvers {
+		if tag == bucket.latest {
+			tag.IsLatest = true
+		}
+
+		substats := *tag.Substats
+		for _, child := range bucket.children[tag] {
+			substats = append(substats, &model.Stat{
+				Kind:  model.AliasStat,
+				Name:  tag.Value,
+				Value: child.Value,
+			})
+		}
+		tag.Substats = &substats
+
+		delete(bucket.children, tag)
+		tags = append(tags, tag)
+	}
+	for _, tag := range bucket.channels {
+		tag.IsLatest = tag.Value == "latest"
+		tag.Kind = model.ChannelStat
+
+		substats := *tag.Substats
+		for _, child := range bucket.children[tag] {
+			substats = append(substats, &model.Stat{
+				Kind:  model.AliasStat,
+				Name:  tag.Value,
+				Value: child.Value,
+			})
+		}
+		tag.Substats = &substats
+
+		delete(bucket.children, tag)
+		tags = append(tags, tag)
+	}
+	for tag, children := range bucket.children {
+		tag.Kind = model.AliasStat
+		substats := *tag.Substats
+		for _, child := range children {
+			substats = append(substats, &model.Stat{
+				Kind:  model.AliasStat,
+				Name:  tag.Value,
+				Value: child.Value,
+			})
+		}
+		tag.Substats = &substats
+
+		tags = append(tags, tag)
+	}
+
+	sort.Sort(TagBySemver(tags))
+	return tags, nil
+}
+
 // tagBucket stores different kinds of tags.
 type tagBucket struct {
 	latest   *model.Stat
@@ -40,7 +110,7 @@ func newTagBucket(tags []*model.Stat) (*tagBucket, error) {
 	if got := model.StatList(tags).GetValue("latest"); len(got) > 0 {
 		latest = got[0]
 	} else {
-		return nil, errors.New("docker: do not have latest tag")
+		return nil, ErrNoLatestTag
 	}
 	// Prepare for latest tag comparsion.
 	for _, digest := range digestsFromTag(latest) {
@@ -68,7 +138,12 @@ func newTagBucket(tags []*model.Stat) (*tagBucket, error) {
 	for _, tag := range tags {
 		if tver, err := semver.NewVersion(tag.Value); err == nil {
 			isLatest := (tagCompare(tag, latest) == tagIsIdentical)
-			if isLatest && tver.GreaterThan(latestVer) {
+			// Strict semver wins the same version, e.g. "1.1.0" over "1.1",
+			// as it is the one kept in semvers.
+			isStricter := latestTag != nil && tver.Equal(latestVer) &&
+				StrictSemverPattern.MatchString(tag.Value) &&
+				!StrictSemverPattern.MatchString(latestTag.Value)
+			if isLatest && (tver.GreaterThan(latestVer) || isStricter) {
 				latestTag = tag
 				latestVer = tver
 			}
@@ -0,0 +1,150 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Credential authenticates the remotes of host. Host may include port
+// to match the remotes of the port only. Password, token and
+// passphrase are not accepted in plaintext, they are read from the
+// environment if starting with "$", e.g. "$GITEA_TOKEN", or from the
+// file if starting with "file:", e.g. "file:/run/secrets/gitea_token".
+type Credential struct {
+	Host          string
+	Username      string
+	Password      string
+	Token         string
+	SSHKey        string
+	SSHPassphrase string
+}
+
+var (
+	credentials   []*Credential
+	credentialsMu = &sync.RWMutex{}
+)
+
+// ErrPlaintextSecret is returned if the secret of credential is not
+// referred from the environment or file.
+var ErrPlaintextSecret = errors.New("git: secret in plaintext, use $ENV or file:path")
+
+// SetCredentials replaces the credentials of remotes. The credentials
+// with plaintext secrets are rejected.
+func SetCredentials(creds []*Credential) error {
+	for _, cred := range creds {
+		for _, value := range []string{cred.Password, cred.Token, cred.SSHPassphrase} {
+			if value != "" && !isSecretRef(value) {
+				return fmt.Errorf("%w: host %s", ErrPlaintextSecret, cred.Host)
+			}
+		}
+	}
+
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	credentials = creds
+	return nil
+}
+
+func findCredential(ep *transport.Endpoint) *Credential {
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+
+	hostPort := fmt.Sprintf("%s:%d", ep.Host, ep.Port)
+	var found *Credential
+	for _, cred := range credentials {
+		switch cred.Host {
+		case hostPort:
+			return cred
+		case ep.Host:
+			found = cred
+		}
+	}
+	return found
+}
+
+// authMethod resolves the credential of remote by host. Nil is
+// returned for anonymous access.
+func authMethod(gitURL string) (transport.AuthMethod, error) {
+	ep, err := transport.NewEndpoint(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	cred := findCredential(ep)
+	if cred == nil {
+		return nil, nil
+	}
+
+	switch ep.Protocol {
+	case "ssh":
+		if cred.SSHKey == "" {
+			return nil, nil
+		}
+		user := cred.Username
+		if user == "" {
+			user = ep.User
+		}
+		if user == "" {
+			user = "git"
+		}
+		passphrase, err := secret(cred.SSHPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		auth, err := ssh.NewPublicKeysFromFile(user, cred.SSHKey, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return auth, nil
+	case "http", "https":
+		token, err := secret(cred.Token)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			user := cred.Username
+			if user == "" {
+				user = "token"
+			}
+			return &http.BasicAuth{Username: user, Password: token}, nil
+		}
+		password, err := secret(cred.Password)
+		if err != nil {
+			return nil, err
+		}
+		if cred.Username != "" && password != "" {
+			return &http.BasicAuth{Username: cred.Username, Password: password}, nil
+		}
+	}
+	return nil, nil
+}
+
+const secretFilePrefix = "file:"
+
+func isSecretRef(value string) bool {
+	return strings.HasPrefix(value, "$") || strings.HasPrefix(value, secretFilePrefix)
+}
+
+// secret reads the value referred from the environment or file.
+func secret(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", nil
+	case strings.HasPrefix(value, "$"):
+		return os.Getenv(strings.TrimPrefix(value, "$")), nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		b, err := ioutil.ReadFile(strings.TrimPrefix(value, secretFilePrefix))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	return "", ErrPlaintextSecret
+}
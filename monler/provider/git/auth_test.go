@@ -0,0 +1,89 @@
+package git
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMethod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monler-git-auth")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	keyPath := filepath.Join(dir, "id_rsa")
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0600)
+	assert.Nil(t, err)
+
+	os.Setenv("MONLER_GIT_TEST_TOKEN", "env-token")
+	defer os.Unsetenv("MONLER_GIT_TEST_TOKEN")
+	passwordPath := filepath.Join(dir, "password")
+	err = ioutil.WriteFile(passwordPath, []byte("secret\n"), 0600)
+	assert.Nil(t, err)
+
+	err = SetCredentials([]*Credential{
+		{Host: "gitea.example.com", Token: "$MONLER_GIT_TEST_TOKEN"},
+		{Host: "gitea.example.com:3000", Username: "user", Password: "file:" + passwordPath},
+		{Host: "gitlab.example.com", Username: "deploy", SSHKey: keyPath},
+	})
+	assert.Nil(t, err)
+	defer SetCredentials(nil)
+
+	tests := []struct {
+		url  string
+		want transport.AuthMethod
+	}{
+		{
+			url:  "https://gitea.example.com/group/repo",
+			want: &http.BasicAuth{Username: "token", Password: "env-token"},
+		},
+		{
+			url:  "http://gitea.example.com:3000/group/repo",
+			want: &http.BasicAuth{Username: "user", Password: "secret"},
+		},
+		{
+			url:  "https://github.com/group/repo",
+			want: nil,
+		},
+		{
+			url:  "https://gitlab.example.com/group/repo",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		got, err := authMethod(test.url)
+		assert.Nil(t, err)
+		assert.Equal(t, test.want, got, test.url)
+	}
+
+	got, err := authMethod("ssh://git@gitlab.example.com/group/repo.git")
+	assert.Nil(t, err)
+	if assert.IsType(t, &ssh.PublicKeys{}, got) {
+		assert.Equal(t, "deploy", got.(*ssh.PublicKeys).User)
+	}
+
+	// Plaintext is rejected.
+	err = SetCredentials([]*Credential{
+		{Host: "gitea.example.com", Username: "user", Password: "secret"},
+	})
+	assert.True(t, errors.Is(err, ErrPlaintextSecret))
+	got, err = authMethod("https://gitea.example.com/group/repo")
+	assert.Nil(t, err)
+	assert.Equal(t, &http.BasicAuth{Username: "token", Password: "env-token"}, got)
+}
@@ -0,0 +1,249 @@
+package git
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// Cache keeps bare clones of repositories in a directory, so that
+// the later analysis fetches the new tags only. The least recently
+// used clones are removed once the total size exceeds MaxSize.
+type Cache struct {
+	Dir     string
+	MaxSize int64
+
+	mu    *sync.Mutex
+	locks map[string]*cacheLock
+}
+
+type cacheLock struct {
+	*sync.Mutex
+	refs int
+}
+
+// NewCache creates the cache in dir. Zero maxSize disables eviction.
+func NewCache(dir string, maxSize int64) *Cache {
+	return &Cache{
+		Dir:     dir,
+		MaxSize: maxSize,
+		mu:      &sync.Mutex{},
+		locks:   make(map[string]*cacheLock),
+	}
+}
+
+func (c *Cache) key(gitURL string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(gitURL)))
+}
+
+// Path returns the directory of clone.
+func (c *Cache) Path(gitURL string) string {
+	return filepath.Join(c.Dir, c.key(gitURL))
+}
+
+// lock blocks until the clone of key is not used by others.
+func (c *Cache) lock(key string) func() {
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = &cacheLock{Mutex: &sync.Mutex{}}
+		c.locks[key] = l
+	}
+	l.refs++
+	c.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		c.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(c.locks, key)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// Open clones the repository if it is not cached, otherwise fetches
+// the tags from remote. The clone is locked until release is called,
+// it is neither opened by others nor evicted in the meantime.
+func (c *Cache) Open(gitURL string) (repo *git.Repository, release func(), err error) {
+	auth, err := authMethod(gitURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := c.key(gitURL)
+	unlock := c.lock(key)
+	defer func() {
+		if err != nil {
+			unlock()
+		}
+	}()
+	dir := filepath.Join(c.Dir, key)
+
+	cloned := false
+	repo, err = git.PlainOpen(dir)
+	switch err {
+	case nil:
+		err = repo.Fetch(&git.FetchOptions{
+			RefSpecs: []config.RefSpec{tagRefSpec},
+			Auth:     auth,
+			Progress: CloneProgress,
+		})
+		if err == git.NoErrAlreadyUpToDate {
+			err = nil
+		}
+		if err == nil || err == transport.ErrEmptyRemoteRepository {
+			err = pruneTags(repo, auth)
+		}
+	case git.ErrRepositoryNotExists:
+		cloned = true
+		repo, err = git.PlainClone(dir, true, &git.CloneOptions{
+			URL:      gitURL,
+			Auth:     auth,
+			Progress: CloneProgress,
+		})
+		if err != nil && err != transport.ErrEmptyRemoteRepository {
+			os.RemoveAll(dir)
+		}
+	}
+	if err != nil && err != transport.ErrEmptyRemoteRepository {
+		return nil, nil, err
+	}
+	err = nil
+
+	now := time.Now()
+	os.Chtimes(dir, now, now)
+
+	// The clone is locked so it will not be evicted by itself.
+	if cloned {
+		if err := c.Evict(); err != nil {
+			logrus.Debugf("git: cache evict err(%v)", err)
+		}
+	}
+
+	var once sync.Once
+	return repo, func() { once.Do(unlock) }, nil
+}
+
+const tagRefSpec = config.RefSpec("+refs/tags/*:refs/tags/*")
+
+// pruneTags removes the tags which are deleted from remote, as the
+// fetch of go-git does not prune.
+func pruneTags(repo *git.Repository, auth transport.AuthMethod) error {
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return err
+	}
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil && err != transport.ErrEmptyRemoteRepository {
+		return err
+	}
+	remoteTags := make(map[plumbing.ReferenceName]struct{})
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			remoteTags[ref.Name()] = struct{}{}
+		}
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return err
+	}
+	var pruned []plumbing.ReferenceName
+	tags.ForEach(func(ref *plumbing.Reference) error {
+		if _, ok := remoteTags[ref.Name()]; !ok {
+			pruned = append(pruned, ref.Name())
+		}
+		return nil
+	})
+	for _, name := range pruned {
+		if err := repo.Storer.RemoveReference(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type cacheEntry struct {
+	key     string
+	size    int64
+	modTime time.Time
+}
+
+// Evict removes the least recently used clones until the total size
+// fits MaxSize. The clones in use, which are opened and not released
+// yet, are skipped.
+func (c *Cache) Evict() error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+
+	infos, err := ioutil.ReadDir(c.Dir)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	entries := make([]*cacheEntry, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		size, err := dirSize(filepath.Join(c.Dir, info.Name()))
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &cacheEntry{
+			key:     info.Name(),
+			size:    size,
+			modTime: info.ModTime(),
+		})
+		total += size
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range entries {
+		if total <= c.MaxSize {
+			break
+		}
+		if _, using := c.locks[entry.key]; using {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.Dir, entry.key)); err != nil {
+			return err
+		}
+		total -= entry.size
+	}
+	return nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
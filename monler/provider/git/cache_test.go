@@ -0,0 +1,148 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRemote(t *testing.T, dir string) *git.Repository {
+	repo, err := git.PlainInit(dir, false)
+	assert.Nil(t, err)
+	addTestCommit(t, repo, dir, "v0.1.0")
+	return repo
+}
+
+func addTestCommit(t *testing.T, repo *git.Repository, dir, tag string) {
+	err := ioutil.WriteFile(filepath.Join(dir, "VERSION"), []byte(tag), 0644)
+	assert.Nil(t, err)
+	wt, err := repo.Worktree()
+	assert.Nil(t, err)
+	_, err = wt.Add("VERSION")
+	assert.Nil(t, err)
+	hash, err := wt.Commit(tag, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	assert.Nil(t, err)
+	_, err = repo.CreateTag(tag, hash, nil)
+	assert.Nil(t, err)
+}
+
+func countTags(t *testing.T, repo *git.Repository) int {
+	iter, err := repo.Tags()
+	assert.Nil(t, err)
+	n := 0
+	for _, err := iter.Next(); err == nil; _, err = iter.Next() {
+		n++
+	}
+	return n
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monler-git-cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	remoteDir := filepath.Join(dir, "remote1")
+	remote := newTestRemote(t, remoteDir)
+	cache := NewCache(filepath.Join(dir, "cache"), 0)
+
+	// Clone.
+	repo, release, err := cache.Open(remoteDir)
+	if !assert.Nil(t, err) {
+		return
+	}
+	release()
+	assert.Equal(t, 1, countTags(t, repo))
+	_, err = os.Stat(filepath.Join(cache.Path(remoteDir), "HEAD"))
+	assert.Nil(t, err)
+
+	// Fetch new tags.
+	addTestCommit(t, remote, remoteDir, "v0.2.0")
+	repo, release, err = cache.Open(remoteDir)
+	if !assert.Nil(t, err) {
+		return
+	}
+	release()
+	assert.Equal(t, 2, countTags(t, repo))
+
+	// Prune deleted tags.
+	assert.Nil(t, remote.DeleteTag("v0.1.0"))
+	repo, release, err = cache.Open(remoteDir)
+	if !assert.Nil(t, err) {
+		return
+	}
+	release()
+	assert.Equal(t, 1, countTags(t, repo))
+
+	// Evict least recently used.
+	remoteDir2 := filepath.Join(dir, "remote2")
+	newTestRemote(t, remoteDir2)
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(cache.Path(remoteDir), old, old)
+
+	cache.MaxSize = 1
+	_, release, err = cache.Open(remoteDir2)
+	assert.Nil(t, err)
+	_, err = os.Stat(cache.Path(remoteDir))
+	assert.True(t, os.IsNotExist(err))
+
+	// Skip the clone in use until released.
+	assert.Nil(t, cache.Evict())
+	_, err = os.Stat(cache.Path(remoteDir2))
+	assert.Nil(t, err)
+	release()
+	release()
+	assert.Empty(t, cache.locks)
+	assert.Nil(t, cache.Evict())
+	_, err = os.Stat(cache.Path(remoteDir2))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monler-git-cache")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	remoteDir := filepath.Join(dir, "remote")
+	newTestRemote(t, remoteDir)
+	cache := NewCache(filepath.Join(dir, "cache"), 0)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		using int
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo, release, err := cache.Open(remoteDir)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer release()
+
+			mu.Lock()
+			using++
+			assert.Equal(t, 1, using)
+			mu.Unlock()
+
+			assert.Equal(t, 1, countTags(t, repo))
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			using--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Empty(t, cache.locks)
+}
@@ -3,7 +3,9 @@ package git
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/pelletier/go-toml"
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/model/field"
 	"github.com/pinmonl/pinmonl/monler/provider"
@@ -57,10 +60,14 @@ func (p *Provider) open(rawurl string) (*Repo, error) {
 }
 
 func (p *Provider) Ping(rawurl string) error {
+	auth, err := authMethod(rawurl)
+	if err != nil {
+		return err
+	}
 	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		URLs: []string{rawurl},
 	})
-	_, err := remote.List(&git.ListOptions{})
+	_, err = remote.List(&git.ListOptions{Auth: auth})
 	if err != nil && err != transport.ErrEmptyRemoteRepository {
 		return err
 	}
@@ -70,6 +77,8 @@ func (p *Provider) Ping(rawurl string) error {
 type Repo struct {
 	gitURL  string
 	tempDir string
+	cached  bool
+	release func()
 	repo    *git.Repository
 }
 
@@ -78,6 +87,25 @@ func NewRepo(gitURL string) (*Repo, error) {
 }
 
 func newRepo(gitURL string) (*Repo, error) {
+	if CloneCache != nil {
+		repo, release, err := CloneCache.Open(gitURL)
+		if err != nil {
+			return nil, err
+		}
+		return &Repo{
+			gitURL:  gitURL,
+			tempDir: CloneCache.Path(gitURL),
+			cached:  true,
+			release: release,
+			repo:    repo,
+		}, nil
+	}
+
+	auth, err := authMethod(gitURL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Git clone to temp directory.
 	dir, err := getCloneDir(gitURL)
 	if err != nil {
@@ -85,6 +113,7 @@ func newRepo(gitURL string) (*Repo, error) {
 	}
 	repo, err := git.PlainClone(dir, false, &git.CloneOptions{
 		URL:        gitURL,
+		Auth:       auth,
 		NoCheckout: true,
 		Progress:   CloneProgress,
 	})
@@ -92,6 +121,7 @@ func newRepo(gitURL string) (*Repo, error) {
 		repo, err = git.PlainOpen(dir)
 		if err == nil {
 			repo.Fetch(&git.FetchOptions{
+				Auth:     auth,
 				Progress: CloneProgress,
 			})
 		}
@@ -167,11 +197,24 @@ func (r *Repo) Derived() ([]string, error) {
 	if npmUrls, err := r.GuessNpm(); err == nil {
 		derived = append(derived, npmUrls...)
 	}
+	if cratesUrls, err := r.GuessCrates(); err == nil {
+		derived = append(derived, cratesUrls...)
+	}
+	if gemUrls, err := r.GuessRubygems(); err == nil {
+		derived = append(derived, gemUrls...)
+	}
+	if composerUrls, err := r.GuessPackagist(); err == nil {
+		derived = append(derived, composerUrls...)
+	}
 
 	return derived, nil
 }
 
 func (r *Repo) Close() error {
+	if r.cached {
+		r.release()
+		return nil
+	}
 	if !IsDev {
 		os.RemoveAll(r.tempDir)
 	}
@@ -190,10 +233,16 @@ func (r *Repo) GuessNpm() ([]string, error) {
 	}
 	defer fr.Close()
 
+	return ParsePackageJSON(fr)
+}
+
+// ParsePackageJSON reads the package name from the content of
+// package.json and returns the npm url.
+func ParsePackageJSON(r io.Reader) ([]string, error) {
 	var packageContent struct {
 		Name string `json:"name"`
 	}
-	if err := json.NewDecoder(fr).Decode(&packageContent); err != nil {
+	if err := json.NewDecoder(r).Decode(&packageContent); err != nil {
 		return nil, err
 	}
 
@@ -210,13 +259,138 @@ func (r *Repo) GuessNpm() ([]string, error) {
 	return urls, nil
 }
 
-func (r *Repo) file(paths ...string) (file *object.File, err error) {
+func (r *Repo) GuessCrates() ([]string, error) {
+	cargoFile, err := r.file("Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	fr, err := cargoFile.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+
+	return ParseCargoToml(fr)
+}
+
+// ParseCargoToml reads the package name from the content of
+// Cargo.toml and returns the crates.io url. Workspace manifest
+// without package gives no url.
+func ParseCargoToml(r io.Reader) ([]string, error) {
+	tree, err := toml.LoadReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0)
+	if name, ok := tree.Get("package.name").(string); ok && name != "" {
+		pu := &pkguri.PkgURI{
+			Provider: pkgdata.CratesProvider,
+			URI:      name,
+			Proto:    pkguri.DefaultProto,
+		}
+		urls = append(urls, pkguri.ToURL(pu))
+	}
+
+	return urls, nil
+}
+
+func (r *Repo) GuessRubygems() ([]string, error) {
+	commit, err := r.headCommit()
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0)
+	for _, entry := range tree.Entries {
+		if !entry.Mode.IsFile() || !strings.HasSuffix(entry.Name, ".gemspec") {
+			continue
+		}
+		gemspecFile, err := tree.File(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		content, err := gemspecFile.Contents()
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, ParseGemspec(content)...)
+	}
+
+	return urls, nil
+}
+
+var gemspecNamePattern = regexp.MustCompile(`(?m)^\s*\w+\.name\s*=\s*["']([^"']+)["']`)
+
+// ParseGemspec reads the gem name from the content of gemspec and
+// returns the rubygems url. Only the name of string literal is
+// recognized.
+func ParseGemspec(content string) []string {
+	urls := make([]string, 0)
+	if matches := gemspecNamePattern.FindStringSubmatch(content); matches != nil {
+		pu := &pkguri.PkgURI{
+			Provider: pkgdata.RubygemsProvider,
+			URI:      matches[1],
+			Proto:    pkguri.DefaultProto,
+		}
+		urls = append(urls, pkguri.ToURL(pu))
+	}
+	return urls
+}
+
+func (r *Repo) GuessPackagist() ([]string, error) {
+	composerFile, err := r.file("composer.json")
+	if err != nil {
+		return nil, err
+	}
+
+	fr, err := composerFile.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+
+	return ParseComposerJSON(fr)
+}
+
+// ParseComposerJSON reads the package name from the content of
+// composer.json and returns the packagist url.
+func ParseComposerJSON(r io.Reader) ([]string, error) {
+	var composerContent struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r).Decode(&composerContent); err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0)
+	if composerContent.Name != "" {
+		pu := &pkguri.PkgURI{
+			Provider: pkgdata.PackagistProvider,
+			URI:      strings.ToLower(composerContent.Name),
+			Proto:    pkguri.DefaultProto,
+		}
+		urls = append(urls, pkguri.ToURL(pu))
+	}
+
+	return urls, nil
+}
+
+func (r *Repo) headCommit() (*object.Commit, error) {
 	ref, err := r.repo.Head()
 	if err != nil {
 		return nil, err
 	}
+	return r.repo.CommitObject(ref.Hash())
+}
 
-	commit, err := r.repo.CommitObject(ref.Hash())
+func (r *Repo) file(paths ...string) (file *object.File, err error) {
+	commit, err := r.headCommit()
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -73,3 +74,35 @@ func TestRepoAnalyze(t *testing.T) {
 		repo.Close()
 	}
 }
+
+func TestParseManifests(t *testing.T) {
+	urls, err := ParseCargoToml(strings.NewReader(`
+[package]
+name = "sample"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://crates.io/crates/sample"}, urls)
+
+	urls, err = ParseCargoToml(strings.NewReader(`
+[workspace]
+members = ["a", "b"]
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, urls)
+
+	urls = ParseGemspec(`
+Gem::Specification.new do |spec|
+  spec.name          = "sample"
+  spec.version       = Sample::VERSION
+end
+`)
+	assert.Equal(t, []string{"https://rubygems.org/gems/sample"}, urls)
+
+	urls, err = ParseComposerJSON(strings.NewReader(`{"name": "Vendor/Sample", "type": "library"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://packagist.org/packages/vendor/sample"}, urls)
+}
@@ -14,6 +14,9 @@ var (
 	CloneDir = "monler/git"
 
 	CloneProgress io.Writer
+
+	// CloneCache keeps the clones across analysis if set.
+	CloneCache *Cache
 )
 
 func getCloneDir(gitURL string) (string, error) {
@@ -4,12 +4,28 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
 )
 
 type Client struct {
 	client *http.Client
 	tokens *TokenStore
+	apiURL string
+}
+
+func (c *Client) endpoint(path string) string {
+	apiURL := c.apiURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return apiURL + path
 }
 
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
@@ -66,36 +82,203 @@ func (c *Client) GetRepository(owner, repo string) (*RepositoryResponse, error)
   }
 }`
 
+	var info struct {
+		Repo RepositoryResponse `json:"repository"`
+	}
+	if err := c.graphql(query, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info.Repo, nil
+}
+
+// ListTags returns the tags of repository ordered by the commit date
+// in descending order. The page starts after the cursor.
+func (c *Client) ListTags(owner, repo string, first int, after string) (*RefsResponse, error) {
+	query := `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/tags/", first: $first, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      totalCount
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+        name
+        target {
+          __typename
+          oid
+          ... on Commit {
+            committedDate
+          }
+          ... on Tag {
+            tagger {
+              date
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+		"first": first,
+	}
+	if after != "" {
+		variables["after"] = after
+	}
+
+	var info struct {
+		Repo *struct {
+			Refs RefsResponse `json:"refs"`
+		} `json:"repository"`
+	}
+	if err := c.graphql(query, variables, &info); err != nil {
+		return nil, err
+	}
+	if info.Repo == nil {
+		return nil, fmt.Errorf("github: repository %s/%s not found", owner, repo)
+	}
+	return &info.Repo.Refs, nil
+}
+
+func (c *Client) graphql(query string, variables map[string]interface{}, data interface{}) error {
 	body := &bytes.Buffer{}
 	err := json.NewEncoder(body).Encode(struct {
-		Query string `json:"query"`
-	}{query})
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{query, variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", c.endpoint("/graphql"), body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	var errs []struct {
+		Type string `json:"type"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&struct {
+		Data   interface{} `json:"data"`
+		Errors interface{} `json:"errors"`
+	}{data, &errs})
+	if err != nil {
+		return err
+	}
+	for _, e := range errs {
+		if e.Type == "RATE_LIMITED" {
+			return ErrRateLimit
+		}
+	}
+	return nil
+}
+
+// checkResponse reports the error of response status, the exceeded
+// rate limit is reported as ErrRateLimit.
+func checkResponse(resp *http.Response) error {
+	switch {
+	case resp.StatusCode < 400:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return provider.ErrNotFound
+	case (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return ErrRateLimit
+	}
+	return fmt.Errorf("github: api response got %d", resp.StatusCode)
+}
+
+func contentsPath(owner, repo, path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/contents/" + strings.Join(segments, "/")
+}
+
+// ListContents returns the entries of directory at path on the
+// default branch.
+func (c *Client) ListContents(owner, repo, path string) ([]*ContentResponse, error) {
+	req, err := http.NewRequest("GET", c.endpoint(contentsPath(owner, repo, path)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", "https://api.github.com/graphql", body)
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var contents []*ContentResponse
+	err = json.NewDecoder(resp.Body).Decode(&contents)
 	if err != nil {
 		return nil, err
 	}
+	return contents, nil
+}
+
+// GetRawContent returns the content of file at path on the default
+// branch.
+func (c *Client) GetRawContent(owner, repo, path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.endpoint(contentsPath(owner, repo, path)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3.raw")
 	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("github: api response got %d", resp.StatusCode)
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
+	return ioutil.ReadAll(resp.Body)
+}
 
-	var info struct {
-		Data struct {
-			Repo RepositoryResponse `json:"repository"`
-		} `json:"data"`
+// ListReleases returns the releases of repository at the given page,
+// sorted by creation time in descending order.
+func (c *Client) ListReleases(owner, repo string, page, perPage int) ([]*ReleaseResponse, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	path := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo) + "/releases?" + query.Encode()
+
+	req, err := http.NewRequest("GET", c.endpoint(path), nil)
+	if err != nil {
+		return nil, err
 	}
-	err = json.NewDecoder(resp.Body).Decode(&info)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	return &info.Data.Repo, nil
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var releases []*ReleaseResponse
+	err = json.NewDecoder(resp.Body).Decode(&releases)
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
 }
 
 type Transport struct {
@@ -153,3 +336,54 @@ type FundingLinkResponse struct {
 	Platform string `json:"platform"`
 	URL      string `json:"url"`
 }
+
+type RefsResponse struct {
+	TotalCount int64            `json:"totalCount"`
+	PageInfo   PageInfoResponse `json:"pageInfo"`
+	Nodes      []*RefResponse   `json:"nodes"`
+}
+
+type PageInfoResponse struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type RefResponse struct {
+	Name   string             `json:"name"`
+	Target *RefTargetResponse `json:"target"`
+}
+
+type RefTargetResponse struct {
+	Typename      string     `json:"__typename"`
+	Oid           string     `json:"oid"`
+	CommittedDate *time.Time `json:"committedDate"`
+	Tagger        *struct {
+		Date *time.Time `json:"date"`
+	} `json:"tagger"`
+}
+
+type ReleaseResponse struct {
+	TagName         string                  `json:"tag_name"`
+	Name            string                  `json:"name"`
+	Body            string                  `json:"body"`
+	Draft           bool                    `json:"draft"`
+	Prerelease      bool                    `json:"prerelease"`
+	TargetCommitish string                  `json:"target_commitish"`
+	CreatedAt       time.Time               `json:"created_at"`
+	PublishedAt     *time.Time              `json:"published_at"`
+	Assets          []*ReleaseAssetResponse `json:"assets"`
+}
+
+type ContentResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type ReleaseAssetResponse struct {
+	Name               string `json:"name"`
+	ContentType        string `json:"content_type"`
+	Size               int64  `json:"size"`
+	DownloadCount      int64  `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
@@ -1,8 +1,12 @@
 package github
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -10,6 +14,7 @@ import (
 	"github.com/pinmonl/pinmonl/model/field"
 	"github.com/pinmonl/pinmonl/monler/provider"
 	"github.com/pinmonl/pinmonl/monler/provider/git"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
 	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
 	"github.com/pinmonl/pinmonl/pkgs/pkguri"
 	"github.com/sirupsen/logrus"
@@ -17,14 +22,28 @@ import (
 
 // Github settings.
 var (
-	DefaultHost = pkgdata.GithubHost
+	DefaultHost     = pkgdata.GithubHost
+	DefaultAPIURL   = "https://api.github.com"
+	ReleasesPerPage = 100
+	TagsPerPage     = 100
+
+	// AlwaysClone lists the tags by cloning the repository even if
+	// the token is available.
+	AlwaysClone = false
 )
 
 // Errors.
 var (
 	ErrNotSupport = errors.New("github: repo not support")
+	ErrRateLimit  = errors.New("github: rate limit exceeded")
 )
 
+// isRateLimit reports whether the api cannot be called until the rate
+// limit is reset.
+func isRateLimit(err error) bool {
+	return err == ErrRateLimit || err == ErrNoToken
+}
+
 type Provider struct {
 	tokens *TokenStore
 }
@@ -89,34 +108,51 @@ type Repo struct {
 }
 
 func newRepo(pu *pkguri.PkgURI, tokens *TokenStore) (*Repo, error) {
-	gitURL := pkguri.ToURL(pu)
-	gitRepo, err := git.NewRepo(gitURL)
-	if err != nil {
-		return nil, err
-	}
-
 	logrus.Debugf("github: report created %s", pu)
 
 	return &Repo{
-		pu:      pu,
-		tokens:  tokens,
-		gitRepo: gitRepo,
+		pu:     pu,
+		tokens: tokens,
 	}, nil
 }
 
+// git clones the repository on first use.
+func (r *Repo) git() (*git.Repo, error) {
+	if r.gitRepo == nil {
+		gitRepo, err := git.NewRepo(pkguri.ToURL(r.pu))
+		if err != nil {
+			return nil, err
+		}
+		r.gitRepo = gitRepo
+	}
+	return r.gitRepo, nil
+}
+
 func (r *Repo) Analyze() (provider.Report, error) {
 	return r.analyze()
 }
 
 func (r *Repo) analyze() (*Report, error) {
-	gitReport, err := r.gitRepo.Analyze()
+	client := &Client{tokens: r.tokens}
+
+	// Fall back to clone if the rate limit of tokens is exhausted.
+	if r.useAPI() {
+		report, err := newAPIReport(r.pu, client, r.cloneTags)
+		r.lastReport = report
+		return report, err
+	}
+
+	gitRepo, err := r.git()
+	if err != nil {
+		return nil, err
+	}
+	gitReport, err := gitRepo.Analyze()
 	if err != nil {
 		return nil, err
 	}
 
 	logrus.Debugf("github: report analyzed %s", r.pu)
 
-	client := &Client{tokens: r.tokens}
 	report, err := newReport(r.pu, client, gitReport)
 	r.lastReport = report
 	return report, err
@@ -129,9 +165,26 @@ func (r *Repo) Derived() ([]string, error) {
 		}
 	}
 
-	derived, err := r.gitRepo.Derived()
-	if err != nil {
-		return nil, err
+	var (
+		derived []string
+		err     error
+	)
+	if r.useAPI() {
+		client := &Client{tokens: r.tokens}
+		derived, err = derivedByAPI(client, r.pu.Namespace(), r.pu.RepoName())
+		if err != nil {
+			logrus.Debugf("github: read manifests of %s by api err(%s), fall back to clone", r.pu, err)
+		}
+	}
+	if derived == nil {
+		gitRepo, err := r.git()
+		if err != nil {
+			return nil, err
+		}
+		derived, err = gitRepo.Derived()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if pu, err := r.lastReport.URI(); err == nil {
@@ -141,6 +194,100 @@ func (r *Repo) Derived() ([]string, error) {
 	return derived, nil
 }
 
+// useAPI reports whether the api is used instead of cloning the
+// repository, which is not used once the repository is cloned.
+func (r *Repo) useAPI() bool {
+	if AlwaysClone || r.gitRepo != nil {
+		return false
+	}
+	_, err := r.tokens.Get()
+	return err == nil
+}
+
+// cloneTags lists the tags by cloning the repository.
+func (r *Repo) cloneTags() ([]*model.Stat, error) {
+	gitRepo, err := r.git()
+	if err != nil {
+		return nil, err
+	}
+	report, err := gitRepo.Analyze()
+	if err != nil {
+		return nil, err
+	}
+	defer report.Close()
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// derivedByAPI reads the manifests at the root of repository by the
+// contents api in the same way as git provider does.
+func derivedByAPI(client *Client, owner, repo string) ([]string, error) {
+	entries, err := client.ListContents(owner, repo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]bool)
+	gemspecs := make([]string, 0)
+	for _, entry := range entries {
+		if entry.Type != "file" {
+			continue
+		}
+		files[entry.Name] = true
+		if strings.HasSuffix(entry.Name, ".gemspec") {
+			gemspecs = append(gemspecs, entry.Name)
+		}
+	}
+
+	derived := make([]string, 0)
+	guess := func(path string, parse func(io.Reader) ([]string, error)) error {
+		if !files[path] {
+			return nil
+		}
+		content, err := client.GetRawContent(owner, repo, path)
+		if err != nil {
+			return err
+		}
+		// Invalid manifest is skipped as git provider does.
+		if urls, err := parse(bytes.NewReader(content)); err == nil {
+			derived = append(derived, urls...)
+		}
+		return nil
+	}
+
+	if err := guess("package.json", git.ParsePackageJSON); err != nil {
+		return nil, err
+	}
+	if err := guess("Cargo.toml", git.ParseCargoToml); err != nil {
+		return nil, err
+	}
+	for _, gemspec := range gemspecs {
+		if err := guess(gemspec, parseGemspec); err != nil {
+			return nil, err
+		}
+	}
+	if err := guess("composer.json", git.ParseComposerJSON); err != nil {
+		return nil, err
+	}
+	return derived, nil
+}
+
+func parseGemspec(r io.Reader) ([]string, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return git.ParseGemspec(string(content)), nil
+}
+
 func (r *Repo) Close() error {
 	if r.gitRepo != nil {
 		if err := r.gitRepo.Close(); err != nil {
@@ -151,8 +298,7 @@ func (r *Repo) Close() error {
 }
 
 type Report struct {
-	*pkguri.PkgURI
-	stats     []*model.Stat
+	provider.Report
 	repoInfo  *RepositoryResponse
 	gitReport provider.Report
 }
@@ -162,7 +308,48 @@ func newReport(pu *pkguri.PkgURI, client *Client, gitReport provider.Report) (*R
 	if err != nil {
 		return nil, err
 	}
+	stats := newRepoStats(resp)
+
+	tags := make([]*model.Stat, 0)
+	for gitReport.Next() {
+		tag, err := gitReport.Tag()
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	attachReleases(tags, releasesByTag(client, pu.Namespace(), pu.RepoName()))
+
+	return &Report{
+		Report:    prvdutils.NewStaticReport(pu, stats, tags),
+		repoInfo:  resp,
+		gitReport: gitReport,
+	}, nil
+}
+
+// newAPIReport lists the tags by API page by page instead of cloning
+// the repository. The rest of tags are listed by clone if the rate
+// limit is exceeded in the middle of pages.
+func newAPIReport(pu *pkguri.PkgURI, client *Client, clone func() ([]*model.Stat, error)) (*Report, error) {
+	resp, err := client.GetRepository(pu.Namespace(), pu.RepoName())
+	if err != nil {
+		return nil, err
+	}
+	stats := newRepoStats(resp)
 
+	statFn := func(page int64) ([]*model.Stat, int64, bool, error) {
+		return stats, int64(len(stats)), false, nil
+	}
+	tagFn := newTagPageFunc(client, pu.Namespace(), pu.RepoName(), clone)
+
+	return &Report{
+		Report:   prvdutils.NewPagesReport(pu, statFn, tagFn),
+		repoInfo: resp,
+	}, nil
+}
+
+func newRepoStats(resp *RepositoryResponse) []*model.Stat {
 	now := field.Now()
 	stats := []*model.Stat{
 		&model.Stat{
@@ -231,33 +418,182 @@ func newReport(pu *pkguri.PkgURI, client *Client, gitReport provider.Report) (*R
 		Substats:   &fundingStats,
 	})
 
-	return &Report{
-		PkgURI:    pu,
-		stats:     stats,
-		repoInfo:  resp,
-		gitReport: gitReport,
-	}, nil
+	return stats
 }
 
-func (r *Report) URI() (*pkguri.PkgURI, error) {
-	return r.PkgURI, nil
+func newTagPageFunc(client *Client, owner, repo string, clone func() ([]*model.Stat, error)) prvdutils.PageFunc {
+	var (
+		cursor   string
+		releases map[string]*ReleaseResponse
+		seen     = make(map[string]bool)
+	)
+	return func(page int64) ([]*model.Stat, int64, bool, error) {
+		if releases == nil {
+			releases = releasesByTag(client, owner, repo)
+		}
+
+		refs, err := client.ListTags(owner, repo, TagsPerPage, cursor)
+		if isRateLimit(err) && clone != nil {
+			logrus.Debugf("github: list tags of %s/%s err(%s), fall back to clone", owner, repo, err)
+			return cloneRestTags(clone, seen, page, releases)
+		}
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		tags := make([]*model.Stat, 0, len(refs.Nodes))
+		for _, ref := range refs.Nodes {
+			if tag := newRefStat(ref); tag != nil {
+				tags = append(tags, tag)
+				seen[tag.Value] = true
+			}
+		}
+		// The first page holds the most recent tags, in which the
+		// greatest version is taken as latest.
+		if page == 1 && len(tags) > 0 {
+			sorted := make([]*model.Stat, len(tags))
+			copy(sorted, tags)
+			sort.Sort(model.StatBySemver(sorted))
+			sorted[len(sorted)-1].IsLatest = true
+		}
+
+		attachReleases(tags, releases)
+		cursor = refs.PageInfo.EndCursor
+		return tags, refs.TotalCount, refs.PageInfo.HasNextPage, nil
+	}
 }
 
-func (r *Report) Stats() ([]*model.Stat, error) {
-	return r.stats, nil
+// cloneRestTags returns the tags from clone which are not listed in
+// the previous pages.
+func cloneRestTags(clone func() ([]*model.Stat, error), seen map[string]bool, page int64, releases map[string]*ReleaseResponse) ([]*model.Stat, int64, bool, error) {
+	tags, err := clone()
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	rest := make([]*model.Stat, 0, len(tags))
+	for _, tag := range tags {
+		if seen[tag.Value] {
+			continue
+		}
+		// The latest is taken from the first page.
+		if page > 1 {
+			tag.IsLatest = false
+		}
+		rest = append(rest, tag)
+	}
+
+	attachReleases(rest, releases)
+	return rest, int64(len(seen) + len(rest)), false, nil
 }
 
-func (r *Report) Next() bool {
-	return r.gitReport.Next()
+// newRefStat converts the tag ref into stat in the same way as git
+// provider does, so that the checksums stay the same in both modes.
+func newRefStat(ref *RefResponse) *model.Stat {
+	if ref.Target == nil {
+		return nil
+	}
+
+	var recordedAt field.Time
+	switch ref.Target.Typename {
+	case "Tag":
+		if ref.Target.Tagger != nil && ref.Target.Tagger.Date != nil {
+			recordedAt = field.Time(*ref.Target.Tagger.Date)
+		}
+	case "Commit":
+		if ref.Target.CommittedDate != nil {
+			recordedAt = field.Time(*ref.Target.CommittedDate)
+		}
+	default:
+		return nil
+	}
+
+	return &model.Stat{
+		RecordedAt: recordedAt,
+		Kind:       model.TagStat,
+		Value:      ref.Name,
+		Checksum:   ref.Target.Oid,
+	}
 }
 
-func (r *Report) Tag() (*model.Stat, error) {
-	return r.gitReport.Tag()
+func listReleases(client *Client, owner, repo string) ([]*ReleaseResponse, error) {
+	releases := make([]*ReleaseResponse, 0)
+	for page := 1; ; page++ {
+		items, err := client.ListReleases(owner, repo, page, ReleasesPerPage)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, items...)
+		if len(items) < ReleasesPerPage {
+			break
+		}
+	}
+	return releases, nil
+}
+
+// releasesByTag lists the published releases by tag name. The tags
+// are still reported if the releases cannot be listed.
+func releasesByTag(client *Client, owner, repo string) map[string]*ReleaseResponse {
+	byTag := make(map[string]*ReleaseResponse)
+	releases, err := listReleases(client, owner, repo)
+	if err != nil {
+		logrus.Debugf("github: list releases of %s/%s err(%s)", owner, repo, err)
+		return byTag
+	}
+	for _, release := range releases {
+		// Drafts are not published yet.
+		if release.Draft {
+			continue
+		}
+		byTag[release.TagName] = release
+	}
+	return byTag
+}
+
+// attachReleases sets the notes and assets of releases to the tags of
+// the same name, prereleases are marked by channel substat.
+func attachReleases(tags []*model.Stat, releases map[string]*ReleaseResponse) {
+	for _, tag := range tags {
+		release, has := releases[tag.Value]
+		if !has {
+			continue
+		}
+
+		assets := make(model.StatAssetList, len(release.Assets))
+		for i, asset := range release.Assets {
+			assets[i] = &model.StatAsset{
+				Name:          asset.Name,
+				URL:           asset.BrowserDownloadURL,
+				ContentType:   asset.ContentType,
+				Size:          asset.Size,
+				DownloadCount: asset.DownloadCount,
+			}
+		}
+		tag.Name = release.Name
+		tag.Body = release.Body
+		tag.Assets = assets
+
+		if release.Prerelease {
+			recordedAt := release.CreatedAt
+			if release.PublishedAt != nil {
+				recordedAt = *release.PublishedAt
+			}
+			tag.Substats = &model.StatList{
+				&model.Stat{
+					Kind:       model.ChannelStat,
+					Value:      "prerelease",
+					RecordedAt: field.Time(recordedAt),
+				},
+			}
+		}
+	}
 }
 
 func (r *Report) Close() error {
-	if err := r.gitReport.Close(); err != nil {
-		return err
+	if r.gitReport != nil {
+		if err := r.gitReport.Close(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/graphql":
+			var body struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Variables == nil {
+				fmt.Fprint(w, `{"data": {"repository": {
+  "forkCount": 3,
+  "stargazers": {"totalCount": 12},
+  "licenseInfo": {"name": "MIT License", "key": "mit"},
+  "fundingLinks": []
+}}}`)
+				return
+			}
+			switch body.Variables["after"] {
+			case "cursor1":
+				if body.Variables["name"] == "limited" {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				fmt.Fprint(w, `{"data": {"repository": {"refs": {
+  "totalCount": 3,
+  "pageInfo": {"hasNextPage": false, "endCursor": "cursor2"},
+  "nodes": [
+    {"name": "v1.0.0", "target": {"__typename": "Commit", "oid": "c1", "committedDate": "2020-05-01T10:00:00Z"}}
+  ]
+}}}}`)
+			default:
+				fmt.Fprint(w, `{"data": {"repository": {"refs": {
+  "totalCount": 3,
+  "pageInfo": {"hasNextPage": true, "endCursor": "cursor1"},
+  "nodes": [
+    {"name": "v1.2.0-rc.1", "target": {"__typename": "Commit", "oid": "c3", "committedDate": "2020-07-01T10:00:00Z"}},
+    {"name": "v1.1.0", "target": {"__typename": "Tag", "oid": "c2", "tagger": {"date": "2020-06-01T10:00:00Z"}}}
+  ]
+}}}}`)
+			}
+		case "/repos/owner/repo/contents/":
+			fmt.Fprint(w, `[
+  {"name": "package.json", "path": "package.json", "type": "file"},
+  {"name": "repo.gemspec", "path": "repo.gemspec", "type": "file"},
+  {"name": "composer.json", "path": "composer.json", "type": "dir"}
+]`)
+		case "/repos/owner/repo/contents/package.json":
+			if r.Header.Get("Accept") != "application/vnd.github.v3.raw" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `{"name": "@owner/repo"}`)
+		case "/repos/owner/repo/contents/repo.gemspec":
+			fmt.Fprint(w, `Gem::Specification.new do |spec|
+  spec.name = "repo"
+end`)
+		case "/repos/owner/limited/contents/":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+		case "/repos/owner/repo/releases":
+			switch r.URL.Query().Get("page") {
+			case "1":
+				fmt.Fprint(w, `[
+  {"tag_name": "v1.2.0-rc.1", "name": "v1.2.0 RC 1", "body": "Release candidate", "prerelease": true,
+   "created_at": "2020-07-01T10:00:00Z", "published_at": "2020-07-01T10:00:00Z", "assets": []},
+  {"tag_name": "v1.1.0", "name": "v1.1.0", "body": "## Changes\n- Feature", "prerelease": false,
+   "created_at": "2020-06-01T10:00:00Z", "published_at": "2020-06-01T10:00:00Z",
+   "assets": [{"name": "repo_linux_amd64.tar.gz", "content_type": "application/gzip", "size": 1024, "download_count": 7,
+     "browser_download_url": "https://github.com/owner/repo/releases/download/v1.1.0/repo_linux_amd64.tar.gz"}]}
+]`)
+			case "2":
+				fmt.Fprint(w, `[
+  {"tag_name": "v1.0.0", "name": "v1.0.0", "body": "Initial release",
+   "created_at": "2020-05-01T10:00:00Z", "published_at": "2020-05-01T10:00:00Z", "assets": []},
+  {"tag_name": "v2.0.0", "name": "v2.0.0", "body": "WIP", "draft": true,
+   "created_at": "2020-08-01T10:00:00Z", "published_at": null, "assets": []}
+]`)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestListReleases(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	defer func(n int) { ReleasesPerPage = n }(ReleasesPerPage)
+	ReleasesPerPage = 2
+
+	client := &Client{client: srv.Client(), apiURL: srv.URL}
+	releases, err := listReleases(client, "owner", "repo")
+	assert.Nil(t, err)
+	if assert.Len(t, releases, 4) {
+		assert.Equal(t, "v1.2.0-rc.1", releases[0].TagName)
+		assert.Equal(t, "v2.0.0", releases[3].TagName)
+		assert.Nil(t, releases[3].PublishedAt)
+	}
+
+	_, err = client.ListReleases("owner", "not-exist", 1, ReleasesPerPage)
+	assert.NotNil(t, err)
+}
+
+func TestAttachReleases(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	defer func(n int) { ReleasesPerPage = n }(ReleasesPerPage)
+	ReleasesPerPage = 2
+
+	client := &Client{client: srv.Client(), apiURL: srv.URL}
+	releases := releasesByTag(client, "owner", "repo")
+	assert.Len(t, releases, 3)
+
+	tags := []*model.Stat{
+		{Kind: model.TagStat, Value: "v1.0.0", Checksum: "c1"},
+		{Kind: model.TagStat, Value: "v1.1.0", Checksum: "c2", IsLatest: true},
+		{Kind: model.TagStat, Value: "v1.2.0-rc.1", Checksum: "c3"},
+		{Kind: model.TagStat, Value: "v2.0.0", Checksum: "c4"},
+	}
+	attachReleases(tags, releases)
+
+	stable := tags[1]
+	assert.True(t, stable.IsLatest)
+	assert.Equal(t, "c2", stable.Checksum)
+	assert.Equal(t, "## Changes\n- Feature", stable.Body)
+	assert.Nil(t, stable.Substats)
+	if assert.Len(t, stable.Assets, 1) {
+		assert.Equal(t, "repo_linux_amd64.tar.gz", stable.Assets[0].Name)
+		assert.Equal(t, int64(1024), stable.Assets[0].Size)
+		assert.Equal(t, int64(7), stable.Assets[0].DownloadCount)
+	}
+
+	prerelease := tags[2]
+	assert.Equal(t, "v1.2.0 RC 1", prerelease.Name)
+	if assert.NotNil(t, prerelease.Substats) && assert.Len(t, *prerelease.Substats, 1) {
+		assert.Equal(t, model.ChannelStat, (*prerelease.Substats)[0].Kind)
+		assert.Equal(t, "prerelease", (*prerelease.Substats)[0].Value)
+	}
+
+	// Draft is not attached.
+	draft := tags[3]
+	assert.Empty(t, draft.Body)
+	assert.Nil(t, draft.Substats)
+
+	// Tags are kept if the releases cannot be listed.
+	assert.Empty(t, releasesByTag(client, "owner", "not-exist"))
+}
+
+func TestAPIReport(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	defer func(n int) { ReleasesPerPage = n }(ReleasesPerPage)
+	ReleasesPerPage = 2
+
+	client := &Client{client: srv.Client(), apiURL: srv.URL}
+	pu := &pkguri.PkgURI{Provider: "github", Host: "github.com", URI: "owner/repo"}
+	report, err := newAPIReport(pu, client, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+	}
+	assert.Equal(t, "12", values[model.StarCountStat])
+	assert.Equal(t, "mit", values[model.LicenseStat])
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		assert.Equal(t, model.TagStat, tag.Kind)
+		tags = append(tags, tag)
+	}
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "v1.2.0-rc.1", tags[0].Value)
+		assert.True(t, tags[0].IsLatest)
+		assert.Equal(t, "v1.1.0", tags[1].Value)
+		assert.Equal(t, "c2", tags[1].Checksum)
+		assert.False(t, tags[1].IsLatest)
+		assert.Equal(t, 6, int(tags[1].RecordedAt.Time().Month()))
+		assert.Equal(t, "## Changes\n- Feature", tags[1].Body)
+		assert.Equal(t, "v1.0.0", tags[2].Value)
+		assert.False(t, tags[2].IsLatest)
+		assert.Equal(t, "Initial release", tags[2].Body)
+	}
+}
+
+func TestAPIReportRateLimit(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := &Client{client: srv.Client(), apiURL: srv.URL}
+	pu := &pkguri.PkgURI{Provider: "github", Host: "github.com", URI: "owner/limited"}
+	clone := func() ([]*model.Stat, error) {
+		return []*model.Stat{
+			{Kind: model.TagStat, Value: "v1.0.0", Checksum: "c1"},
+			{Kind: model.TagStat, Value: "v1.1.0", Checksum: "c2"},
+			{Kind: model.TagStat, Value: "v1.2.0-rc.1", Checksum: "c3", IsLatest: true},
+		}, nil
+	}
+	report, err := newAPIReport(pu, client, clone)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags = append(tags, tag)
+	}
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "v1.2.0-rc.1", tags[0].Value)
+		assert.True(t, tags[0].IsLatest)
+		assert.Equal(t, "v1.1.0", tags[1].Value)
+		assert.Equal(t, "v1.0.0", tags[2].Value)
+		assert.Equal(t, "c1", tags[2].Checksum)
+		assert.False(t, tags[2].IsLatest)
+	}
+}
+
+func TestDerivedByAPI(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := &Client{client: srv.Client(), apiURL: srv.URL}
+	derived, err := derivedByAPI(client, "owner", "repo")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"https://www.npmjs.com/package/@owner/repo",
+		"https://rubygems.org/gems/repo",
+	}, derived)
+
+	_, err = derivedByAPI(client, "owner", "limited")
+	assert.Equal(t, ErrRateLimit, err)
+}
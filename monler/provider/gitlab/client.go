@@ -0,0 +1,167 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+)
+
+// PerPage is the page size of list api.
+var PerPage int64 = 100
+
+type Client struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newClient(client *http.Client, pu *pkguri.PkgURI) *Client {
+	host := pu.Host
+	if host == "" {
+		host = DefaultHost
+	}
+	proto := pu.Proto
+	if proto == "" {
+		proto = pkguri.DefaultProto
+	}
+	return &Client{
+		client:  client,
+		baseURL: proto + "://" + host + "/api/v4",
+	}
+}
+
+func (c *Client) get(dest string) (*http.Response, error) {
+	resp, err := c.client.Get(c.baseURL + dest)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("gitlab: api response got %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (c *Client) getJSON(dest string, out interface{}) (*http.Response, error) {
+	resp, err := c.get(dest)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return resp, json.NewDecoder(resp.Body).Decode(out)
+}
+
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(project)
+}
+
+func (c *Client) Project(project string) (*ProjectResponse, error) {
+	var out ProjectResponse
+	if _, err := c.getJSON(projectPath(project)+"?license=true", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) Tags(project string, page int64) ([]*TagResponse, *PageInfo, error) {
+	query := url.Values{}
+	query.Set("page", strconv.FormatInt(page, 10))
+	query.Set("per_page", strconv.FormatInt(PerPage, 10))
+
+	var out []*TagResponse
+	resp, err := c.getJSON(projectPath(project)+"/repository/tags?"+query.Encode(), &out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, newPageInfo(resp.Header), nil
+}
+
+func (c *Client) Releases(project string, page int64) ([]*ReleaseResponse, *PageInfo, error) {
+	query := url.Values{}
+	query.Set("page", strconv.FormatInt(page, 10))
+	query.Set("per_page", strconv.FormatInt(PerPage, 10))
+
+	var out []*ReleaseResponse
+	resp, err := c.getJSON(projectPath(project)+"/releases?"+query.Encode(), &out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, newPageInfo(resp.Header), nil
+}
+
+// RawFile opens the file content of the project at ref.
+func (c *Client) RawFile(project, path, ref string) (io.ReadCloser, error) {
+	query := url.Values{}
+	query.Set("ref", ref)
+
+	resp, err := c.get(projectPath(project) + "/repository/files/" + url.PathEscape(path) + "/raw?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// PageInfo is parsed from the pagination headers.
+type PageInfo struct {
+	Total    int64
+	NextPage int64
+}
+
+func newPageInfo(header http.Header) *PageInfo {
+	info := &PageInfo{}
+	info.Total, _ = strconv.ParseInt(header.Get("X-Total"), 10, 64)
+	info.NextPage, _ = strconv.ParseInt(header.Get("X-Next-Page"), 10, 64)
+	return info
+}
+
+type ProjectResponse struct {
+	ID                int64            `json:"id"`
+	PathWithNamespace string           `json:"path_with_namespace"`
+	WebURL            string           `json:"web_url"`
+	DefaultBranch     string           `json:"default_branch"`
+	StarCount         int64            `json:"star_count"`
+	ForksCount        int64            `json:"forks_count"`
+	OpenIssuesCount   *int64           `json:"open_issues_count"`
+	License           *LicenseResponse `json:"license"`
+}
+
+type LicenseResponse struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type TagResponse struct {
+	Name   string          `json:"name"`
+	Commit *CommitResponse `json:"commit"`
+}
+
+type CommitResponse struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ReleaseResponse struct {
+	Name        string                 `json:"name"`
+	TagName     string                 `json:"tag_name"`
+	Description string                 `json:"description"`
+	Assets      *ReleaseAssetsResponse `json:"assets"`
+}
+
+type ReleaseAssetsResponse struct {
+	Links []*ReleaseLinkResponse `json:"links"`
+}
+
+type ReleaseLinkResponse struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,329 @@
+package gitlab
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/provider/git"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+// Gitlab settings.
+var (
+	DefaultHost = pkgdata.GitlabHost
+)
+
+type Provider struct {
+	hosts  []string
+	client *http.Client
+}
+
+// NewProvider creates provider of the gitlab instances at hosts,
+// which defaults to gitlab.com.
+func NewProvider(hosts []string) (*Provider, error) {
+	if len(hosts) == 0 {
+		hosts = []string{DefaultHost}
+	}
+	return &Provider{
+		hosts:  hosts,
+		client: &http.Client{},
+	}, nil
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.GitlabProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseGitlab(rawurl, p.hosts...)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseGitlab(rawurl, p.hosts...)
+	if err != nil {
+		return err
+	}
+
+	client := newClient(p.client, pu)
+	if _, err := client.Project(pu.URI); err != nil {
+		return provider.ErrNotSupport
+	}
+	return nil
+}
+
+type Repo struct {
+	pu          *pkguri.PkgURI
+	client      *Client
+	lastProject *ProjectResponse
+}
+
+func newRepo(client *http.Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: newClient(client, pu),
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	project, err := r.client.Project(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("gitlab:", err)
+		return nil, err
+	}
+
+	logrus.Debugf("gitlab: report analyzed %s", r.pu)
+
+	r.lastProject = project
+	return newReport(r.client, r.pu, project)
+}
+
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastProject == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	derived := make([]string, 0)
+
+	if r.lastProject.DefaultBranch != "" {
+		if npmUrls, err := r.guessNpm(); err == nil {
+			derived = append(derived, npmUrls...)
+		}
+	}
+
+	// The project is renamed or moved.
+	if webURL := r.lastProject.WebURL; webURL != "" && webURL != pkguri.ToURL(r.pu) {
+		derived = append(derived, webURL)
+	}
+
+	return derived, nil
+}
+
+func (r *Repo) guessNpm() ([]string, error) {
+	fr, err := r.client.RawFile(r.pu.URI, "package.json", r.lastProject.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+
+	return git.ParsePackageJSON(fr)
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.PagesReport
+}
+
+func newReport(client *Client, pu *pkguri.PkgURI, project *ProjectResponse) (*Report, error) {
+	statFn := reportStatFn(project)
+	tagFn := reportTagFn(client, pu.URI)
+
+	report := prvdutils.NewPagesReport(pu, statFn, tagFn)
+	return &Report{PagesReport: report}, nil
+}
+
+func reportStatFn(project *ProjectResponse) prvdutils.PageFunc {
+	return func(_ int64) ([]*model.Stat, int64, bool, error) {
+		now := field.Now()
+		stats := []*model.Stat{
+			&model.Stat{
+				Kind:       model.StarCountStat,
+				Value:      strconv.FormatInt(project.StarCount, 10),
+				RecordedAt: now,
+				IsLatest:   true,
+			},
+			&model.Stat{
+				Kind:       model.ForkCountStat,
+				Value:      strconv.FormatInt(project.ForksCount, 10),
+				RecordedAt: now,
+				IsLatest:   true,
+			},
+		}
+
+		// Open issues count is absent if issues are disabled.
+		if project.OpenIssuesCount != nil {
+			stats = append(stats, &model.Stat{
+				Kind:       model.OpenIssueCountStat,
+				Value:      strconv.FormatInt(*project.OpenIssuesCount, 10),
+				RecordedAt: now,
+				IsLatest:   true,
+			})
+		}
+		if project.License != nil {
+			stats = append(stats, &model.Stat{
+				Kind:       model.LicenseStat,
+				Value:      strings.ToLower(project.License.Key),
+				RecordedAt: now,
+				IsLatest:   true,
+			})
+		}
+
+		slen := int64(len(stats))
+		return stats, slen, false, nil
+	}
+}
+
+// reportTagFn lists the tags in the order of last update with the
+// notes and assets of their releases. The latest tag is picked across
+// the fetched pages.
+func reportTagFn(client *Client, project string) prvdutils.PageFunc {
+	var (
+		releases map[string]*ReleaseResponse
+		latest   *model.Stat
+	)
+	return func(page int64) ([]*model.Stat, int64, bool, error) {
+		if releases == nil {
+			releases = releasesByTag(client, project)
+		}
+
+		tagResponses, pageInfo, err := client.Tags(project, page)
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		tags := make([]*model.Stat, len(tagResponses))
+		for i, tr := range tagResponses {
+			tags[i] = parseTag(tr)
+		}
+		latest = markLatest(tags, latest)
+
+		attachReleases(tags, releases)
+		return tags, pageInfo.Total, pageInfo.NextPage > 0, nil
+	}
+}
+
+// markLatest flags the newer one of latest and the tags as latest, and
+// returns it. The greatest version wins, the tags which are not semver
+// are compared by the time of commit.
+func markLatest(tags []*model.Stat, latest *model.Stat) *model.Stat {
+	for _, tag := range tags {
+		if latest != nil && !isNewerTag(tag, latest) {
+			continue
+		}
+		if latest != nil {
+			latest.IsLatest = false
+		}
+		tag.IsLatest = true
+		latest = tag
+	}
+	return latest
+}
+
+func isNewerTag(tag, than *model.Stat) bool {
+	_, err := semver.NewVersion(tag.Value)
+	isSemver := err == nil
+	_, err = semver.NewVersion(than.Value)
+	thanSemver := err == nil
+
+	switch {
+	case isSemver && thanSemver:
+		return model.StatBySemver{than, tag}.Less(0, 1)
+	case isSemver != thanSemver:
+		return isSemver
+	default:
+		return tag.RecordedAt.Time().After(than.RecordedAt.Time())
+	}
+}
+
+func listReleases(client *Client, project string) ([]*ReleaseResponse, error) {
+	releases := make([]*ReleaseResponse, 0)
+	for page := int64(1); ; page++ {
+		items, pageInfo, err := client.Releases(project, page)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, items...)
+		if pageInfo.NextPage == 0 {
+			break
+		}
+	}
+	return releases, nil
+}
+
+// releasesByTag lists the releases by tag name. The tags are still
+// reported if the releases cannot be listed.
+func releasesByTag(client *Client, project string) map[string]*ReleaseResponse {
+	byTag := make(map[string]*ReleaseResponse)
+	releases, err := listReleases(client, project)
+	if err != nil {
+		logrus.Debugf("gitlab: list releases of %s err(%s)", project, err)
+		return byTag
+	}
+	for _, release := range releases {
+		byTag[release.TagName] = release
+	}
+	return byTag
+}
+
+// attachReleases sets the notes and asset links of releases to the
+// tags of the same name.
+func attachReleases(tags []*model.Stat, releases map[string]*ReleaseResponse) {
+	for _, tag := range tags {
+		release, has := releases[tag.Value]
+		if !has {
+			continue
+		}
+
+		assets := make(model.StatAssetList, 0)
+		if release.Assets != nil {
+			for _, link := range release.Assets.Links {
+				assets = append(assets, &model.StatAsset{
+					Name: link.Name,
+					URL:  link.URL,
+				})
+			}
+		}
+		tag.Name = release.Name
+		tag.Body = release.Description
+		tag.Assets = assets
+	}
+}
+
+func parseTag(tag *TagResponse) *model.Stat {
+	stat := &model.Stat{
+		Kind:  model.TagStat,
+		Value: tag.Name,
+	}
+	if tag.Commit != nil {
+		stat.Checksum = tag.Commit.ID
+		if at, err := time.Parse(time.RFC3339, tag.Commit.CreatedAt); err == nil {
+			stat.RecordedAt = field.Time(at)
+		} else {
+			logrus.Debugf("gitlab parse tag: created_at=%v", tag.Commit.CreatedAt)
+		}
+	}
+	return stat
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
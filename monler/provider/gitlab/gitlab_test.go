@@ -0,0 +1,178 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Frepo":
+			fmt.Fprint(w, `{
+  "id": 1,
+  "path_with_namespace": "group/repo",
+  "web_url": "http://`+r.Host+`/group/repo",
+  "default_branch": "main",
+  "star_count": 12,
+  "forks_count": 3,
+  "open_issues_count": 4,
+  "license": {"key": "MIT", "name": "MIT License"}
+}`)
+		case "/api/v4/projects/group%2Frepo/repository/tags":
+			switch r.URL.Query().Get("page") {
+			case "1":
+				w.Header().Set("X-Total", "3")
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[
+  {"name": "v1.0.1", "commit": {"id": "c2", "created_at": "2020-06-01T10:00:00.000+00:00"}},
+  {"name": "v1.1.0", "commit": {"id": "c3", "created_at": "2020-05-01T10:00:00.000+00:00"}}
+]`)
+			case "2":
+				w.Header().Set("X-Total", "3")
+				w.Header().Set("X-Next-Page", "")
+				fmt.Fprint(w, `[
+  {"name": "v1.2.0", "commit": {"id": "c1", "created_at": "2020-04-01T10:00:00.000+00:00"}}
+]`)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		case "/api/v4/projects/group%2Frepo/releases":
+			w.Header().Set("X-Total", "1")
+			fmt.Fprint(w, `[
+  {
+    "name": "Release 1.1.0",
+    "tag_name": "v1.1.0",
+    "description": "Notes",
+    "assets": {"links": [{"name": "repo.tar.gz", "url": "https://example.com/repo.tar.gz"}]}
+  }
+]`)
+		case "/api/v4/projects/group%2Frepo/repository/files/package.json/raw":
+			if r.URL.Query().Get("ref") != "main" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `{"name": "@group/repo"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newTestProvider(t *testing.T, srv *httptest.Server) *Provider {
+	u, _ := url.Parse(srv.URL)
+	p, err := NewProvider([]string{u.Host})
+	assert.Nil(t, err)
+	return p
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newTestProvider(t, srv)
+
+	assert.Nil(t, p.Ping(srv.URL+"/group/repo"))
+	assert.NotNil(t, p.Ping(srv.URL+"/group/not-exist"))
+	assert.NotNil(t, p.Ping("https://gitlab.com/group/repo"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newTestProvider(t, srv)
+
+	repo, err := p.Open(srv.URL + "/group/repo/-/tree/main")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	pu, err := report.URI()
+	assert.Nil(t, err)
+	assert.Equal(t, "group/repo", pu.URI)
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+	}
+	assert.Equal(t, map[model.StatKind]string{
+		model.StarCountStat:      "12",
+		model.ForkCountStat:      "3",
+		model.OpenIssueCountStat: "4",
+		model.LicenseStat:        "mit",
+	}, values)
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags = append(tags, tag)
+	}
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "v1.0.1", tags[0].Value)
+		assert.False(t, tags[0].IsLatest)
+		assert.Empty(t, tags[0].Body)
+		assert.Equal(t, "v1.1.0", tags[1].Value)
+		assert.Equal(t, "c3", tags[1].Checksum)
+		assert.False(t, tags[1].IsLatest)
+		assert.Equal(t, 2020, tags[1].RecordedAt.Time().Year())
+		assert.Equal(t, "Release 1.1.0", tags[1].Name)
+		assert.Equal(t, "Notes", tags[1].Body)
+		if assert.Len(t, tags[1].Assets, 1) {
+			assert.Equal(t, "https://example.com/repo.tar.gz", tags[1].Assets[0].URL)
+		}
+		// The greatest version is on the second page.
+		assert.Equal(t, "v1.2.0", tags[2].Value)
+		assert.True(t, tags[2].IsLatest)
+	}
+}
+
+func TestMarkLatest(t *testing.T) {
+	at := func(day int) field.Time {
+		return field.Time(time.Date(2020, 1, day, 0, 0, 0, 0, time.UTC))
+	}
+
+	// The tags which are not semver are compared by time.
+	nightly := &model.Stat{Value: "nightly", RecordedAt: at(3)}
+	stable := &model.Stat{Value: "stable", RecordedAt: at(1)}
+	latest := markLatest([]*model.Stat{stable, nightly}, nil)
+	assert.Equal(t, nightly, latest)
+	assert.True(t, nightly.IsLatest)
+	assert.False(t, stable.IsLatest)
+
+	// Semver wins over the newer tag which is not semver.
+	v1 := &model.Stat{Value: "v1.0.0", RecordedAt: at(2)}
+	latest = markLatest([]*model.Stat{v1}, latest)
+	assert.Equal(t, v1, latest)
+	assert.True(t, v1.IsLatest)
+	assert.False(t, nightly.IsLatest)
+
+	edge := &model.Stat{Value: "edge", RecordedAt: at(9)}
+	latest = markLatest([]*model.Stat{edge}, latest)
+	assert.Equal(t, v1, latest)
+	assert.False(t, edge.IsLatest)
+}
+
+func TestRepoDerived(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newTestProvider(t, srv)
+
+	repo, err := p.Open(srv.URL + "/group/repo")
+	assert.Nil(t, err)
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://www.npmjs.com/package/@group/repo"}, derived)
+}
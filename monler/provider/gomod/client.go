@@ -0,0 +1,110 @@
+package gomod
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+type Client struct {
+	client   *http.Client
+	proxyURL string
+}
+
+func (c *Client) get(dest string) (io.ReadCloser, error) {
+	resp, err := c.client.Get(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Proxy responds 410 to the modules which cannot be fetched.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		resp.Body.Close()
+		return nil, provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("gomod: proxy response got %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) getJSON(dest string, out interface{}) error {
+	body, err := c.get(dest)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(out)
+}
+
+func (c *Client) modulePath(module string) string {
+	return strings.TrimSuffix(c.proxyURL, "/") + "/" + EscapePath(module)
+}
+
+// List returns the known versions of module, excluding the
+// pseudo-versions.
+func (c *Client) List(module string) ([]string, error) {
+	body, err := c.get(c.modulePath(module) + "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0)
+	for _, line := range strings.Split(string(b), "\n") {
+		if v := strings.TrimSpace(line); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// Info returns the metadata of the module version.
+func (c *Client) Info(module, version string) (*InfoResponse, error) {
+	var out InfoResponse
+	dest := c.modulePath(module) + "/@v/" + EscapePath(version) + ".info"
+	if err := c.getJSON(dest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Latest returns the metadata of the latest version, which may be a
+// pseudo-version if module has no tagged release.
+func (c *Client) Latest(module string) (*InfoResponse, error) {
+	var out InfoResponse
+	if err := c.getJSON(c.modulePath(module)+"/@latest", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type InfoResponse struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// EscapePath replaces the upper-case letters with "!" followed by the
+// lower-case letter as required by the proxy protocol.
+func EscapePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package gomod
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+// Gomod settings.
+var (
+	DefaultProxyURL = pkgdata.GomodProxyURL
+)
+
+type Provider struct {
+	client *Client
+}
+
+// NewProvider creates provider which queries the module proxy at
+// proxyURL, which defaults to proxy.golang.org.
+func NewProvider(proxyURL string) (*Provider, error) {
+	if proxyURL == "" {
+		proxyURL = DefaultProxyURL
+	}
+	return &Provider{
+		client: &Client{
+			client:   &http.Client{},
+			proxyURL: proxyURL,
+		},
+	}, nil
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.GomodProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseGomod(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseGomod(rawurl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.client.Latest(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu     *pkguri.PkgURI
+	client *Client
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	latest, err := r.client.Latest(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("gomod:", err)
+		return nil, err
+	}
+	versions, err := r.client.List(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("gomod:", err)
+		return nil, err
+	}
+
+	// Module without tagged version is reported by its latest
+	// pseudo-version.
+	if len(versions) == 0 {
+		versions = []string{latest.Version}
+	}
+
+	tags := make([]*model.Stat, 0, len(versions))
+	for _, version := range versions {
+		var info *InfoResponse
+		if version == latest.Version {
+			info = latest
+		} else if info, err = r.client.Info(r.pu.URI, version); err != nil {
+			logrus.Debugf("gomod: info of %s@%s, %v", r.pu.URI, version, err)
+			return nil, err
+		}
+
+		tags = append(tags, &model.Stat{
+			Kind:       model.TagStat,
+			Value:      info.Version,
+			RecordedAt: field.Time(info.Time),
+			IsLatest:   info.Version == latest.Version,
+		})
+	}
+	sort.Sort(model.StatBySemver(tags))
+
+	return newReport(r.pu, []*model.Stat{}, tags)
+}
+
+// Derived returns the repository url of the modules hosted on github.
+func (r *Repo) Derived() ([]string, error) {
+	splits := strings.Split(r.pu.URI, "/")
+	if splits[0] != pkgdata.GithubHost || len(splits) < 3 {
+		return nil, nil
+	}
+	return []string{"https://" + strings.Join(splits[:3], "/")}, nil
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
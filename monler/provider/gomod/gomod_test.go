@@ -0,0 +1,149 @@
+package gomod
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	infos := map[string]string{
+		"v1.0.0":              "2020-01-01T10:00:00Z",
+		"v1.1.0-rc.1":         "2020-02-01T10:00:00Z",
+		"v1.1.0":              "2020-03-01T10:00:00Z",
+		"v2.0.0+incompatible": "2020-04-01T10:00:00Z",
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!owner/repo/@v/list":
+			fmt.Fprint(w, "v1.0.0\nv1.1.0\nv2.0.0+incompatible\nv1.1.0-rc.1\n")
+		case "/github.com/!owner/repo/@latest":
+			fmt.Fprint(w, `{"Version": "v2.0.0+incompatible", "Time": "2020-04-01T10:00:00Z"}`)
+		case "/example.com/untagged/@v/list":
+			fmt.Fprint(w, "")
+		case "/example.com/untagged/@latest":
+			fmt.Fprint(w, `{"Version": "v0.0.0-20200501100000-abcdef123456", "Time": "2020-05-01T10:00:00Z"}`)
+		default:
+			var version string
+			if n, _ := fmt.Sscanf(r.URL.Path, "/github.com/!owner/repo/@v/%s", &version); n == 1 {
+				version = version[:len(version)-len(".info")]
+				if at, has := infos[version]; has {
+					fmt.Fprintf(w, `{"Version": %q, "Time": %q}`, version, at)
+					return
+				}
+			}
+			http.Error(w, "not found", http.StatusGone)
+		}
+	}))
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p, err := NewProvider(srv.URL)
+	assert.Nil(t, err)
+
+	assert.Nil(t, p.Ping("https://pkg.go.dev/github.com/Owner/repo"))
+	assert.Nil(t, p.Ping("github.com/Owner/repo"))
+	assert.NotNil(t, p.Ping("https://pkg.go.dev/github.com/owner/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/Owner/repo"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p, err := NewProvider(srv.URL)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		rawurl string
+		values []string
+		latest string
+	}{
+		{
+			rawurl: "https://pkg.go.dev/github.com/Owner/repo@v1.0.0",
+			values: []string{"v1.0.0", "v1.1.0-rc.1", "v1.1.0", "v2.0.0+incompatible"},
+			latest: "v2.0.0+incompatible",
+		},
+		{
+			rawurl: "example.com/untagged",
+			values: []string{"v0.0.0-20200501100000-abcdef123456"},
+			latest: "v0.0.0-20200501100000-abcdef123456",
+		},
+	}
+
+	for _, test := range tests {
+		repo, err := p.Open(test.rawurl)
+		assert.Nil(t, err)
+		report, err := repo.Analyze()
+		if !assert.Nil(t, err) {
+			continue
+		}
+
+		values := []string{}
+		for report.Next() {
+			tag, err := report.Tag()
+			assert.Nil(t, err)
+			assert.Equal(t, model.TagStat, tag.Kind)
+			assert.False(t, tag.RecordedAt.Time().IsZero())
+			assert.Equal(t, test.latest == tag.Value, tag.IsLatest)
+			values = append(values, tag.Value)
+		}
+		assert.Equal(t, test.values, values)
+	}
+}
+
+func TestRepoDerived(t *testing.T) {
+	p, err := NewProvider("")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		rawurl string
+		expect []string
+	}{
+		{"github.com/Owner/repo/v2", []string{"https://github.com/Owner/repo"}},
+		{"golang.org/x/mod", nil},
+	}
+	for _, test := range tests {
+		repo, err := p.Open(test.rawurl)
+		assert.Nil(t, err)
+		derived, err := repo.Derived()
+		assert.Nil(t, err)
+		assert.Equal(t, test.expect, derived)
+	}
+}
+
+func TestStatBySemver(t *testing.T) {
+	expect := []string{
+		"v0.0.0-20191109021931-daa7c04131f5",
+		"v0.0.0-20200101120000-abcdef123456",
+		"v1.2.3",
+		"v1.2.4-0.20200101120000-abcdef123456",
+		"v1.2.4-pre",
+		"v1.2.4-pre.0.20200101120000-abcdef123456",
+		"v1.2.4",
+		"v1.10.0",
+		"v2.0.0",
+		"v2.0.0+incompatible",
+	}
+	stats := make([]*model.Stat, len(expect))
+	for i := range expect {
+		stats[len(expect)-i-1] = &model.Stat{Value: expect[i]}
+	}
+
+	sort.Sort(model.StatBySemver(stats))
+	for i := range stats {
+		assert.Equal(t, expect[i], stats[i].Value)
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	assert.Equal(t, "github.com/!burnt!sushi/toml", EscapePath("github.com/BurntSushi/toml"))
+	assert.Equal(t, "golang.org/x/mod", EscapePath("golang.org/x/mod"))
+}
@@ -0,0 +1,101 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"gopkg.in/yaml.v2"
+)
+
+// Helm api settings.
+var (
+	DefaultArtifacthubURL = "https://artifacthub.io"
+)
+
+type Client struct {
+	client         *http.Client
+	artifacthubURL string
+}
+
+func (c *Client) get(dest string) (io.ReadCloser, error) {
+	resp, err := c.client.Get(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("helm: response got %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// Package finds the chart from artifact hub.
+func (c *Client) Package(repo, chart string) (*PackageResponse, error) {
+	body, err := c.get(c.artifacthubURL + "/api/v1/packages/helm/" + repo + "/" + chart)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var out PackageResponse
+	if err := json.NewDecoder(body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Index downloads the index file of chart repository.
+func (c *Client) Index(indexURL string) (*IndexFile, error) {
+	body, err := c.get(indexURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var out IndexFile
+	if err := yaml.NewDecoder(body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type PackageResponse struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Repository struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"repository"`
+}
+
+type IndexFile struct {
+	APIVersion string                     `yaml:"apiVersion"`
+	Entries    map[string][]*ChartVersion `yaml:"entries"`
+}
+
+type ChartVersion struct {
+	Name        string            `yaml:"name"`
+	Version     string            `yaml:"version"`
+	AppVersion  string            `yaml:"appVersion"`
+	Created     time.Time         `yaml:"created"`
+	Digest      string            `yaml:"digest"`
+	Home        string            `yaml:"home"`
+	Sources     []string          `yaml:"sources"`
+	Deprecated  bool              `yaml:"deprecated"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// ChartImage is the item of "artifacthub.io/images" annotation.
+type ChartImage struct {
+	Name  string `yaml:"name"`
+	Image string `yaml:"image"`
+}
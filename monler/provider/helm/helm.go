@@ -0,0 +1,236 @@
+package helm
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// ImagesAnnotation is the chart annotation listing the container
+// images used by chart.
+const ImagesAnnotation = "artifacthub.io/images"
+
+type Provider struct {
+	client *Client
+}
+
+func NewProvider() (*Provider, error) {
+	return newProvider(DefaultArtifacthubURL), nil
+}
+
+func newProvider(artifacthubURL string) *Provider {
+	return &Provider{
+		client: &Client{
+			client:         &http.Client{},
+			artifacthubURL: artifacthubURL,
+		},
+	}
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.HelmProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseHelm(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseHelm(rawurl)
+	if err != nil {
+		return err
+	}
+
+	repo, err := newRepo(p.client, pu)
+	if err != nil {
+		return err
+	}
+	if _, err := repo.versions(); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu         *pkguri.PkgURI
+	client     *Client
+	lastLatest *ChartVersion
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+// indexURL returns the url of repository index. The repository of
+// artifact hub package is looked up from the api.
+func (r *Repo) indexURL() (string, error) {
+	if r.pu.Host != pkgdata.ArtifacthubHost {
+		return strings.SplitN(pkguri.ToURL(r.pu), "#", 2)[0], nil
+	}
+
+	pkg, err := r.client.Package(r.pu.Namespace(), r.pu.RepoName())
+	if err != nil {
+		return "", err
+	}
+	repoURL := pkg.Repository.URL
+	if !strings.HasPrefix(repoURL, "http://") && !strings.HasPrefix(repoURL, "https://") {
+		return "", provider.ErrNotSupport
+	}
+	return strings.TrimSuffix(repoURL, "/") + "/" + pkgdata.HelmIndexFile, nil
+}
+
+// versions lists the chart versions from repository index.
+func (r *Repo) versions() ([]*ChartVersion, error) {
+	indexURL, err := r.indexURL()
+	if err != nil {
+		return nil, err
+	}
+	index, err := r.client.Index(indexURL)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, has := index.Entries[r.pu.RepoName()]
+	if !has || len(versions) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return versions, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	versions, err := r.versions()
+	if err != nil {
+		logrus.Debugln("helm:", err)
+		return nil, err
+	}
+
+	tags := make([]*model.Stat, 0, len(versions))
+	for _, version := range versions {
+		substats := model.StatList{}
+		if version.AppVersion != "" {
+			substats = append(substats, &model.Stat{
+				Kind:       model.AppVersionStat,
+				Value:      version.AppVersion,
+				RecordedAt: field.Time(version.Created),
+			})
+		}
+
+		tags = append(tags, &model.Stat{
+			Kind:       model.TagStat,
+			Value:      version.Version,
+			Checksum:   version.Digest,
+			RecordedAt: field.Time(version.Created),
+			Substats:   &substats,
+		})
+	}
+	sort.Sort(model.StatBySemver(tags))
+
+	// The greatest stable version is the latest. Index lists the
+	// newest version first, which is used if none is stable.
+	r.lastLatest = versions[0]
+	for i := len(tags) - 1; i >= 0; i-- {
+		v, err := semver.NewVersion(tags[i].Value)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		tags[i].IsLatest = true
+		for _, version := range versions {
+			if version.Version == tags[i].Value {
+				r.lastLatest = version
+			}
+		}
+		break
+	}
+
+	return newReport(r.pu, []*model.Stat{}, tags)
+}
+
+// Derived returns the sources and the docker images of the latest
+// chart version.
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastLatest == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		latest  = r.lastLatest
+		derived = make([]string, 0)
+		seen    = make(map[string]bool)
+	)
+	add := func(rawurl string) {
+		if rawurl != "" && !seen[rawurl] {
+			seen[rawurl] = true
+			derived = append(derived, rawurl)
+		}
+	}
+
+	for _, source := range latest.Sources {
+		add(strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git"))
+	}
+	for _, image := range parseImages(latest.Annotations[ImagesAnnotation]) {
+		pu, err := pkguri.ParseDockerImage(image.Image)
+		if err != nil {
+			continue
+		}
+		add(pkguri.ToURL(pu))
+	}
+
+	return derived, nil
+}
+
+func parseImages(annotation string) []*ChartImage {
+	var images []*ChartImage
+	if err := yaml.Unmarshal([]byte(annotation), &images); err != nil {
+		logrus.Debugln("helm: images annotation", err)
+		return nil
+	}
+	return images
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
@@ -0,0 +1,84 @@
+package helm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/charts/", http.StripPrefix("/charts/", http.FileServer(http.Dir("testdata"))))
+	mux.HandleFunc("/api/v1/packages/helm/repo/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/packages/helm/repo/app" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"name": "app", "version": "1.1.0", "repository": {"name": "repo", "url": "http://%s/charts"}}`, r.Host)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	assert.Nil(t, p.Ping(srv.URL+"/charts/index.yaml#app"))
+	assert.Nil(t, p.Ping("https://artifacthub.io/packages/helm/repo/app"))
+	assert.NotNil(t, p.Ping(srv.URL+"/charts/index.yaml#not-exist"))
+	assert.NotNil(t, p.Ping("https://artifacthub.io/packages/helm/repo/not-exist"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	for _, rawurl := range []string{
+		srv.URL + "/charts/index.yaml#app",
+		"https://artifacthub.io/packages/helm/repo/app",
+	} {
+		repo, err := p.Open(rawurl)
+		assert.Nil(t, err)
+		report, err := repo.Analyze()
+		if !assert.Nil(t, err) {
+			continue
+		}
+
+		tags := make([]*model.Stat, 0)
+		for report.Next() {
+			tag, err := report.Tag()
+			assert.Nil(t, err)
+			tags = append(tags, tag)
+		}
+		if assert.Len(t, tags, 3) {
+			assert.Equal(t, "1.0.0", tags[0].Value)
+			assert.Equal(t, "d100", tags[0].Checksum)
+			assert.Equal(t, 5, int(tags[0].RecordedAt.Time().Month()))
+			assert.Equal(t, "1.1.0", tags[1].Value)
+			assert.True(t, tags[1].IsLatest)
+			assert.Equal(t, "2.0.0-rc.1", tags[2].Value)
+			assert.False(t, tags[2].IsLatest)
+
+			substats := *tags[1].Substats
+			if assert.Len(t, substats, 1) {
+				assert.Equal(t, model.AppVersionStat, substats[0].Kind)
+				assert.Equal(t, "1.19.0", substats[0].Value)
+			}
+		}
+
+		derived, err := repo.Derived()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"https://github.com/owner/app",
+			"https://github.com/owner/charts",
+			"https://hub.docker.com/r/owner/app",
+			"https://hub.docker.com/_/nginx",
+		}, derived)
+	}
+}
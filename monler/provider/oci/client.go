@@ -0,0 +1,287 @@
+package oci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// Media types of manifest.
+const (
+	MediaTypeOciIndex         = "application/vnd.oci.image.index.v1+json"
+	MediaTypeOciManifest      = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerList       = "application/vnd.docker.distribution.manifest.list.v2+json"
+	MediaTypeDockerManifest   = "application/vnd.docker.distribution.manifest.v2+json"
+	DockerContentDigestHeader = "Docker-Content-Digest"
+)
+
+// Errors.
+var (
+	ErrAuthChallenge = errors.New("oci: unsupported auth challenge")
+)
+
+var (
+	// PerPage is the page size of tag listing.
+	PerPage = 100
+
+	authParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	linkNextPattern  = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+)
+
+// Client talks to registry by the OCI distribution api. Bearer
+// token is requested on the auth challenge and reused afterwards.
+type Client struct {
+	client  *http.Client
+	baseURL string
+
+	mu    sync.Mutex
+	token string
+}
+
+func newClient(client *http.Client, proto, host string) *Client {
+	return &Client{
+		client:  client,
+		baseURL: proto + "://" + host,
+	}
+}
+
+func (c *Client) do(method, dest string, accept []string) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest(method, dest, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		c.mu.Lock()
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		c.mu.Unlock()
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authorize(challenge); err != nil {
+			return nil, err
+		}
+		if req, err = newRequest(); err != nil {
+			return nil, err
+		}
+		if resp, err = c.client.Do(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("oci: registry response got %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// authorize requests anonymous token from the realm of bearer
+// challenge.
+func (c *Client) authorize(challenge string) error {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return ErrAuthChallenge
+	}
+	params := make(map[string]string)
+	for _, match := range authParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return ErrAuthChallenge
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.client.Get(realm.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("oci: token response got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token = out.Token; c.token == "" {
+		c.token = out.AccessToken
+	}
+	return nil
+}
+
+func (c *Client) getJSON(dest string, accept []string, out interface{}) (*http.Response, error) {
+	resp, err := c.do("GET", dest, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return resp, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Tags lists all tags of repository following the pagination links.
+func (c *Client) Tags(name string) ([]string, error) {
+	dest := fmt.Sprintf("%s/v2/%s/tags/list?n=%d", c.baseURL, name, PerPage)
+	tags := make([]string, 0)
+	for dest != "" {
+		var out TagsResponse
+		resp, err := c.getJSON(dest, nil, &out)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, out.Tags...)
+
+		dest = ""
+		if match := linkNextPattern.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next, err := url.Parse(match[1])
+			if err != nil {
+				return nil, err
+			}
+			base, _ := url.Parse(c.baseURL)
+			dest = base.ResolveReference(next).String()
+		}
+	}
+	return tags, nil
+}
+
+// Manifest gets the manifest or index of reference, which is tag or
+// digest.
+func (c *Client) Manifest(name, reference string) (*ManifestResponse, error) {
+	dest := fmt.Sprintf("%s/v2/%s/manifests/%s", c.baseURL, name, reference)
+	accept := []string{MediaTypeOciIndex, MediaTypeDockerList, MediaTypeOciManifest, MediaTypeDockerManifest}
+
+	var out ManifestResponse
+	resp, err := c.getJSON(dest, accept, &out)
+	if err != nil {
+		return nil, err
+	}
+	out.Digest = resp.Header.Get(DockerContentDigestHeader)
+	if out.MediaType == "" {
+		out.MediaType = resp.Header.Get("Content-Type")
+	}
+	return &out, nil
+}
+
+// Config gets the image config blob.
+func (c *Client) Config(name, digest string) (*ConfigResponse, error) {
+	dest := fmt.Sprintf("%s/v2/%s/blobs/%s", c.baseURL, name, digest)
+
+	var out ConfigResponse
+	if _, err := c.getJSON(dest, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Ping checks the registry api.
+func (c *Client) Ping(name string) error {
+	resp, err := c.do("GET", fmt.Sprintf("%s/v2/%s/tags/list?n=1", c.baseURL, name), nil)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+type TagsResponse struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ManifestResponse covers both the image manifest and the index.
+type ManifestResponse struct {
+	Digest      string                `json:"-"`
+	MediaType   string                `json:"mediaType"`
+	Manifests   []*ManifestDescriptor `json:"manifests"`
+	Config      *Descriptor           `json:"config"`
+	Layers      []*Descriptor         `json:"layers"`
+	Annotations map[string]string     `json:"annotations"`
+}
+
+// IsIndex reports whether the response is an index of platform
+// manifests.
+func (m *ManifestResponse) IsIndex() bool {
+	return m.MediaType == MediaTypeOciIndex || m.MediaType == MediaTypeDockerList || len(m.Manifests) > 0
+}
+
+// size sums the size of config and layers.
+func (m *ManifestResponse) size() int64 {
+	var size int64
+	if m.Config != nil {
+		size += m.Config.Size
+	}
+	for _, layer := range m.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type ManifestDescriptor struct {
+	Descriptor
+	Platform *Platform `json:"platform"`
+}
+
+type Platform struct {
+	Architecture string   `json:"architecture"`
+	OS           string   `json:"os"`
+	OSVersion    string   `json:"os.version"`
+	OSFeatures   []string `json:"os.features"`
+	Variant      string   `json:"variant"`
+}
+
+type ConfigResponse struct {
+	Created      time.Time `json:"created"`
+	Architecture string    `json:"architecture"`
+	OS           string    `json:"os"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
@@ -0,0 +1,302 @@
+package oci
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/provider/docker"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+// Annotations and labels of image.
+const (
+	CreatedAnnotation = "org.opencontainers.image.created"
+	SourceAnnotation  = "org.opencontainers.image.source"
+)
+
+// OCI settings.
+var (
+	DefaultHosts = []string{pkgdata.GhcrHost, pkgdata.QuayHost}
+)
+
+type Provider struct {
+	hosts  []string
+	client *http.Client
+}
+
+// NewProvider creates provider of the registries at hosts, which
+// defaults to ghcr.io and quay.io. Registry of other host is reached
+// by "oci://" url.
+func NewProvider(hosts []string) (*Provider, error) {
+	if len(hosts) == 0 {
+		hosts = DefaultHosts
+	}
+	return &Provider{
+		hosts:  hosts,
+		client: &http.Client{},
+	}, nil
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.OciProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseOci(rawurl, p.hosts...)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseOci(rawurl, p.hosts...)
+	if err != nil {
+		return err
+	}
+
+	client := newClient(p.client, pu.Proto, pu.Host)
+	if err := client.Ping(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu         *pkguri.PkgURI
+	client     *Client
+	analyzed   bool
+	lastSource string
+}
+
+func newRepo(client *http.Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: newClient(client, pu.Proto, pu.Host),
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	names, err := r.client.Tags(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("oci:", err)
+		return nil, err
+	}
+
+	var (
+		rawtags = make([]*model.Stat, 0, len(names))
+		configs = make(map[string]*ConfigResponse)
+		sources = make(map[string]string)
+	)
+	for _, name := range names {
+		tag, source, err := r.parseTag(name, configs)
+		if err != nil {
+			logrus.Debugf("oci: tag %s, %v", name, err)
+			return nil, err
+		}
+		rawtags = append(rawtags, tag)
+		sources[name] = source
+	}
+
+	tags, err := docker.GroupTags(rawtags)
+	if err == docker.ErrNoLatestTag {
+		tags = rawtags
+		sort.Sort(docker.TagBySemver(tags))
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.analyzed = true
+	// Source of the latest tag is preferred.
+	r.lastSource = sources["latest"]
+	for _, tag := range tags {
+		if tag.IsLatest && sources[tag.Value] != "" {
+			r.lastSource = sources[tag.Value]
+		}
+	}
+	return newReport(r.pu, []*model.Stat{}, tags)
+}
+
+// parseTag creates tag with the manifests of each platform. Configs
+// are cached by digest as tags often share the same image.
+func (r *Repo) parseTag(name string, configs map[string]*ConfigResponse) (*model.Stat, string, error) {
+	manifest, err := r.client.Manifest(r.pu.URI, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	type platformManifest struct {
+		digest   string
+		size     int64
+		platform *Platform
+		config   *ConfigResponse
+	}
+	list := make([]*platformManifest, 0)
+	if manifest.IsIndex() {
+		for _, m := range manifest.Manifests {
+			// Skips the attestation manifests.
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			// Image size is unknown from index.
+			list = append(list, &platformManifest{digest: m.Digest, platform: m.Platform})
+		}
+	} else {
+		list = append(list, &platformManifest{digest: manifest.Digest, size: manifest.size()})
+	}
+
+	config := func(pm *platformManifest) (*ConfigResponse, error) {
+		if pm.config != nil {
+			return pm.config, nil
+		}
+		if cached, has := configs[pm.digest]; has {
+			return cached, nil
+		}
+		m := manifest
+		if manifest.IsIndex() {
+			if m, err = r.client.Manifest(r.pu.URI, pm.digest); err != nil {
+				return nil, err
+			}
+		}
+		if m.Config == nil {
+			return nil, provider.ErrNotFound
+		}
+		cfg, err := r.client.Config(r.pu.URI, m.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		configs[pm.digest] = cfg
+		return cfg, nil
+	}
+
+	var (
+		createdAt time.Time
+		source    = manifest.Annotations[SourceAnnotation]
+	)
+	if at, err := time.Parse(time.RFC3339, manifest.Annotations[CreatedAnnotation]); err == nil {
+		createdAt = at
+	}
+	// Single platform manifest needs config for its platform.
+	if len(list) > 0 && (createdAt.IsZero() || source == "" || list[0].platform == nil) {
+		cfg, err := config(list[0])
+		if err != nil {
+			return nil, "", err
+		}
+		if createdAt.IsZero() {
+			createdAt = cfg.Created
+		}
+		if source == "" {
+			source = cfg.Config.Labels[SourceAnnotation]
+		}
+		if list[0].platform == nil {
+			list[0].platform = &Platform{Architecture: cfg.Architecture, OS: cfg.OS}
+		}
+	}
+
+	recordedAt := field.Time(createdAt)
+	images := model.StatList{}
+	for _, pm := range list {
+		substats := model.StatList{
+			&model.Stat{
+				Name:       "architecture",
+				Value:      pm.platform.Architecture,
+				RecordedAt: recordedAt,
+			},
+			&model.Stat{
+				Name:       "variant",
+				Value:      pm.platform.Variant,
+				RecordedAt: recordedAt,
+			},
+			&model.Stat{
+				Name:       "os",
+				Value:      pm.platform.OS,
+				RecordedAt: recordedAt,
+			},
+			&model.Stat{
+				Name:       "os_features",
+				Value:      strings.Join(pm.platform.OSFeatures, ","),
+				RecordedAt: recordedAt,
+			},
+			&model.Stat{
+				Name:       "os_version",
+				Value:      pm.platform.OSVersion,
+				RecordedAt: recordedAt,
+			},
+		}
+		if pm.size > 0 {
+			substats = append(substats, &model.Stat{
+				Kind:       model.SizeStat,
+				Value:      strconv.FormatInt(pm.size, 10),
+				RecordedAt: recordedAt,
+			})
+		}
+
+		images = append(images, &model.Stat{
+			Kind:       model.ManifestStat,
+			RecordedAt: recordedAt,
+			Value:      pm.platform.OS + "/" + pm.platform.Architecture,
+			Checksum:   pm.digest,
+			Substats:   &substats,
+		})
+	}
+
+	return &model.Stat{
+		Kind:       model.TagStat,
+		RecordedAt: recordedAt,
+		Value:      name,
+		Substats:   &images,
+	}, source, nil
+}
+
+// Derived returns the source repository from the image labels.
+func (r *Repo) Derived() ([]string, error) {
+	if !r.analyzed {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	source := strings.TrimSuffix(strings.TrimSuffix(r.lastSource, "/"), ".git")
+	if source == "" {
+		return []string{}, nil
+	}
+	return []string{source}, nil
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
@@ -0,0 +1,187 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRegistry creates registry stand-in of repository "owner/app",
+// which requires token from the auth challenge.
+func newTestRegistry() *httptest.Server {
+	var (
+		tags      = []string{"1.0", "1.0.0", "1.1", "1.1.0", "edge", "latest"}
+		index11   = `{"mediaType": "` + MediaTypeOciIndex + `", "manifests": [
+  {"digest": "sha256:m11amd", "size": 500, "platform": {"architecture": "amd64", "os": "linux"}},
+  {"digest": "sha256:m11arm", "size": 500, "platform": {"architecture": "arm64", "os": "linux", "variant": "v8"}},
+  {"digest": "sha256:att", "size": 500, "platform": {"architecture": "unknown", "os": "unknown"}}
+]}`
+		manifests = map[string]string{
+			"1.1":           index11,
+			"1.1.0":         index11,
+			"latest":        index11,
+			"1.0":           `{"mediaType": "` + MediaTypeDockerManifest + `", "config": {"digest": "sha256:c10", "size": 10}, "layers": [{"digest": "sha256:l1", "size": 90}]}`,
+			"1.0.0":         `{"mediaType": "` + MediaTypeDockerManifest + `", "config": {"digest": "sha256:c10", "size": 10}, "layers": [{"digest": "sha256:l1", "size": 90}]}`,
+			"edge":          `{"mediaType": "` + MediaTypeOciManifest + `", "config": {"digest": "sha256:cedge", "size": 10}, "layers": []}`,
+			"sha256:m11amd": `{"mediaType": "` + MediaTypeOciManifest + `", "config": {"digest": "sha256:c11", "size": 10}, "layers": []}`,
+		}
+		digests = map[string]string{
+			"1.1": "sha256:idx11", "1.1.0": "sha256:idx11", "latest": "sha256:idx11",
+			"1.0": "sha256:m10", "1.0.0": "sha256:m10",
+			"edge": "sha256:medge", "sha256:m11amd": "sha256:m11amd",
+		}
+		configs = map[string]string{
+			"sha256:c10":   `{"created": "2020-01-01T10:00:00Z", "architecture": "amd64", "os": "linux", "config": {"Labels": {}}}`,
+			"sha256:c11":   `{"created": "2020-03-01T10:00:00Z", "architecture": "amd64", "os": "linux", "config": {"Labels": {"org.opencontainers.image.source": "https://github.com/owner/app.git"}}}`,
+			"sha256:cedge": `{"created": "2020-04-01T10:00:00Z", "architecture": "amd64", "os": "linux", "config": {"Labels": {}}}`,
+		}
+	)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:owner/app:pull" {
+				http.Error(w, "denied", http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry",scope="repository:owner/app:pull"`, r.Host))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		const prefix = "/v2/owner/app/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		switch path := strings.TrimPrefix(r.URL.Path, prefix); {
+		case path == "tags/list":
+			// Paginates by n and last.
+			n := len(tags)
+			fmt.Sscanf(r.URL.Query().Get("n"), "%d", &n)
+			start := 0
+			for i, tag := range tags {
+				if tag == r.URL.Query().Get("last") {
+					start = i + 1
+				}
+			}
+			end := start + n
+			if end < len(tags) {
+				next := url.Values{"n": {fmt.Sprint(n)}, "last": {tags[end-1]}}
+				w.Header().Set("Link", fmt.Sprintf(`</v2/owner/app/tags/list?%s>; rel="next"`, next.Encode()))
+			} else {
+				end = len(tags)
+			}
+			json.NewEncoder(w).Encode(TagsResponse{Name: "owner/app", Tags: tags[start:end]})
+		case strings.HasPrefix(path, "manifests/"):
+			ref := strings.TrimPrefix(path, "manifests/")
+			body, has := manifests[ref]
+			if !has {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set(DockerContentDigestHeader, digests[ref])
+			fmt.Fprint(w, body)
+		case strings.HasPrefix(path, "blobs/"):
+			body, has := configs[strings.TrimPrefix(path, "blobs/")]
+			if !has {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, body)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newTestProvider(t *testing.T, srv *httptest.Server) *Provider {
+	u, _ := url.Parse(srv.URL)
+	p, err := NewProvider([]string{u.Host})
+	assert.Nil(t, err)
+	return p
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestRegistry()
+	defer srv.Close()
+	p := newTestProvider(t, srv)
+
+	assert.Nil(t, p.Ping(srv.URL+"/owner/app"))
+	assert.NotNil(t, p.Ping(srv.URL+"/owner/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/owner/app"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestRegistry()
+	defer srv.Close()
+	p := newTestProvider(t, srv)
+
+	defer func(perPage int) { PerPage = perPage }(PerPage)
+	PerPage = 4
+
+	repo, err := p.Open(srv.URL + "/owner/app:1.0.0")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tags := make(map[string]*model.Stat)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags[tag.Value] = tag
+	}
+	aliases := func(tag *model.Stat) []string {
+		out := []string{}
+		for _, s := range (*tag.Substats).GetKind(model.AliasStat) {
+			out = append(out, s.Value)
+		}
+		return out
+	}
+
+	if assert.Contains(t, tags, "1.1.0") {
+		tag := tags["1.1.0"]
+		assert.Equal(t, model.TagStat, tag.Kind)
+		assert.True(t, tag.IsLatest)
+		assert.Equal(t, 3, int(tag.RecordedAt.Time().Month()))
+		manifests := (*tag.Substats).GetKind(model.ManifestStat)
+		if assert.Len(t, manifests, 2) {
+			assert.Equal(t, "linux/amd64", manifests[0].Value)
+			assert.Equal(t, "sha256:m11amd", manifests[0].Checksum)
+		}
+		assert.ElementsMatch(t, []string{"1.1", "latest"}, aliases(tag))
+	}
+	if assert.Contains(t, tags, "1.0.0") {
+		tag := tags["1.0.0"]
+		assert.False(t, tag.IsLatest)
+		manifests := (*tag.Substats).GetKind(model.ManifestStat)
+		if assert.Len(t, manifests, 1) {
+			assert.Equal(t, "linux/amd64", manifests[0].Value)
+			assert.Equal(t, "sha256:m10", manifests[0].Checksum)
+		}
+		assert.Equal(t, []string{"1.0"}, aliases(tag))
+	}
+	if assert.Contains(t, tags, "latest") {
+		assert.Equal(t, model.ChannelStat, tags["latest"].Kind)
+		assert.True(t, tags["latest"].IsLatest)
+	}
+	if assert.Contains(t, tags, "edge") {
+		assert.Equal(t, model.ChannelStat, tags["edge"].Kind)
+	}
+
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/owner/app"}, derived)
+}
@@ -0,0 +1,72 @@
+package packagist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// Packagist api settings.
+var (
+	DefaultRegistryURL = "https://packagist.org"
+)
+
+type Client struct {
+	client      *http.Client
+	registryURL string
+}
+
+func (c *Client) get(dest string, out interface{}) error {
+	resp, err := c.client.Get(dest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("packagist: api response got %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) Package(name string) (*PackageResponse, error) {
+	dest := c.registryURL + "/packages/" + name + ".json"
+
+	var out struct {
+		Package *PackageResponse `json:"package"`
+	}
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	if out.Package == nil {
+		return nil, provider.ErrNotFound
+	}
+	return out.Package, nil
+}
+
+type PackageResponse struct {
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+	Downloads  struct {
+		Total   uint64 `json:"total"`
+		Monthly uint64 `json:"monthly"`
+		Daily   uint64 `json:"daily"`
+	} `json:"downloads"`
+	Versions map[string]*VersionResponse `json:"versions"`
+}
+
+type VersionResponse struct {
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+	License []string  `json:"license"`
+	Source  struct {
+		URL       string `json:"url"`
+		Reference string `json:"reference"`
+	} `json:"source"`
+}
@@ -0,0 +1,172 @@
+package packagist
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+type Provider struct {
+	client *Client
+}
+
+func NewProvider() (*Provider, error) {
+	return newProvider(DefaultRegistryURL), nil
+}
+
+func newProvider(registryURL string) *Provider {
+	return &Provider{
+		client: &Client{
+			client:      &http.Client{},
+			registryURL: registryURL,
+		},
+	}
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.PackagistProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParsePackagist(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParsePackagist(rawurl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.client.Package(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu          *pkguri.PkgURI
+	client      *Client
+	lastPackage *PackageResponse
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	pkg, err := r.client.Package(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("packagist:", err)
+		return nil, err
+	}
+
+	now := field.Now()
+	stats := []*model.Stat{
+		{
+			Kind:       model.DownloadCountStat,
+			Value:      strconv.FormatUint(pkg.Downloads.Total, 10),
+			IsLatest:   true,
+			RecordedAt: now,
+		},
+	}
+
+	tags := make([]*model.Stat, 0, len(pkg.Versions))
+	for _, version := range pkg.Versions {
+		// Skips the branches.
+		if isDevVersion(version.Version) {
+			continue
+		}
+		tags = append(tags, &model.Stat{
+			Kind:       model.TagStat,
+			Value:      version.Version,
+			Checksum:   version.Source.Reference,
+			RecordedAt: field.Time(version.Time),
+		})
+	}
+	sort.Sort(model.StatBySemver(tags))
+
+	// The greatest stable version is the latest.
+	for i := len(tags) - 1; i >= 0; i-- {
+		v, err := semver.NewVersion(tags[i].Value)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		tags[i].IsLatest = true
+		if license := pkg.Versions[tags[i].Value].License; len(license) > 0 {
+			stats = append(stats, &model.Stat{
+				Kind:       model.LicenseStat,
+				Value:      strings.ToLower(strings.Join(license, ", ")),
+				IsLatest:   true,
+				RecordedAt: now,
+			})
+		}
+		break
+	}
+
+	r.lastPackage = pkg
+	return newReport(r.pu, stats, tags)
+}
+
+func isDevVersion(version string) bool {
+	return strings.HasPrefix(version, "dev-") || strings.HasSuffix(version, "-dev")
+}
+
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastPackage == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	repository := strings.TrimSuffix(strings.TrimSuffix(r.lastPackage.Repository, "/"), ".git")
+	if repository == "" {
+		return []string{}, nil
+	}
+	return []string{repository}, nil
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
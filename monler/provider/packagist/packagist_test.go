@@ -0,0 +1,85 @@
+package packagist
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/packages/vendor/sample.json":
+			fmt.Fprint(w, `{"package": {
+  "name": "vendor/sample",
+  "repository": "https://github.com/vendor/sample",
+  "downloads": {"total": 900, "monthly": 90, "daily": 3},
+  "versions": {
+    "dev-master": {"version": "dev-master", "time": "2020-05-01T10:00:00+00:00", "license": ["MIT"], "source": {"reference": "cdev"}},
+    "2.0.0-beta1": {"version": "2.0.0-beta1", "time": "2020-04-01T10:00:00+00:00", "license": ["MIT"], "source": {"reference": "c200b"}},
+    "v1.1.0": {"version": "v1.1.0", "time": "2020-03-01T10:00:00+00:00", "license": ["BSD-3-Clause"], "source": {"reference": "c110"}},
+    "v1.0.0": {"version": "v1.0.0", "time": "2020-01-01T10:00:00+00:00", "license": ["MIT"], "source": {"reference": "c100"}}
+  }
+}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	assert.Nil(t, p.Ping("https://packagist.org/packages/vendor/sample"))
+	assert.NotNil(t, p.Ping("https://packagist.org/packages/vendor/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/vendor/sample"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	repo, err := p.Open("https://packagist.org/packages/vendor/sample")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+	}
+	assert.Equal(t, map[model.StatKind]string{
+		model.DownloadCountStat: "900",
+		model.LicenseStat:       "bsd-3-clause",
+	}, values)
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags = append(tags, tag)
+	}
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "v1.0.0", tags[0].Value)
+		assert.Equal(t, "c100", tags[0].Checksum)
+		assert.Equal(t, "v1.1.0", tags[1].Value)
+		assert.True(t, tags[1].IsLatest)
+		assert.Equal(t, "2.0.0-beta1", tags[2].Value)
+		assert.False(t, tags[2].IsLatest)
+	}
+
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/vendor/sample"}, derived)
+}
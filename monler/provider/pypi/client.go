@@ -0,0 +1,87 @@
+package pypi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// Pypi api settings.
+var (
+	DefaultRegistryURL = "https://pypi.org"
+	DefaultStatsURL    = "https://pypistats.org"
+)
+
+type Client struct {
+	client      *http.Client
+	registryURL string
+	statsURL    string
+}
+
+func (c *Client) get(dest string, out interface{}) error {
+	resp, err := c.client.Get(dest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("pypi: api response got %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) Project(name string) (*ProjectResponse, error) {
+	dest := c.registryURL + "/pypi/" + name + "/json"
+
+	var out ProjectResponse
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) RecentDownloads(name string) (*RecentDownloadsResponse, error) {
+	dest := c.statsURL + "/api/packages/" + name + "/recent"
+
+	var out RecentDownloadsResponse
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type ProjectResponse struct {
+	Info     *ProjectInfo              `json:"info"`
+	Releases map[string][]*ReleaseFile `json:"releases"`
+}
+
+type ProjectInfo struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	License     string            `json:"license"`
+	HomePage    string            `json:"home_page"`
+	ProjectURLs map[string]string `json:"project_urls"`
+}
+
+type ReleaseFile struct {
+	Filename          string            `json:"filename"`
+	PackageType       string            `json:"packagetype"`
+	UploadTimeISO8601 string            `json:"upload_time_iso_8601"`
+	Yanked            bool              `json:"yanked"`
+	Digests           map[string]string `json:"digests"`
+}
+
+type RecentDownloadsResponse struct {
+	Data struct {
+		LastDay   uint64 `json:"last_day"`
+		LastWeek  uint64 `json:"last_week"`
+		LastMonth uint64 `json:"last_month"`
+	} `json:"data"`
+	Package string `json:"package"`
+}
@@ -0,0 +1,303 @@
+package pypi
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// sourceLabels are the project url labels of source repository.
+	sourceLabels = []string{"source", "source code", "sourcecode", "repository", "code", "github", "gitlab"}
+
+	// sourceHosts are the hosts of source repository.
+	sourceHosts = []string{pkgdata.GithubHost, pkgdata.GitlabHost, "bitbucket.org"}
+
+	// prereleasePattern matches the pre-release and development
+	// segments of PEP 440 version.
+	prereleasePattern = regexp.MustCompile(`(?i)\d[._-]?(a|alpha|b|beta|c|rc|pre|preview|dev)[._-]?\d*`)
+)
+
+type Provider struct {
+	client *Client
+}
+
+func NewProvider() (*Provider, error) {
+	return newProvider(DefaultRegistryURL, DefaultStatsURL), nil
+}
+
+func newProvider(registryURL, statsURL string) *Provider {
+	return &Provider{
+		client: &Client{
+			client:      &http.Client{},
+			registryURL: registryURL,
+			statsURL:    statsURL,
+		},
+	}
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.PypiProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParsePypi(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParsePypi(rawurl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.client.Project(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu          *pkguri.PkgURI
+	client      *Client
+	lastProject *ProjectResponse
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	project, err := r.client.Project(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("pypi:", err)
+		return nil, err
+	}
+
+	now := field.Now()
+	stats := make([]*model.Stat, 0)
+
+	// Download count is served by pypistats, which is optional.
+	if downloads, err := r.client.RecentDownloads(r.pu.URI); err == nil {
+		stats = append(stats, &model.Stat{
+			Kind:       model.DownloadCountStat,
+			Value:      strconv.FormatUint(downloads.Data.LastMonth, 10),
+			IsLatest:   true,
+			RecordedAt: now,
+		})
+	} else {
+		logrus.Debugln("pypi: download count", err)
+	}
+
+	if license := project.Info.License; license != "" && !strings.Contains(license, "\n") {
+		stats = append(stats, &model.Stat{
+			Kind:       model.LicenseStat,
+			Value:      strings.ToLower(license),
+			IsLatest:   true,
+			RecordedAt: now,
+		})
+	}
+
+	labels := make([]string, 0, len(project.Info.ProjectURLs))
+	for label := range project.Info.ProjectURLs {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	links := make(model.StatList, len(labels))
+	for i, label := range labels {
+		links[i] = &model.Stat{
+			Name:       label,
+			Value:      project.Info.ProjectURLs[label],
+			RecordedAt: now,
+		}
+	}
+	stats = append(stats, &model.Stat{
+		Kind:       model.LinkStat,
+		Value:      strconv.Itoa(len(links)),
+		IsLatest:   true,
+		RecordedAt: now,
+		Substats:   &links,
+	})
+
+	tags := model.StatList{}
+	for version, files := range project.Releases {
+		// Release without file has no upload time.
+		if len(files) == 0 {
+			continue
+		}
+		tags = append(tags, parseRelease(version, files, version == project.Info.Version))
+	}
+	sort.Sort(model.StatByRecordedAt(tags))
+
+	r.lastProject = project
+	return newReport(r.pu, stats, tags)
+}
+
+// parseRelease creates tag from the files of release. The earliest
+// upload time is used and the release is yanked if all files are.
+func parseRelease(version string, files []*ReleaseFile, isLatest bool) *model.Stat {
+	var (
+		recordedAt time.Time
+		yanked     = true
+	)
+	for _, file := range files {
+		at, err := time.Parse(time.RFC3339, file.UploadTimeISO8601)
+		if err != nil {
+			logrus.Debugf("pypi parse release: upload_time_iso_8601=%v", file.UploadTimeISO8601)
+		} else if recordedAt.IsZero() || at.Before(recordedAt) {
+			recordedAt = at
+		}
+		yanked = yanked && file.Yanked
+	}
+
+	substats := model.StatList{}
+	if isPrerelease(version) {
+		substats = append(substats, &model.Stat{
+			Name:       "prerelease",
+			Value:      "true",
+			RecordedAt: field.Time(recordedAt),
+		})
+	}
+	if yanked {
+		substats = append(substats, &model.Stat{
+			Name:       "yanked",
+			Value:      "true",
+			RecordedAt: field.Time(recordedAt),
+		})
+	}
+
+	return &model.Stat{
+		Kind:       model.TagStat,
+		Value:      version,
+		Checksum:   files[0].Digests["sha256"],
+		RecordedAt: field.Time(recordedAt),
+		IsLatest:   isLatest,
+		Substats:   &substats,
+	}
+}
+
+func isPrerelease(version string) bool {
+	// Ignores the local version label.
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	return prereleasePattern.MatchString(version)
+}
+
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastProject == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		info    = r.lastProject.Info
+		derived = make([]string, 0)
+		seen    = make(map[string]bool)
+	)
+	add := func(rawurl string) {
+		u, err := url.Parse(rawurl)
+		if err != nil || u.Host == "" {
+			return
+		}
+		u.Fragment = ""
+		u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+		if s := u.String(); !seen[s] {
+			seen[s] = true
+			derived = append(derived, s)
+		}
+	}
+
+	// Prefers the urls labelled as source, then falls back to the urls
+	// pointing at source hosting.
+	for label, rawurl := range info.ProjectURLs {
+		if isSourceLabel(label) {
+			add(rawurl)
+		}
+	}
+	if len(derived) == 0 {
+		for _, rawurl := range info.ProjectURLs {
+			if isSourceHost(rawurl) {
+				add(rawurl)
+			}
+		}
+	}
+	if len(derived) == 0 && isSourceHost(info.HomePage) {
+		add(info.HomePage)
+	}
+
+	sort.Strings(derived)
+	return derived, nil
+}
+
+func isSourceLabel(label string) bool {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, l := range sourceLabels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func isSourceHost(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	for _, h := range sourceHosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
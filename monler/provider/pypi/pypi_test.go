@@ -0,0 +1,157 @@
+package pypi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/pypi/sample-pkg/json":
+			fmt.Fprint(w, `{
+  "info": {
+    "name": "Sample_Pkg",
+    "version": "1.1.0",
+    "license": "MIT",
+    "home_page": "https://example.com",
+    "project_urls": {
+      "Homepage": "https://example.com",
+      "Source Code": "https://github.com/owner/sample.git",
+      "Tracker": "https://github.com/owner/sample/issues"
+    }
+  },
+  "releases": {
+    "1.0.0": [
+      {"filename": "sample-1.0.0.tar.gz", "packagetype": "sdist", "upload_time_iso_8601": "2020-01-02T10:00:00.000000Z", "yanked": false, "digests": {"sha256": "d100"}}
+    ],
+    "1.0.1": [
+      {"filename": "sample-1.0.1.tar.gz", "packagetype": "sdist", "upload_time_iso_8601": "2020-02-02T10:00:00.000000Z", "yanked": true, "digests": {"sha256": "d101"}}
+    ],
+    "1.1.0rc1": [
+      {"filename": "sample-1.1.0rc1.tar.gz", "packagetype": "sdist", "upload_time_iso_8601": "2020-03-02T10:00:00.000000Z", "yanked": false, "digests": {"sha256": "d110rc1"}}
+    ],
+    "1.1.0": [
+      {"filename": "sample-1.1.0-py3-none-any.whl", "packagetype": "bdist_wheel", "upload_time_iso_8601": "2020-04-02T12:00:00.000000Z", "yanked": false, "digests": {"sha256": "d110whl"}},
+      {"filename": "sample-1.1.0.tar.gz", "packagetype": "sdist", "upload_time_iso_8601": "2020-04-02T10:00:00.000000Z", "yanked": false, "digests": {"sha256": "d110"}}
+    ],
+    "0.0.1": []
+  }
+}`)
+		case "/api/packages/sample-pkg/recent":
+			fmt.Fprint(w, `{"data": {"last_day": 10, "last_week": 70, "last_month": 300}, "package": "sample-pkg", "type": "recent_downloads"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL, srv.URL)
+
+	assert.Nil(t, p.Ping("https://pypi.org/project/Sample_Pkg/"))
+	assert.NotNil(t, p.Ping("https://pypi.org/project/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/owner/sample"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL, srv.URL)
+
+	repo, err := p.Open("https://pypi.org/project/sample-pkg")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	var links *model.Stat
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+		if stat.Kind == model.LinkStat {
+			links = stat
+		}
+	}
+	assert.Equal(t, map[model.StatKind]string{
+		model.DownloadCountStat: "300",
+		model.LicenseStat:       "mit",
+		model.LinkStat:          "3",
+	}, values)
+	if assert.NotNil(t, links) {
+		substats := *links.Substats
+		assert.Equal(t, "Homepage", substats[0].Name)
+		assert.Equal(t, "https://example.com", substats[0].Value)
+	}
+
+	tags := make([]*model.Stat, 0)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags = append(tags, tag)
+	}
+	flags := func(tag *model.Stat) []string {
+		out := []string{}
+		for _, s := range *tag.Substats {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+	if assert.Len(t, tags, 4) {
+		assert.Equal(t, "1.0.0", tags[0].Value)
+		assert.Equal(t, "d100", tags[0].Checksum)
+		assert.False(t, tags[0].IsLatest)
+		assert.Equal(t, []string{}, flags(tags[0]))
+
+		assert.Equal(t, "1.0.1", tags[1].Value)
+		assert.Equal(t, []string{"yanked"}, flags(tags[1]))
+
+		assert.Equal(t, "1.1.0rc1", tags[2].Value)
+		assert.Equal(t, []string{"prerelease"}, flags(tags[2]))
+
+		assert.Equal(t, "1.1.0", tags[3].Value)
+		assert.True(t, tags[3].IsLatest)
+		assert.Equal(t, 10, tags[3].RecordedAt.Time().Hour())
+	}
+}
+
+func TestRepoDerived(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL, srv.URL)
+
+	repo, err := p.Open("https://pypi.org/project/sample-pkg")
+	assert.Nil(t, err)
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/owner/sample"}, derived)
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.0.0", false},
+		{"1.0.0.post1", false},
+		{"1.0.0a1", true},
+		{"1.0.0b2", true},
+		{"1.0.0rc1", true},
+		{"1.0.0.dev3", true},
+		{"1.0.0+local.dev1", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, isPrerelease(test.version), test.version)
+	}
+}
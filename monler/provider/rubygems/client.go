@@ -0,0 +1,74 @@
+package rubygems
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// Rubygems api settings.
+var (
+	DefaultRegistryURL = "https://rubygems.org"
+)
+
+type Client struct {
+	client      *http.Client
+	registryURL string
+}
+
+func (c *Client) get(dest string, out interface{}) error {
+	resp, err := c.client.Get(dest)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return provider.ErrNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("rubygems: api response got %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) Gem(name string) (*GemResponse, error) {
+	dest := c.registryURL + "/api/v1/gems/" + name + ".json"
+
+	var out GemResponse
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *Client) Versions(name string) ([]*VersionResponse, error) {
+	dest := c.registryURL + "/api/v1/versions/" + name + ".json"
+
+	var out []*VersionResponse
+	if err := c.get(dest, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type GemResponse struct {
+	Name          string   `json:"name"`
+	Downloads     uint64   `json:"downloads"`
+	Version       string   `json:"version"`
+	Licenses      []string `json:"licenses"`
+	HomepageURI   string   `json:"homepage_uri"`
+	SourceCodeURI string   `json:"source_code_uri"`
+}
+
+type VersionResponse struct {
+	Number         string    `json:"number"`
+	Platform       string    `json:"platform"`
+	CreatedAt      time.Time `json:"created_at"`
+	DownloadsCount uint64    `json:"downloads_count"`
+	Prerelease     bool      `json:"prerelease"`
+	SHA            string    `json:"sha"`
+}
@@ -0,0 +1,181 @@
+package rubygems
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/pinmonl/pinmonl/monler/prvdutils"
+	"github.com/pinmonl/pinmonl/pkgs/pkgdata"
+	"github.com/pinmonl/pinmonl/pkgs/pkguri"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultPlatform is the platform of pure ruby gem.
+const defaultPlatform = "ruby"
+
+type Provider struct {
+	client *Client
+}
+
+func NewProvider() (*Provider, error) {
+	return newProvider(DefaultRegistryURL), nil
+}
+
+func newProvider(registryURL string) *Provider {
+	return &Provider{
+		client: &Client{
+			client:      &http.Client{},
+			registryURL: registryURL,
+		},
+	}
+}
+
+func (p *Provider) ProviderName() string {
+	return pkgdata.RubygemsProvider
+}
+
+func (p *Provider) Open(rawurl string) (provider.Repo, error) {
+	pu, err := pkguri.ParseRubygems(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Parse(uri string) (provider.Repo, error) {
+	pu, err := pkguri.NewFromURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return newRepo(p.client, pu)
+}
+
+func (p *Provider) Ping(rawurl string) error {
+	pu, err := pkguri.ParseRubygems(rawurl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.client.Gem(pu.URI); err != nil {
+		return provider.ErrNotFound
+	}
+	return nil
+}
+
+type Repo struct {
+	pu      *pkguri.PkgURI
+	client  *Client
+	lastGem *GemResponse
+}
+
+func newRepo(client *Client, pu *pkguri.PkgURI) (*Repo, error) {
+	return &Repo{
+		pu:     pu,
+		client: client,
+	}, nil
+}
+
+func (r *Repo) Analyze() (provider.Report, error) {
+	return r.analyze()
+}
+
+func (r *Repo) analyze() (*Report, error) {
+	gem, err := r.client.Gem(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("rubygems:", err)
+		return nil, err
+	}
+	versions, err := r.client.Versions(r.pu.URI)
+	if err != nil {
+		logrus.Debugln("rubygems:", err)
+		return nil, err
+	}
+
+	now := field.Now()
+	stats := []*model.Stat{
+		{
+			Kind:       model.DownloadCountStat,
+			Value:      strconv.FormatUint(gem.Downloads, 10),
+			IsLatest:   true,
+			RecordedAt: now,
+		},
+	}
+	if len(gem.Licenses) > 0 {
+		stats = append(stats, &model.Stat{
+			Kind:       model.LicenseStat,
+			Value:      strings.ToLower(strings.Join(gem.Licenses, ", ")),
+			IsLatest:   true,
+			RecordedAt: now,
+		})
+	}
+
+	// Gem may be released for several platforms under the same
+	// version, the pure ruby one is preferred.
+	byNumber := make(map[string]*VersionResponse)
+	for _, version := range versions {
+		if found, has := byNumber[version.Number]; has && found.Platform == defaultPlatform {
+			continue
+		}
+		byNumber[version.Number] = version
+	}
+
+	tags := make([]*model.Stat, 0, len(byNumber))
+	for _, version := range byNumber {
+		tags = append(tags, &model.Stat{
+			Kind:       model.TagStat,
+			Value:      version.Number,
+			Checksum:   version.SHA,
+			RecordedAt: field.Time(version.CreatedAt),
+			IsLatest:   version.Number == gem.Version,
+		})
+	}
+	sort.Sort(model.StatBySemver(tags))
+
+	r.lastGem = gem
+	return newReport(r.pu, stats, tags)
+}
+
+// Derived returns the source code uri, or the homepage if it is
+// hosted on github.
+func (r *Repo) Derived() ([]string, error) {
+	if r.lastGem == nil {
+		if _, err := r.analyze(); err != nil {
+			return nil, err
+		}
+	}
+
+	source := r.lastGem.SourceCodeURI
+	if source == "" {
+		if u, err := url.Parse(r.lastGem.HomepageURI); err == nil && u.Host == pkgdata.GithubHost {
+			source = r.lastGem.HomepageURI
+		}
+	}
+	source = strings.TrimSuffix(strings.TrimSuffix(source, "/"), ".git")
+	if source == "" {
+		return []string{}, nil
+	}
+	return []string{source}, nil
+}
+
+func (r *Repo) Close() error {
+	return nil
+}
+
+type Report struct {
+	*prvdutils.StaticReport
+}
+
+func newReport(pu *pkguri.PkgURI, stats, tags []*model.Stat) (*Report, error) {
+	report := prvdutils.NewStaticReport(pu, stats, tags)
+	return &Report{report}, nil
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Repo = &Repo{}
+var _ provider.Report = &Report{}
@@ -0,0 +1,80 @@
+package rubygems
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/gems/sample.json":
+			fmt.Fprint(w, `{"name": "sample", "downloads": 5000, "version": "1.1.0", "licenses": ["MIT"], "homepage_uri": "https://github.com/owner/sample", "source_code_uri": null}`)
+		case "/api/v1/versions/sample.json":
+			fmt.Fprint(w, `[
+  {"number": "1.1.0", "platform": "java", "created_at": "2020-03-01T11:00:00.000Z", "prerelease": false, "sha": "j110"},
+  {"number": "1.1.0", "platform": "ruby", "created_at": "2020-03-01T10:00:00.000Z", "prerelease": false, "sha": "r110"},
+  {"number": "1.1.0.rc1", "platform": "ruby", "created_at": "2020-02-01T10:00:00.000Z", "prerelease": true, "sha": "r110rc1"},
+  {"number": "1.0.0", "platform": "ruby", "created_at": "2020-01-01T10:00:00.000Z", "prerelease": false, "sha": "r100"}
+]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestProviderPing(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	assert.Nil(t, p.Ping("https://rubygems.org/gems/sample"))
+	assert.NotNil(t, p.Ping("https://rubygems.org/gems/not-exist"))
+	assert.NotNil(t, p.Ping("https://github.com/owner/sample"))
+}
+
+func TestRepoAnalyze(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	p := newProvider(srv.URL)
+
+	repo, err := p.Open("https://rubygems.org/gems/sample/versions/1.0.0")
+	assert.Nil(t, err)
+	report, err := repo.Analyze()
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	stats, err := report.Stats()
+	assert.Nil(t, err)
+	values := make(map[model.StatKind]string)
+	for _, stat := range stats {
+		values[stat.Kind] = stat.Value
+	}
+	assert.Equal(t, map[model.StatKind]string{
+		model.DownloadCountStat: "5000",
+		model.LicenseStat:       "mit",
+	}, values)
+
+	tags := make(map[string]*model.Stat)
+	for report.Next() {
+		tag, err := report.Tag()
+		assert.Nil(t, err)
+		tags[tag.Value] = tag
+	}
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "r110", tags["1.1.0"].Checksum)
+		assert.True(t, tags["1.1.0"].IsLatest)
+		assert.False(t, tags["1.0.0"].IsLatest)
+		assert.Equal(t, "r110rc1", tags["1.1.0.rc1"].Checksum)
+	}
+
+	derived, err := repo.Derived()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/owner/sample"}, derived)
+}
@@ -0,0 +1,95 @@
+package pinmonl
+
+import "io"
+
+// pager follows the next cursor of responses. The page number is
+// followed instead until TotalCount is reached if the server returns
+// no cursor, e.g. the servers before cursor pagination.
+type pager struct {
+	fetched int64
+	done    bool
+}
+
+func (p *pager) advance(opts *ListOpts, next string, total int64, n int) {
+	p.fetched += int64(n)
+	switch {
+	case n == 0:
+		p.done = true
+	case next != "":
+		opts.Cursor = next
+	case opts.Cursor == "" && p.fetched < total:
+		if opts.Page < 1 {
+			opts.Page = 1
+		}
+		opts.Page++
+	default:
+		p.done = true
+	}
+}
+
+// PkgIterator lists the pkgs page by page by following the next cursor.
+type PkgIterator struct {
+	client *Client
+	opts   PkgListOpts
+	pager  pager
+}
+
+// PkgIterator returns the iterator of pkgs starting from opts.
+func (c *Client) PkgIterator(opts *PkgListOpts) *PkgIterator {
+	it := &PkgIterator{client: c}
+	if opts != nil {
+		it.opts = *opts
+	}
+	return it
+}
+
+// Next returns the pkgs of next page. io.EOF is returned if there is
+// no more page.
+func (it *PkgIterator) Next() ([]*Monpkg, error) {
+	if it.pager.done {
+		return nil, io.EOF
+	}
+	resp, err := it.client.PkgList(&it.opts)
+	if err != nil {
+		return nil, err
+	}
+	it.pager.advance(&it.opts.ListOpts, resp.Next, resp.TotalCount, len(resp.Data))
+	if len(resp.Data) == 0 {
+		return nil, io.EOF
+	}
+	return resp.Data, nil
+}
+
+// StatIterator lists the stats page by page by following the next
+// cursor.
+type StatIterator struct {
+	client *Client
+	opts   StatListOpts
+	pager  pager
+}
+
+// StatIterator returns the iterator of stats starting from opts.
+func (c *Client) StatIterator(opts *StatListOpts) *StatIterator {
+	it := &StatIterator{client: c}
+	if opts != nil {
+		it.opts = *opts
+	}
+	return it
+}
+
+// Next returns the stats of next page. io.EOF is returned if there is
+// no more page.
+func (it *StatIterator) Next() ([]*Stat, error) {
+	if it.pager.done {
+		return nil, io.EOF
+	}
+	resp, err := it.client.StatList(&it.opts)
+	if err != nil {
+		return nil, err
+	}
+	it.pager.advance(&it.opts.ListOpts, resp.Next, resp.TotalCount, len(resp.Data))
+	if len(resp.Data) == 0 {
+		return nil, io.EOF
+	}
+	return resp.Data, nil
+}
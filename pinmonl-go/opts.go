@@ -11,6 +11,9 @@ import (
 type ListOpts struct {
 	Page int
 	Size int
+
+	// Cursor is the next or prev cursor of the previous response.
+	Cursor string
 }
 
 func (l ListOpts) AppendTo(val url.Values) {
@@ -22,6 +25,9 @@ func (l ListOpts) AppendTo(val url.Values) {
 	} else if l.Size == -1 {
 		val.Add("page_size", "0")
 	}
+	if l.Cursor != "" {
+		val.Add("cursor", l.Cursor)
+	}
 }
 
 type StatListOpts struct {
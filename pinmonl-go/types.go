@@ -37,6 +37,7 @@ type (
 		ProviderHost  string     `json:"providerHost"`
 		ProviderURI   string     `json:"providerUri"`
 		ProviderProto string     `json:"providerProto"`
+		FetchedAt     field.Time `json:"fetchedAt"`
 		CreatedAt     field.Time `json:"createdAt"`
 		UpdatedAt     field.Time `json:"updatedAt"`
 	}
@@ -53,6 +54,8 @@ type (
 		TotalCount int64     `json:"totalCount"`
 		Page       int64     `json:"page"`
 		PageSize   int64     `json:"pageSize"`
+		Next       string    `json:"next"`
+		Prev       string    `json:"prev"`
 		Data       []*Monpkg `json:"data"`
 	}
 
@@ -68,14 +71,26 @@ type (
 		Weight      int           `json:"weight"`
 		IsLatest    bool          `json:"isLatest"`
 		HasChildren bool          `json:"hasChildren"`
+		Body        string        `json:"body"`
+		Assets      []*StatAsset  `json:"assets"`
 
 		Substats []*Stat `json:"substats"`
 	}
 
+	StatAsset struct {
+		Name          string `json:"name"`
+		URL           string `json:"url"`
+		ContentType   string `json:"contentType"`
+		Size          int64  `json:"size"`
+		DownloadCount int64  `json:"downloadCount"`
+	}
+
 	StatListResponse struct {
 		TotalCount int64   `json:"totalCount"`
 		Page       int64   `json:"page"`
 		PageSize   int64   `json:"pageSize"`
+		Next       string  `json:"next"`
+		Prev       string  `json:"prev"`
 		Data       []*Stat `json:"data"`
 	}
 
@@ -0,0 +1,115 @@
+package bookmark
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Bookmark is an entry parsed from a bookmark export.
+type Bookmark struct {
+	URL         string
+	Title       string
+	Description string
+	Folders     []string
+	Tags        []string
+	AddedAt     time.Time
+}
+
+// FolderPath returns the folder hierarchy as a tag name, e.g. "a/b/c".
+func (b *Bookmark) FolderPath() string {
+	return strings.Join(b.Folders, "/")
+}
+
+// TagNames returns the folder path followed by the tags specified
+// in the TAGS attribute.
+func (b *Bookmark) TagNames() []string {
+	var (
+		names = make([]string, 0)
+		seen  = make(map[string]bool)
+	)
+	for _, name := range append([]string{b.FolderPath()}, b.Tags...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// ParseNetscape parses the Netscape bookmark file format which is
+// exported by most of the browsers.
+func ParseNetscape(r io.Reader) ([]*Bookmark, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Bookmark, 0)
+	doc.Find("dl").First().Each(func(_ int, dl *goquery.Selection) {
+		list = parseNetscapeList(dl, nil, list)
+	})
+	return list, nil
+}
+
+func parseNetscapeList(dl *goquery.Selection, folders []string, list []*Bookmark) []*Bookmark {
+	dl.ChildrenFiltered("dt").Each(func(_ int, dt *goquery.Selection) {
+		if h3 := dt.ChildrenFiltered("h3").First(); h3.Length() > 0 {
+			sub := append(append([]string{}, folders...), folderName(h3.Text()))
+			children := dt.ChildrenFiltered("dl").First()
+			if children.Length() == 0 {
+				children = dt.NextFiltered("dl").First()
+			}
+			list = parseNetscapeList(children, sub, list)
+			return
+		}
+
+		a := dt.ChildrenFiltered("a").First()
+		if a.Length() == 0 {
+			return
+		}
+		href, _ := a.Attr("href")
+		bm := &Bookmark{
+			URL:     strings.TrimSpace(href),
+			Title:   strings.TrimSpace(a.Text()),
+			Folders: folders,
+			Tags:    parseTags(a.AttrOr("tags", "")),
+			AddedAt: parseTimestamp(a.AttrOr("add_date", "")),
+		}
+		if dd := dt.NextFiltered("dd").First(); dd.Length() > 0 {
+			bm.Description = strings.TrimSpace(dd.Text())
+		}
+		list = append(list, bm)
+	})
+	return list
+}
+
+// folderName escapes the path separator so that a folder always maps
+// to exactly one level of tag.
+func folderName(name string) string {
+	name = strings.TrimSpace(name)
+	return strings.ReplaceAll(name, "/", "-")
+}
+
+func parseTags(attr string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(attr, ",") {
+		tag = strings.Trim(strings.TrimSpace(tag), "/")
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func parseTimestamp(attr string) time.Time {
+	sec, err := strconv.ParseInt(attr, 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
@@ -0,0 +1,50 @@
+package bookmark
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const netscapeFixture = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3 ADD_DATE="1590000000">Dev</H3>
+    <DL><p>
+        <DT><H3>Go / Tools</H3>
+        <DL><p>
+            <DT><A HREF="https://github.com/go-chi/chi" ADD_DATE="1590000001" TAGS="router,http">chi</A>
+            <DD>Lightweight router
+        </DL><p>
+        <DT><A HREF="https://github.com/pinmonl/pinmonl">pinmonl</A>
+    </DL><p>
+    <DT><A HREF="https://example.com/">Example</A>
+    <DT><A HREF="javascript:void(0)">Bookmarklet</A>
+</DL><p>
+`
+
+func TestParseNetscape(t *testing.T) {
+	list, err := ParseNetscape(strings.NewReader(netscapeFixture))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(list))
+
+	assert.Equal(t, "https://github.com/go-chi/chi", list[0].URL)
+	assert.Equal(t, "chi", list[0].Title)
+	assert.Equal(t, "Lightweight router", list[0].Description)
+	assert.Equal(t, "Dev/Go - Tools", list[0].FolderPath())
+	assert.Equal(t, []string{"Dev/Go - Tools", "router", "http"}, list[0].TagNames())
+	assert.Equal(t, time.Unix(1590000001, 0), list[0].AddedAt)
+
+	assert.Equal(t, "https://github.com/pinmonl/pinmonl", list[1].URL)
+	assert.Equal(t, "", list[1].Description)
+	assert.Equal(t, []string{"Dev"}, list[1].TagNames())
+
+	assert.Equal(t, "https://example.com/", list[2].URL)
+	assert.Equal(t, []string{}, list[2].TagNames())
+
+	assert.Equal(t, "javascript:void(0)", list[3].URL)
+}
@@ -3,10 +3,15 @@ package card
 import (
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// MaxContentLength limits the bytes of page content.
+var MaxContentLength = 64 * 1024
+
 // Card scrapes the information of social media card.
 type Card struct {
 	HtmlTitle       string
@@ -87,6 +92,22 @@ func (c *Card) Description() string {
 	return c.MetaDescription
 }
 
+// Content retrieves the visible text of page body, which is truncated
+// to MaxContentLength.
+func (c *Card) Content() string {
+	body := c.Document.Find("body").Clone()
+	body.Find("script, style, noscript, template").Remove()
+	content := strings.Join(strings.Fields(body.Text()), " ")
+	if len(content) <= MaxContentLength {
+		return content
+	}
+	content = content[:MaxContentLength]
+	for !utf8.ValidString(content) {
+		content = content[:len(content)-1]
+	}
+	return content
+}
+
 // ImageURL retrieves the suitable card image url.
 func (c *Card) ImageURL() string {
 	if c.FacebookImageURL != "" {
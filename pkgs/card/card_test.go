@@ -1,6 +1,9 @@
 package card
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +52,26 @@ func TestNewCard(t *testing.T) {
 		}
 	}
 }
+
+func TestCardContent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Page</title><style>body {}</style></head>
+<body>
+  <h1>Hello</h1>
+  <script>var x = 1;</script>
+  <p>Full   text
+  search</p>
+</body></html>`)
+	}))
+	defer srv.Close()
+
+	c, err := NewCard(srv.URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "Hello Full text search", c.Content())
+
+	defer func(n int) { MaxContentLength = n }(MaxContentLength)
+	MaxContentLength = 7
+	assert.Equal(t, "Hello F", c.Content())
+}
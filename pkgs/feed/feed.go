@@ -0,0 +1,267 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Content types of the feed formats.
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	JSONContentType = "application/feed+json; charset=utf-8"
+)
+
+// Feed is the format independent representation of a feed.
+type Feed struct {
+	ID          string
+	Title       string
+	Link        string
+	FeedLink    string
+	Description string
+	Author      string
+	Updated     time.Time
+	Items       []*Item
+}
+
+// Item is an entry of the feed. Content is html.
+type Item struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Content    string
+	Author     string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+func (it *Item) updated() time.Time {
+	if !it.Updated.IsZero() {
+		return it.Updated
+	}
+	return it.Published
+}
+
+// Atom.
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"feed"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Author  *atomAuthor  `xml:"author,omitempty"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// WriteAtom encodes the feed in Atom 1.0.
+func WriteAtom(w io.Writer, f *Feed) error {
+	af := &atomFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: atomTime(f.Updated),
+		Links:   []atomLink{{Href: f.Link, Rel: "alternate"}},
+	}
+	if f.FeedLink != "" {
+		af.Links = append(af.Links, atomLink{Href: f.FeedLink, Rel: "self"})
+	}
+	if f.Author != "" {
+		af.Author = &atomAuthor{Name: f.Author}
+	}
+	for _, it := range f.Items {
+		ae := &atomEntry{
+			ID:        it.ID,
+			Title:     it.Title,
+			Updated:   atomTime(it.updated()),
+			Published: atomTime(it.Published),
+			Links:     []atomLink{{Href: it.Link, Rel: "alternate"}},
+		}
+		if it.Author != "" {
+			ae.Author = &atomAuthor{Name: it.Author}
+		}
+		for _, c := range it.Categories {
+			ae.Categories = append(ae.Categories, atomCategory{Term: c})
+		}
+		if it.Summary != "" {
+			ae.Summary = &atomText{Type: "text", Body: it.Summary}
+		}
+		if it.Content != "" {
+			ae.Content = &atomText{Type: "html", Body: it.Content}
+		}
+		af.Entries = append(af.Entries, ae)
+	}
+	return writeXML(w, af)
+}
+
+// RSS.
+
+type rssFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+func rssTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+// WriteRSS encodes the feed in RSS 2.0.
+func WriteRSS(w io.Writer, f *Feed) error {
+	ch := &rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		LastBuildDate: rssTime(f.Updated),
+	}
+	for _, it := range f.Items {
+		desc := it.Content
+		if desc == "" {
+			desc = it.Summary
+		}
+		ch.Items = append(ch.Items, &rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			Description: desc,
+			Author:      it.Author,
+			Categories:  it.Categories,
+			GUID:        rssGUID{Value: it.ID},
+			PubDate:     rssTime(it.updated()),
+		})
+	}
+	return writeXML(w, &rssFeed{Version: "2.0", Channel: ch})
+}
+
+// JSON Feed.
+
+type jsonFeed struct {
+	Version     string      `json:"version"`
+	Title       string      `json:"title"`
+	HomePageURL string      `json:"home_page_url,omitempty"`
+	FeedURL     string      `json:"feed_url,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Items       []*jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string      `json:"id"`
+	URL           string      `json:"url,omitempty"`
+	Title         string      `json:"title,omitempty"`
+	ContentHTML   string      `json:"content_html,omitempty"`
+	Summary       string      `json:"summary,omitempty"`
+	DatePublished string      `json:"date_published,omitempty"`
+	DateModified  string      `json:"date_modified,omitempty"`
+	Author        *jsonAuthor `json:"author,omitempty"`
+	Tags          []string    `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+// WriteJSON encodes the feed in JSON Feed 1.0.
+func WriteJSON(w io.Writer, f *Feed) error {
+	jf := &jsonFeed{
+		Version:     "https://jsonfeed.org/version/1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedLink,
+		Description: f.Description,
+		Items:       make([]*jsonItem, 0, len(f.Items)),
+	}
+	for _, it := range f.Items {
+		ji := &jsonItem{
+			ID:            it.ID,
+			URL:           it.Link,
+			Title:         it.Title,
+			ContentHTML:   it.Content,
+			Summary:       it.Summary,
+			DatePublished: atomTime(it.Published),
+			DateModified:  atomTime(it.Updated),
+			Tags:          it.Categories,
+		}
+		if it.Author != "" {
+			ji.Author = &jsonAuthor{Name: it.Author}
+		}
+		jf.Items = append(jf.Items, ji)
+	}
+	return json.NewEncoder(w).Encode(jf)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,97 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	return &Feed{
+		ID:       "urn:pinmonl:feed",
+		Title:    "Releases",
+		Link:     "http://localhost/",
+		FeedLink: "http://localhost/feed.atom",
+		Updated:  published,
+		Items: []*Item{
+			{
+				ID:         "urn:pinmonl:stat:1",
+				Title:      "pinmonl v1.0.0",
+				Link:       "https://github.com/pinmonl/pinmonl",
+				Content:    "<p>v1.0.0 & more</p>",
+				Categories: []string{"go"},
+				Published:  published,
+			},
+		},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteAtom(buf, testFeed()))
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+	var got struct {
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "Releases", got.Title)
+	assert.Equal(t, "2020-06-01T12:00:00Z", got.Updated)
+	if assert.Len(t, got.Entries, 1) {
+		assert.Equal(t, "urn:pinmonl:stat:1", got.Entries[0].ID)
+		assert.Equal(t, "2020-06-01T12:00:00Z", got.Entries[0].Updated)
+		assert.Equal(t, "<p>v1.0.0 & more</p>", got.Entries[0].Content)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteRSS(buf, testFeed()))
+
+	var got struct {
+		Version string `xml:"version,attr"`
+		Items   []struct {
+			GUID    string `xml:"guid"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+	assert.Nil(t, xml.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "2.0", got.Version)
+	if assert.Len(t, got.Items, 1) {
+		assert.Equal(t, "urn:pinmonl:stat:1", got.Items[0].GUID)
+		assert.Equal(t, "Mon, 01 Jun 2020 12:00:00 +0000", got.Items[0].PubDate)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, WriteJSON(buf, testFeed()))
+
+	var got map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "https://jsonfeed.org/version/1", got["version"])
+	items := got["items"].([]interface{})
+	if assert.Len(t, items, 1) {
+		item := items[0].(map[string]interface{})
+		assert.Equal(t, "urn:pinmonl:stat:1", item["id"])
+		assert.Equal(t, "2020-06-01T12:00:00Z", item["date_published"])
+		assert.Equal(t, []interface{}{"go"}, item["tags"])
+	}
+
+	// Items is an empty array when there is no item.
+	buf.Reset()
+	assert.Nil(t, WriteJSON(buf, &Feed{Title: "Empty"}))
+	assert.Contains(t, buf.String(), `"items":[]`)
+}
@@ -0,0 +1,63 @@
+// Package graceful stops the servers on signals and waits for the
+// running jobs.
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SignalContext returns the context which is canceled on
+// SIGINT or SIGTERM.
+func SignalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigs:
+			logrus.Infof("received %s, shutting down", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigs)
+	}()
+	return ctx, cancel
+}
+
+// Shutdown stops accepting requests and waits for the running jobs
+// until the timeout, then calls interrupt and waits for the jobs to
+// return. It reports whether the jobs are finished before the timeout.
+func Shutdown(srv *http.Server, wg *sync.WaitGroup, timeout time.Duration, interrupt func()) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logrus.Errorf("server: shutdown err(%v)", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		logrus.Info("server: shutdown completed")
+		return true
+	case <-ctx.Done():
+		logrus.Warn("server: shutdown timeout, running jobs are interrupted")
+		if interrupt != nil {
+			interrupt()
+		}
+		<-done
+		return false
+	}
+}
@@ -0,0 +1,31 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		wg.Done()
+	}()
+	assert.True(t, Shutdown(&http.Server{}, wg, time.Second, nil))
+
+	// The running jobs are interrupted on timeout and waited.
+	ctx, cancel := context.WithCancel(context.Background())
+	wg.Add(1)
+	go func() {
+		<-ctx.Done()
+		wg.Done()
+	}()
+	assert.False(t, Shutdown(&http.Server{}, wg, 10*time.Millisecond, cancel))
+	assert.NotNil(t, ctx.Err())
+}
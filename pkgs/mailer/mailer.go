@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+var ErrNoRecipient = errors.New("mailer: recipient is missing")
+
+// Mailer sends email through the SMTP server.
+type Mailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Message is an email with both plain text and html body.
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func (m *Mailer) addr() string {
+	return net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+}
+
+func (m *Mailer) auth() smtp.Auth {
+	if m.Username == "" {
+		return nil
+	}
+	return smtp.PlainAuth("", m.Username, m.Password, m.Host)
+}
+
+// Send delivers the message to the recipients.
+func (m *Mailer) Send(msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	body, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr(), m.auth(), m.From, msg.To, body)
+}
+
+// build encodes the message as multipart/alternative.
+func (m *Mailer) build(msg *Message) ([]byte, error) {
+	var (
+		buf = &bytes.Buffer{}
+		mw  = multipart.NewWriter(buf)
+	)
+
+	fmt.Fprintf(buf, "From: %s\r\n", m.From)
+	for _, to := range msg.To {
+		fmt.Fprintf(buf, "To: %s\r\n", to)
+	}
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
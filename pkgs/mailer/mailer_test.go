@@ -0,0 +1,130 @@
+package mailer
+
+import (
+	"bufio"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpStub accepts one message and reports the envelope and data.
+type smtpStub struct {
+	ln   net.Listener
+	from string
+	to   []string
+	data chan string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	s := &smtpStub{ln: ln, data: make(chan string, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *smtpStub) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimPrefix(cmd, "MAIL FROM:"), "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(strings.TrimPrefix(cmd, "RCPT TO:"), "<>"))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			s.data <- sb.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	stub := newSMTPStub(t)
+	defer stub.ln.Close()
+
+	m := &Mailer{
+		Host: "127.0.0.1",
+		Port: stub.port(),
+		From: "pinmonl@localhost",
+	}
+	err := m.Send(&Message{
+		To:      []string{"user@example.com"},
+		Subject: "New releases",
+		Text:    "v1.0.0",
+		HTML:    "<p>v1.0.0</p>",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "pinmonl@localhost", stub.from)
+	assert.Equal(t, []string{"user@example.com"}, stub.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(<-stub.data))
+	assert.Nil(t, err)
+	assert.Equal(t, "New releases", msg.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	got := make(map[string]string)
+	for {
+		p, err := mr.NextRawPart()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(quotedprintable.NewReader(p))
+		got[strings.Split(p.Header.Get("Content-Type"), ";")[0]] = string(b)
+	}
+	assert.Equal(t, "v1.0.0", got["text/plain"])
+	assert.Equal(t, "<p>v1.0.0</p>", got["text/html"])
+}
+
+func TestSendNoRecipient(t *testing.T) {
+	m := &Mailer{Host: "127.0.0.1", Port: 25}
+	assert.Equal(t, ErrNoRecipient, m.Send(&Message{}))
+}
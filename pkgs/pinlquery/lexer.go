@@ -0,0 +1,129 @@
+package pinlquery
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenWord
+	tokenPhrase
+)
+
+type token struct {
+	kind  tokenKind
+	key   string
+	value string
+	raw   string
+}
+
+var errUnterminatedQuote = errors.New("pinlquery: unterminated quote")
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func (l *lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+// readQuoted reads until the closing quote. The opening quote must
+// be consumed already.
+func (l *lexer) readQuoted() (string, error) {
+	var sb strings.Builder
+	for l.pos < len(l.input) {
+		r := l.input[l.pos]
+		l.pos++
+		if r == '"' {
+			return sb.String(), nil
+		}
+		sb.WriteRune(r)
+	}
+	return "", errUnterminatedQuote
+}
+
+func isWordEnd(r rune) bool {
+	return r == 0 || r == '(' || r == ')' || unicode.IsSpace(r)
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	start := l.pos
+	switch r := l.peek(); r {
+	case 0:
+		return token{kind: tokenEOF}, nil
+	case '(':
+		l.pos++
+		return token{kind: tokenLParen, raw: "("}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokenRParen, raw: ")"}, nil
+	case '-':
+		l.pos++
+		if next := l.peek(); next == '(' || !isWordEnd(next) {
+			return token{kind: tokenNot, raw: "-"}, nil
+		}
+		l.pos = start
+	case '"':
+		l.pos++
+		text, err := l.readQuoted()
+		if err != nil {
+			return token{}, err
+		}
+		return token{kind: tokenPhrase, value: text, raw: string(l.input[start:l.pos])}, nil
+	}
+
+	var sb strings.Builder
+	key := ""
+	for !isWordEnd(l.peek()) {
+		r := l.peek()
+		l.pos++
+		if r == ':' && key == "" && sb.Len() > 0 {
+			key = sb.String()
+			sb.Reset()
+			if l.peek() == '"' {
+				l.pos++
+				value, err := l.readQuoted()
+				if err != nil {
+					return token{}, err
+				}
+				sb.WriteString(value)
+				break
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	raw := string(l.input[start:l.pos])
+
+	if key == "" {
+		switch raw {
+		case "AND":
+			return token{kind: tokenAnd, raw: raw}, nil
+		case "OR":
+			return token{kind: tokenOr, raw: raw}, nil
+		case "NOT":
+			return token{kind: tokenNot, raw: raw}, nil
+		}
+	}
+	return token{kind: tokenWord, key: strings.ToLower(key), value: sb.String(), raw: raw}, nil
+}
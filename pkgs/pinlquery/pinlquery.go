@@ -0,0 +1,274 @@
+// Package pinlquery parses the search query of pinls, e.g.
+//
+//	tag:lang/go -tag:archived provider:npm has:release released:<30d
+//	stars:>1000 url:github.com "exact phrase" (docker OR k8s)
+//
+// Terms are joined by AND implicitly. OR, NOT and "-" negate or
+// combine the terms, and parentheses group them. Unknown keys are
+// searched as text.
+package pinlquery
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/tagutils"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// Query is the parsed search query.
+type Query struct {
+	// Text is the words required by the query, which are searched
+	// with ranking.
+	Text string
+
+	// Filter is the rest of the query. Nil if the query has text
+	// only.
+	Filter store.PinlFilter
+}
+
+// StatKeys maps the keys of query to the stat kinds compared by
+// number.
+var StatKeys = map[string]model.StatKind{
+	"stars":       model.StarCountStat,
+	"forks":       model.ForkCountStat,
+	"downloads":   model.DownloadCountStat,
+	"watchers":    model.WatcherCountStat,
+	"subscribers": model.SubscriberCountStat,
+	"issues":      model.OpenIssueCountStat,
+}
+
+var timeNow = time.Now
+
+var (
+	ageRegex  = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+	opRegex   = regexp.MustCompile(`^(<=|>=|<|>|=)?(.*)$`)
+	ageUnits  = map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "m": 30 * 24 * time.Hour, "y": 365 * 24 * time.Hour}
+	ageOps    = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<=", "=": ">=", "": ">="}
+	errSyntax = errors.New("pinlquery: syntax error")
+)
+
+// Parse parses the search query.
+func Parse(input string) (*Query, error) {
+	p := &parser{lexer: &lexer{input: []rune(input)}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenEOF {
+		return &Query{}, nil
+	}
+
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, fmt.Errorf("%w: unexpected %q", errSyntax, p.tok.raw)
+	}
+	return split(filter), nil
+}
+
+// split moves the required words to Text.
+func split(filter store.PinlFilter) *Query {
+	var filters []store.PinlFilter
+	if and, ok := filter.(store.PinlFilterAnd); ok {
+		filters = and
+	} else {
+		filters = []store.PinlFilter{filter}
+	}
+
+	var (
+		words []string
+		rest  store.PinlFilterAnd
+	)
+	for _, f := range filters {
+		if text, ok := f.(store.PinlFilterText); ok && !text.Phrase {
+			words = append(words, text.Text)
+			continue
+		}
+		rest = append(rest, f)
+	}
+
+	query := &Query{Text: strings.Join(words, " ")}
+	switch len(rest) {
+	case 0:
+	case 1:
+		query.Filter = rest[0]
+	default:
+		query.Filter = rest
+	}
+	return query
+}
+
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) parseOr() (store.PinlFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := store.PinlFilterOr{left}
+	for p.tok.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, right)
+	}
+	if len(or) == 1 {
+		return left, nil
+	}
+	return or, nil
+}
+
+func (p *parser) parseAnd() (store.PinlFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	and := store.PinlFilterAnd{left}
+	for {
+		switch p.tok.kind {
+		case tokenAnd:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case tokenEOF, tokenOr, tokenRParen:
+			if len(and) == 1 {
+				return left, nil
+			}
+			return and, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, right)
+	}
+}
+
+func (p *parser) parseUnary() (store.PinlFilter, error) {
+	switch p.tok.kind {
+	case tokenNot:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		filter, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return store.PinlFilterNot{Filter: filter}, nil
+	case tokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRParen {
+			return nil, fmt.Errorf("%w: missing \")\"", errSyntax)
+		}
+		return filter, p.advance()
+	case tokenPhrase:
+		filter := store.PinlFilterText{Text: p.tok.value, Phrase: true}
+		return filter, p.advance()
+	case tokenWord:
+		filter, err := parseTerm(p.tok)
+		if err != nil {
+			return nil, err
+		}
+		return filter, p.advance()
+	case tokenEOF:
+		return nil, fmt.Errorf("%w: unexpected end of query", errSyntax)
+	}
+	return nil, fmt.Errorf("%w: unexpected %q", errSyntax, p.tok.raw)
+}
+
+func parseTerm(tok token) (store.PinlFilter, error) {
+	if tok.key != "" && tok.value == "" {
+		return nil, fmt.Errorf("%w: missing value of %q", errSyntax, tok.key)
+	}
+
+	switch tok.key {
+	case "":
+		return store.PinlFilterText{Text: tok.value}, nil
+	case "tag":
+		return store.PinlFilterTag{NamePattern: tagutils.ToNamePattern(tok.value)}, nil
+	case "url":
+		return store.PinlFilterURL{URL: tok.value}, nil
+	case "provider":
+		return store.PinlFilterProvider{Provider: strings.ToLower(tok.value)}, nil
+	case "has":
+		return store.PinlFilterHas{What: strings.ToLower(tok.value)}, nil
+	case "released":
+		return parseReleased(tok.value)
+	}
+	if kind, ok := StatKeys[tok.key]; ok {
+		op, value := splitOp(tok.value)
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q of %q", errSyntax, value, tok.key)
+		}
+		if op == "" {
+			op = "="
+		}
+		return store.PinlFilterStat{Kind: kind, Op: op, Value: n}, nil
+	}
+	return store.PinlFilterText{Text: tok.raw}, nil
+}
+
+// parseReleased parses the age, e.g. "<30d", or the date, e.g.
+// ">=2020-01-01", of latest release. Age without operator is the
+// same as "<", i.e. released in the age.
+func parseReleased(input string) (store.PinlFilter, error) {
+	op, value := splitOp(input)
+
+	if m := ageRegex.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		t := timeNow().Add(-time.Duration(n) * ageUnits[m[2]])
+		return store.PinlFilterReleased{Op: ageOps[op], Time: t}, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q of \"released\"", errSyntax, value)
+	}
+	switch op {
+	case "", "=":
+		return store.PinlFilterAnd{
+			store.PinlFilterReleased{Op: ">=", Time: date},
+			store.PinlFilterReleased{Op: "<", Time: date.AddDate(0, 0, 1)},
+		}, nil
+	case ">":
+		return store.PinlFilterReleased{Op: ">=", Time: date.AddDate(0, 0, 1)}, nil
+	case "<=":
+		return store.PinlFilterReleased{Op: "<", Time: date.AddDate(0, 0, 1)}, nil
+	}
+	return store.PinlFilterReleased{Op: op, Time: date}, nil
+}
+
+func splitOp(input string) (string, string) {
+	m := opRegex.FindStringSubmatch(input)
+	return m[1], m[2]
+}
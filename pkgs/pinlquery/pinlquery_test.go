@@ -0,0 +1,114 @@
+package pinlquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input  string
+		expect *Query
+		hasErr bool
+	}{
+		{
+			input:  "",
+			expect: &Query{},
+		},
+		{
+			input:  "docker  compose",
+			expect: &Query{Text: "docker compose"},
+		},
+		{
+			input: `tag:lang/go -tag:archived provider:npm has:release released:<30d stars:>1000 url:github.com "exact phrase"`,
+			expect: &Query{
+				Filter: store.PinlFilterAnd{
+					store.PinlFilterTag{NamePattern: "%lang/go%"},
+					store.PinlFilterNot{Filter: store.PinlFilterTag{NamePattern: "%archived%"}},
+					store.PinlFilterProvider{Provider: "npm"},
+					store.PinlFilterHas{What: "release"},
+					store.PinlFilterReleased{Op: ">", Time: now.AddDate(0, 0, -30)},
+					store.PinlFilterStat{Kind: model.StarCountStat, Op: ">", Value: 1000},
+					store.PinlFilterURL{URL: "github.com"},
+					store.PinlFilterText{Text: "exact phrase", Phrase: true},
+				},
+			},
+		},
+		{
+			input: "kubernetes tag:/devops/ (helm OR NOT provider:docker)",
+			expect: &Query{
+				Text: "kubernetes",
+				Filter: store.PinlFilterAnd{
+					store.PinlFilterTag{NamePattern: "devops"},
+					store.PinlFilterOr{
+						store.PinlFilterText{Text: "helm"},
+						store.PinlFilterNot{Filter: store.PinlFilterProvider{Provider: "docker"}},
+					},
+				},
+			},
+		},
+		{
+			input: "a OR b AND c",
+			expect: &Query{
+				Filter: store.PinlFilterOr{
+					store.PinlFilterText{Text: "a"},
+					store.PinlFilterAnd{
+						store.PinlFilterText{Text: "b"},
+						store.PinlFilterText{Text: "c"},
+					},
+				},
+			},
+		},
+		{
+			input: `-(tag:"my tag" OR released:>2020-01-01) forks:<=10`,
+			expect: &Query{
+				Filter: store.PinlFilterAnd{
+					store.PinlFilterNot{Filter: store.PinlFilterOr{
+						store.PinlFilterTag{NamePattern: "%my tag%"},
+						store.PinlFilterReleased{Op: ">=", Time: date.AddDate(0, 0, 1)},
+					}},
+					store.PinlFilterStat{Kind: model.ForkCountStat, Op: "<=", Value: 10},
+				},
+			},
+		},
+		{
+			input: "released:2020-01-01",
+			expect: &Query{
+				Filter: store.PinlFilterAnd{
+					store.PinlFilterReleased{Op: ">=", Time: date},
+					store.PinlFilterReleased{Op: "<", Time: date.AddDate(0, 0, 1)},
+				},
+			},
+		},
+		{
+			input:  "https://github.com/pinmonl - or",
+			expect: &Query{Text: "https://github.com/pinmonl - or"},
+		},
+		{input: "(tag:go", hasErr: true},
+		{input: "tag:go)", hasErr: true},
+		{input: "tag:go OR", hasErr: true},
+		{input: "tag:", hasErr: true},
+		{input: "stars:many", hasErr: true},
+		{input: "released:yesterday", hasErr: true},
+		{input: `"exact phrase`, hasErr: true},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if test.hasErr {
+			assert.NotNil(t, err, test.input)
+			continue
+		}
+		assert.Nil(t, err, test.input)
+		assert.Equal(t, test.expect, got, test.input)
+	}
+}
@@ -1,6 +1,9 @@
 package pinlutils
 
-import "net/url"
+import (
+	"net/url"
+	"strings"
+)
 
 func IsValidURL(rawurl string) bool {
 	u, err := url.Parse(rawurl)
@@ -9,3 +12,13 @@ func IsValidURL(rawurl string) bool {
 	}
 	return u.Scheme != "" && u.Host != ""
 }
+
+// Host returns the lower-cased host name of url without port. Empty
+// string is returned if url is invalid.
+func Host(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -14,9 +14,53 @@ const (
 	GithubProvider = "github"
 	GithubHost     = "github.com"
 
+	// Gitlab.
+	GitlabProvider = "gitlab"
+	GitlabHost     = "gitlab.com"
+
+	// Pypi.
+	PypiProvider = "pypi"
+	PypiHost     = "pypi.org"
+	PypiPrefix   = "project"
+
+	// Go module.
+	GomodProvider = "gomod"
+	GomodHost     = "pkg.go.dev"
+	GomodPrefix   = "mod"
+	GomodProxyURL = "https://proxy.golang.org"
+
+	// Crates.
+	CratesProvider = "crates"
+	CratesHost     = "crates.io"
+	CratesPrefix   = "crates"
+
+	// Rubygems.
+	RubygemsProvider = "rubygems"
+	RubygemsHost     = "rubygems.org"
+	RubygemsPrefix   = "gems"
+
+	// Packagist.
+	PackagistProvider = "packagist"
+	PackagistHost     = "packagist.org"
+	PackagistPrefix   = "packages"
+
+	// Helm.
+	HelmProvider      = "helm"
+	HelmIndexFile     = "index.yaml"
+	ArtifacthubHost   = "artifacthub.io"
+	ArtifacthubPrefix = "packages/helm"
+
+	// OCI registry.
+	OciProvider = "oci"
+	OciScheme   = "oci"
+	GhcrHost    = "ghcr.io"
+	QuayHost    = "quay.io"
+	QuayPrefix  = "repository"
+
 	// Docker.
-	DockerProvider = "docker"
-	DockerHost     = "hub.docker.com"
+	DockerProvider     = "docker"
+	DockerHost         = "hub.docker.com"
+	DockerRegistryHost = "docker.io"
 
 	// Youtube
 	YoutubeProvider = "youtube"
@@ -232,6 +232,49 @@ func ParseGithub(rawurl string) (*PkgURI, error) {
 	}, nil
 }
 
+// ParseGitlab parses gitlab project url to pkguri. The url host
+// must be one of hosts, which defaults to gitlab.com.
+func ParseGitlab(rawurl string, hosts ...string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		hosts = []string{pkgdata.GitlabHost}
+	}
+	host := u.Host
+	if host == "" {
+		host = hosts[0]
+	}
+	matched := false
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, ErrHost
+	}
+
+	// Removes the sub-page of project, e.g. "/-/tree/master".
+	path := u.Path
+	if i := strings.Index(path, "/-/"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimSuffix(sanitizePath(path), ".git")
+	if len(strings.Split(path, "/")) < 2 {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.GitlabProvider,
+		Host:     strings.ToLower(host),
+		URI:      path,
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
 // ParseNpm parses npm package url to pkguri.
 func ParseNpm(rawurl string) (*PkgURI, error) {
 	u, err := url.Parse(rawurl)
@@ -258,6 +301,155 @@ func ParseNpm(rawurl string) (*PkgURI, error) {
 	}, nil
 }
 
+// ParsePypi parses pypi project url to pkguri. The project name
+// is normalized as PEP 503.
+func ParsePypi(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != pkgdata.PypiHost {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if !strings.HasPrefix(path, pkgdata.PypiPrefix+"/") {
+		return nil, ErrPath
+	}
+	splits := strings.SplitN(strings.TrimPrefix(path, pkgdata.PypiPrefix+"/"), "/", 2)
+	if splits[0] == "" {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.PypiProvider,
+		URI:      NormalizePypiName(splits[0]),
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
+var pypiNameSeparators = regexp.MustCompile("[-_.]+")
+
+// NormalizePypiName lowercases the name and replaces the runs of
+// separators with hyphen.
+func NormalizePypiName(name string) string {
+	return pypiNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// ParseGomod parses pkg.go.dev url or module path to pkguri. The
+// version and package suffix after "@" are dropped.
+func ParseGomod(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != pkgdata.GomodHost {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if u.Host == "" {
+		path = strings.TrimPrefix(path, pkgdata.GomodHost+"/")
+	}
+	path = strings.TrimPrefix(path, pkgdata.GomodPrefix+"/")
+	if i := strings.Index(path, "@"); i >= 0 {
+		path = path[:i]
+	}
+	path = sanitizePath(path)
+	if path == "" {
+		return nil, ErrNoURI
+	}
+	// Module path starts with domain name.
+	if splits := strings.SplitN(path, "/", 2); !strings.Contains(splits[0], ".") {
+		return nil, ErrPath
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.GomodProvider,
+		URI:      path,
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
+// ParseCrates parses crates.io url to pkguri.
+func ParseCrates(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != pkgdata.CratesHost {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if !strings.HasPrefix(path, pkgdata.CratesPrefix+"/") {
+		return nil, ErrPath
+	}
+	splits := strings.SplitN(strings.TrimPrefix(path, pkgdata.CratesPrefix+"/"), "/", 2)
+	if splits[0] == "" {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.CratesProvider,
+		URI:      splits[0],
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
+// ParseRubygems parses rubygems.org url to pkguri.
+func ParseRubygems(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != pkgdata.RubygemsHost {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if !strings.HasPrefix(path, pkgdata.RubygemsPrefix+"/") {
+		return nil, ErrPath
+	}
+	splits := strings.SplitN(strings.TrimPrefix(path, pkgdata.RubygemsPrefix+"/"), "/", 2)
+	if splits[0] == "" {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.RubygemsProvider,
+		URI:      splits[0],
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
+// ParsePackagist parses packagist.org url to pkguri. Package name
+// consists of vendor and project, e.g. "vendor/project".
+func ParsePackagist(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != pkgdata.PackagistHost {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if !strings.HasPrefix(path, pkgdata.PackagistPrefix+"/") {
+		return nil, ErrPath
+	}
+	splits := strings.SplitN(strings.TrimPrefix(path, pkgdata.PackagistPrefix+"/"), "/", 3)
+	if len(splits) < 2 || splits[0] == "" || splits[1] == "" {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.PackagistProvider,
+		URI:      strings.ToLower(strings.Join(splits[:2], "/")),
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
 func ParseYoutube(rawurl string) (*PkgURI, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
@@ -328,6 +520,147 @@ func ParseDocker(rawurl string) (*PkgURI, error) {
 	}, nil
 }
 
+// ParseDockerImage parses image reference, e.g. "bitnami/nginx:1.19",
+// to pkguri. Images outside docker hub are not supported.
+func ParseDockerImage(ref string) (*PkgURI, error) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+
+	splits := strings.SplitN(ref, "/", 2)
+	if len(splits) == 2 && (strings.ContainsAny(splits[0], ".:") || splits[0] == "localhost") {
+		switch splits[0] {
+		case pkgdata.DockerRegistryHost, "index.docker.io", "registry-1.docker.io":
+			ref = splits[1]
+		default:
+			return nil, ErrHost
+		}
+	}
+	if ref == "" {
+		return nil, ErrNoURI
+	}
+	if !strings.Contains(ref, "/") {
+		ref = "library/" + ref
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.DockerProvider,
+		URI:      ref,
+		Proto:    DefaultProto,
+	}, nil
+}
+
+// ParseOci parses image repository url of OCI registry to pkguri.
+// The url host must be one of hosts, which defaults to ghcr.io and
+// quay.io, unless the url is in the form of "oci://<host>/<name>".
+// Image reference without scheme, e.g. "ghcr.io/owner/app:1.0", is
+// also accepted.
+func ParseOci(rawurl string, hosts ...string) (*PkgURI, error) {
+	if !strings.Contains(rawurl, "://") {
+		rawurl = DefaultProto + "://" + rawurl
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, ErrHost
+	}
+
+	proto := u.Scheme
+	if proto == pkgdata.OciScheme {
+		proto = DefaultProto
+	} else {
+		if len(hosts) == 0 {
+			hosts = []string{pkgdata.GhcrHost, pkgdata.QuayHost}
+		}
+		matched := false
+		for _, h := range hosts {
+			if strings.EqualFold(h, u.Host) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, ErrHost
+		}
+	}
+	host := strings.ToLower(u.Host)
+
+	path := sanitizePath(u.Path)
+	if host == pkgdata.QuayHost {
+		path = strings.TrimPrefix(path, pkgdata.QuayPrefix+"/")
+	}
+	// Removes digest and tag of image reference.
+	if i := strings.Index(path, "@"); i >= 0 {
+		path = path[:i]
+	}
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		path = path[:i]
+	}
+	path = sanitizePath(path)
+	if path == "" {
+		return nil, ErrNoURI
+	}
+
+	return &PkgURI{
+		Provider: pkgdata.OciProvider,
+		Host:     host,
+		URI:      strings.ToLower(path),
+		Proto:    proto,
+	}, nil
+}
+
+// ParseHelm parses artifact hub package url or chart repository
+// index url to pkguri. The chart name of index url is given by
+// fragment, e.g. "https://example.com/charts/index.yaml#nginx".
+//
+// Both forms produce uri of the repository and chart name.
+func ParseHelm(rawurl string) (*PkgURI, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, ErrHost
+	}
+
+	path := sanitizePath(u.Path)
+	if u.Host == pkgdata.ArtifacthubHost {
+		if !strings.HasPrefix(path, pkgdata.ArtifacthubPrefix+"/") {
+			return nil, ErrPath
+		}
+		splits := strings.SplitN(strings.TrimPrefix(path, pkgdata.ArtifacthubPrefix+"/"), "/", 3)
+		if len(splits) < 2 || splits[0] == "" || splits[1] == "" {
+			return nil, ErrNoURI
+		}
+		return &PkgURI{
+			Provider: pkgdata.HelmProvider,
+			Host:     u.Host,
+			URI:      strings.Join(splits[:2], "/"),
+			Proto:    getProto(u.Scheme),
+		}, nil
+	}
+
+	if path != pkgdata.HelmIndexFile && !strings.HasSuffix(path, "/"+pkgdata.HelmIndexFile) {
+		return nil, ErrPath
+	}
+	if u.Fragment == "" {
+		return nil, ErrNoURI
+	}
+	repo := strings.TrimSuffix(strings.TrimSuffix(path, pkgdata.HelmIndexFile), "/")
+
+	return &PkgURI{
+		Provider: pkgdata.HelmProvider,
+		Host:     u.Host,
+		URI:      strings.TrimPrefix(repo+"/"+u.Fragment, "/"),
+		Proto:    getProto(u.Scheme),
+	}, nil
+}
+
 func ParseWebsite(rawurl string) (*PkgURI, error) {
 	u, err := monlutils.NormalizeURL(rawurl)
 	if err != nil {
@@ -366,12 +699,47 @@ func ToURL(pu *PkgURI) string {
 			u.Path = strings.Join(splits[1:], "/")
 		}
 
+	case pkgdata.GitlabProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.GitlabHost
+		}
+
 	case pkgdata.NpmProvider:
 		if u.Host == "" {
 			u.Host = pkgdata.NpmHost
 		}
 		u.Path = fmt.Sprintf("/%s/%s", pkgdata.NpmPrefix, pu.URI)
 
+	case pkgdata.PypiProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.PypiHost
+		}
+		u.Path = fmt.Sprintf("/%s/%s/", pkgdata.PypiPrefix, pu.URI)
+
+	case pkgdata.CratesProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.CratesHost
+		}
+		u.Path = fmt.Sprintf("/%s/%s", pkgdata.CratesPrefix, pu.URI)
+
+	case pkgdata.RubygemsProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.RubygemsHost
+		}
+		u.Path = fmt.Sprintf("/%s/%s", pkgdata.RubygemsPrefix, pu.URI)
+
+	case pkgdata.PackagistProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.PackagistHost
+		}
+		u.Path = fmt.Sprintf("/%s/%s", pkgdata.PackagistPrefix, pu.URI)
+
+	case pkgdata.GomodProvider:
+		if u.Host == "" {
+			u.Host = pkgdata.GomodHost
+		}
+		u.Path = "/" + pu.URI
+
 	case pkgdata.YoutubeProvider:
 		if u.Host == "" {
 			u.Host = pkgdata.YoutubeHost
@@ -380,6 +748,22 @@ func ToURL(pu *PkgURI) string {
 			u.Path = fmt.Sprintf("/channel/%s", pu.URI)
 		}
 
+	case pkgdata.HelmProvider:
+		if u.Host == pkgdata.ArtifacthubHost {
+			u.Path = fmt.Sprintf("/%s/%s", pkgdata.ArtifacthubPrefix, pu.URI)
+		} else {
+			u.Path = "/" + pkgdata.HelmIndexFile
+			if ns := pu.Namespace(); ns != "" {
+				u.Path = "/" + ns + u.Path
+			}
+			u.Fragment = pu.RepoName()
+		}
+
+	case pkgdata.OciProvider:
+		if u.Host == pkgdata.QuayHost {
+			u.Path = fmt.Sprintf("/%s/%s", pkgdata.QuayPrefix, pu.URI)
+		}
+
 	case pkgdata.DockerProvider:
 		if u.Host == "" {
 			u.Host = pkgdata.DockerHost
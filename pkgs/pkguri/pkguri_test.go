@@ -127,3 +127,377 @@ func TestMarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestParseGitlab(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		hosts  []string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://gitlab.com/owner/repo",
+			expect: &PkgURI{
+				Provider: "gitlab",
+				Host:     "gitlab.com",
+				URI:      "owner/repo",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://gitlab.com/group/subgroup/repo/-/tree/master",
+			expect: &PkgURI{
+				Provider: "gitlab",
+				Host:     "gitlab.com",
+				URI:      "group/subgroup/repo",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "http://git.example.com/owner/repo.git",
+			hosts:  []string{"gitlab.com", "git.example.com"},
+			expect: &PkgURI{
+				Provider: "gitlab",
+				Host:     "git.example.com",
+				URI:      "owner/repo",
+				Proto:    "http",
+			},
+		},
+		{
+			rawurl: "https://git.example.com/owner/repo",
+			err:    ErrHost,
+		},
+		{
+			rawurl: "https://gitlab.com/owner",
+			err:    ErrNoURI,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseGitlab(test.rawurl, test.hosts...)
+		if assert.Equal(t, test.err, err) && err == nil {
+			assert.Equal(t, test.expect, pu)
+		}
+	}
+}
+
+func TestParseGomod(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://pkg.go.dev/github.com/pinmonl/pinmonl",
+			expect: &PkgURI{
+				Provider: "gomod",
+				URI:      "github.com/pinmonl/pinmonl",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://pkg.go.dev/mod/golang.org/x/mod@v0.3.0",
+			expect: &PkgURI{
+				Provider: "gomod",
+				URI:      "golang.org/x/mod",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://pkg.go.dev/github.com/Masterminds/semver/v3@v3.1.0/?tab=versions",
+			expect: &PkgURI{
+				Provider: "gomod",
+				URI:      "github.com/Masterminds/semver/v3",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "github.com/pinmonl/pinmonl",
+			expect: &PkgURI{
+				Provider: "gomod",
+				URI:      "github.com/pinmonl/pinmonl",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "pkg.go.dev/gopkg.in/yaml.v2",
+			expect: &PkgURI{
+				Provider: "gomod",
+				URI:      "gopkg.in/yaml.v2",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://pkg.go.dev/std",
+			err:    ErrPath,
+		},
+		{
+			rawurl: "https://github.com/pinmonl/pinmonl",
+			err:    ErrHost,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseGomod(test.rawurl)
+		if assert.Equal(t, test.err, err, test.rawurl) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, "https://pkg.go.dev/"+pu.URI, ToURL(pu))
+		}
+	}
+}
+
+func TestParseCrates(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://crates.io/crates/serde",
+			expect: &PkgURI{Provider: "crates", URI: "serde", Proto: "https"},
+		},
+		{
+			rawurl: "https://crates.io/crates/serde/1.0.0",
+			expect: &PkgURI{Provider: "crates", URI: "serde", Proto: "https"},
+		},
+		{
+			rawurl: "https://crates.io/users/someone",
+			err:    ErrPath,
+		},
+		{
+			rawurl: "https://example.com/crates/serde",
+			err:    ErrHost,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseCrates(test.rawurl)
+		if assert.Equal(t, test.err, err) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, "https://crates.io/crates/"+pu.URI, ToURL(pu))
+		}
+	}
+}
+
+func TestParseRubygems(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://rubygems.org/gems/rails",
+			expect: &PkgURI{Provider: "rubygems", URI: "rails", Proto: "https"},
+		},
+		{
+			rawurl: "https://rubygems.org/gems/rails/versions/6.0.3",
+			expect: &PkgURI{Provider: "rubygems", URI: "rails", Proto: "https"},
+		},
+		{
+			rawurl: "https://rubygems.org/profiles/someone",
+			err:    ErrPath,
+		},
+		{
+			rawurl: "https://example.com/gems/rails",
+			err:    ErrHost,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseRubygems(test.rawurl)
+		if assert.Equal(t, test.err, err) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, "https://rubygems.org/gems/"+pu.URI, ToURL(pu))
+		}
+	}
+}
+
+func TestParsePackagist(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://packagist.org/packages/laravel/framework",
+			expect: &PkgURI{Provider: "packagist", URI: "laravel/framework", Proto: "https"},
+		},
+		{
+			rawurl: "https://packagist.org/packages/Monolog/Monolog#2.1.0",
+			expect: &PkgURI{Provider: "packagist", URI: "monolog/monolog", Proto: "https"},
+		},
+		{
+			rawurl: "https://packagist.org/packages/laravel/",
+			err:    ErrNoURI,
+		},
+		{
+			rawurl: "https://example.com/packages/laravel/framework",
+			err:    ErrHost,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParsePackagist(test.rawurl)
+		if assert.Equal(t, test.err, err) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, "https://packagist.org/packages/"+pu.URI, ToURL(pu))
+		}
+	}
+}
+
+func TestParsePypi(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		err    error
+	}{
+		{
+			rawurl: "https://pypi.org/project/requests/",
+			expect: &PkgURI{
+				Provider: "pypi",
+				URI:      "requests",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://pypi.org/project/Zope.Interface/5.1.0/",
+			expect: &PkgURI{
+				Provider: "pypi",
+				URI:      "zope-interface",
+				Proto:    "https",
+			},
+		},
+		{
+			rawurl: "https://pypi.org/user/someone/",
+			err:    ErrPath,
+		},
+		{
+			rawurl: "https://example.com/project/requests/",
+			err:    ErrHost,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParsePypi(test.rawurl)
+		if assert.Equal(t, test.err, err) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, "https://pypi.org/project/"+pu.URI+"/", ToURL(pu))
+		}
+	}
+}
+
+func TestParseHelm(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		expect *PkgURI
+		url    string
+		err    error
+	}{
+		{
+			rawurl: "https://artifacthub.io/packages/helm/bitnami/nginx",
+			expect: &PkgURI{Provider: "helm", Host: "artifacthub.io", URI: "bitnami/nginx", Proto: "https"},
+			url:    "https://artifacthub.io/packages/helm/bitnami/nginx",
+		},
+		{
+			rawurl: "https://charts.bitnami.com/bitnami/index.yaml#nginx",
+			expect: &PkgURI{Provider: "helm", Host: "charts.bitnami.com", URI: "bitnami/nginx", Proto: "https"},
+			url:    "https://charts.bitnami.com/bitnami/index.yaml#nginx",
+		},
+		{
+			rawurl: "http://localhost:8080/index.yaml#app",
+			expect: &PkgURI{Provider: "helm", Host: "localhost:8080", URI: "app", Proto: "http"},
+			url:    "http://localhost:8080/index.yaml#app",
+		},
+		{
+			rawurl: "https://charts.bitnami.com/bitnami/index.yaml",
+			err:    ErrNoURI,
+		},
+		{
+			rawurl: "https://artifacthub.io/packages/olm/foo/bar",
+			err:    ErrPath,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseHelm(test.rawurl)
+		if assert.Equal(t, test.err, err, test.rawurl) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, test.url, ToURL(pu))
+		}
+	}
+}
+
+func TestParseDockerImage(t *testing.T) {
+	tests := []struct {
+		ref    string
+		expect string
+		err    error
+	}{
+		{ref: "nginx", expect: "library/nginx"},
+		{ref: "bitnami/nginx:1.19.0-debian-10-r0", expect: "bitnami/nginx"},
+		{ref: "docker.io/bitnami/nginx@sha256:abcdef", expect: "bitnami/nginx"},
+		{ref: "quay.io/prometheus/prometheus:v2.19.0", err: ErrHost},
+		{ref: "localhost:5000/app", err: ErrHost},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseDockerImage(test.ref)
+		if assert.Equal(t, test.err, err, test.ref) && err == nil {
+			assert.Equal(t, "docker", pu.Provider)
+			assert.Equal(t, test.expect, pu.URI)
+		}
+	}
+}
+
+func TestParseOci(t *testing.T) {
+	tests := []struct {
+		rawurl string
+		hosts  []string
+		expect *PkgURI
+		url    string
+		err    error
+	}{
+		{
+			rawurl: "https://ghcr.io/owner/app",
+			expect: &PkgURI{Provider: "oci", Host: "ghcr.io", URI: "owner/app", Proto: "https"},
+			url:    "https://ghcr.io/owner/app",
+		},
+		{
+			rawurl: "ghcr.io/Owner/app:1.0.0",
+			expect: &PkgURI{Provider: "oci", Host: "ghcr.io", URI: "owner/app", Proto: "https"},
+			url:    "https://ghcr.io/owner/app",
+		},
+		{
+			rawurl: "https://quay.io/repository/prometheus/prometheus?tab=tags",
+			expect: &PkgURI{Provider: "oci", Host: "quay.io", URI: "prometheus/prometheus", Proto: "https"},
+			url:    "https://quay.io/repository/prometheus/prometheus",
+		},
+		{
+			rawurl: "oci://registry.example.com:5000/team/app@sha256:abcdef",
+			expect: &PkgURI{Provider: "oci", Host: "registry.example.com:5000", URI: "team/app", Proto: "https"},
+			url:    "https://registry.example.com:5000/team/app",
+		},
+		{
+			rawurl: "http://registry.example.com/app",
+			hosts:  []string{"registry.example.com"},
+			expect: &PkgURI{Provider: "oci", Host: "registry.example.com", URI: "app", Proto: "http"},
+			url:    "http://registry.example.com/app",
+		},
+		{
+			rawurl: "https://registry.example.com/app",
+			err:    ErrHost,
+		},
+		{
+			rawurl: "https://ghcr.io/",
+			err:    ErrNoURI,
+		},
+	}
+
+	for _, test := range tests {
+		pu, err := ParseOci(test.rawurl, test.hosts...)
+		if assert.Equal(t, test.err, err, test.rawurl) && err == nil {
+			assert.Equal(t, test.expect, pu)
+			assert.Equal(t, test.url, ToURL(pu))
+		}
+	}
+}
@@ -18,6 +18,8 @@ const (
 	TagCtxKey
 	SharetagCtxKey
 	ImageCtxKey
+	NotifyRuleCtxKey
+	PresetCtxKey
 )
 
 func WithPaginator(ctx context.Context, p *Paginator) context.Context {
@@ -127,3 +129,27 @@ func ImageFrom(ctx context.Context) *model.Image {
 	}
 	return nil
 }
+
+func WithNotifyRule(ctx context.Context, rule *model.NotifyRule) context.Context {
+	return context.WithValue(ctx, NotifyRuleCtxKey, rule)
+}
+
+func NotifyRuleFrom(ctx context.Context) *model.NotifyRule {
+	rule, ok := ctx.Value(NotifyRuleCtxKey).(*model.NotifyRule)
+	if ok {
+		return rule
+	}
+	return nil
+}
+
+func WithPreset(ctx context.Context, preset *model.Preset) context.Context {
+	return context.WithValue(ctx, PresetCtxKey, preset)
+}
+
+func PresetFrom(ctx context.Context) *model.Preset {
+	preset, ok := ctx.Value(PresetCtxKey).(*model.Preset)
+	if ok {
+		return preset
+	}
+	return nil
+}
@@ -40,6 +40,10 @@ func ParseJwtClaims(jwtToken string, secret []byte, claims jwt.Claims) error {
 	return nil
 }
 
+// FeedAudience is the audience of feed token, which is only
+// accepted by the feed endpoints.
+const FeedAudience = "feed"
+
 type AuthClaims struct {
 	jwt.StandardClaims
 	UserID string `json:"userId"`
@@ -64,6 +68,47 @@ func Authenticate(secret []byte, users *store.Users) func(http.Handler) http.Han
 			if err != nil {
 				return
 			}
+			if claims.Audience == FeedAudience {
+				return
+			}
+
+			user, err := users.Find(ctx, claims.UserID)
+			if user == nil {
+				return
+			}
+			if user.Hash != claims.Hash {
+				return
+			}
+
+			ctx = WithAuthed(ctx, user)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// AuthenticateFeed binds the user of the feed token in query
+// parameter "token". The authed user is kept if token is absent.
+func AuthenticateFeed(secret []byte, users *store.Users) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			defer func() {
+				next.ServeHTTP(w, r.WithContext(ctx))
+			}()
+
+			jwtToken := r.URL.Query().Get("token")
+			if jwtToken == "" {
+				return
+			}
+
+			var claims AuthClaims
+			err := ParseJwtClaims(jwtToken, secret, &claims)
+			if err != nil {
+				return
+			}
+			if claims.Audience != FeedAudience {
+				return
+			}
 
 			user, err := users.Find(ctx, claims.UserID)
 			if user == nil {
@@ -125,3 +170,28 @@ func GenerateJwtToken(issuer string, expireAfter time.Duration, secret []byte, u
 	}
 	return signed, nil
 }
+
+// GenerateFeedToken creates the token of feed endpoints. The token does
+// not expire if expireAfter is zero, and is revoked anyway when the
+// user hash is changed.
+func GenerateFeedToken(issuer string, expireAfter time.Duration, secret []byte, user *model.User) (string, error) {
+	claims := AuthClaims{
+		UserID: user.ID,
+		Hash:   user.Hash,
+		StandardClaims: jwt.StandardClaims{
+			Audience: FeedAudience,
+			IssuedAt: time.Now().Unix(),
+			Issuer:   issuer,
+		},
+	}
+	if expireAfter > 0 {
+		claims.ExpiresAt = time.Now().Add(expireAfter).Unix()
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		return "", err
+	}
+	return signed, nil
+}
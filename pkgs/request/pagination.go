@@ -11,8 +11,14 @@ import (
 type Paginator struct {
 	Page     int64
 	PageSize int64
+
+	// Cursor is the position of page, which takes precedence over Page.
+	Cursor *store.Cursor
 }
 
+// cursorName is the query param of cursor.
+const cursorName = "cursor"
+
 func NewPaginatorFromRequest(r *http.Request, pageName, sizeName string, defaultSize int64) (*Paginator, error) {
 	var (
 		page, size int64
@@ -37,9 +43,17 @@ func NewPaginatorFromRequest(r *http.Request, pageName, sizeName string, default
 		size = defaultSize
 	}
 
+	var cursor *store.Cursor
+	if cursorq := r.URL.Query().Get(cursorName); cursorq != "" {
+		if cursor, err = store.DecodeCursor(cursorq); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Paginator{
 		Page:     page,
 		PageSize: size,
+		Cursor:   cursor,
 	}, nil
 }
 
@@ -47,6 +61,7 @@ func (p *Paginator) ToOpts() store.ListOpts {
 	return store.ListOpts{
 		Limit:  p.PageSize,
 		Offset: p.PageSize * (p.Page - 1),
+		Cursor: p.Cursor,
 	}
 }
 
@@ -58,6 +73,32 @@ func (p *Paginator) ToPageInfo(total int64) *response.PageInfo {
 	}
 }
 
+// ToCursorPageInfo returns the page info with the cursors of next and
+// previous pages. cursor returns the cursor of the i-th row of the list,
+// which has n rows. Empty cursor means the list cannot be paginated by
+// cursor.
+func (p *Paginator) ToCursorPageInfo(total int64, n int, cursor func(i int) string) *response.PageInfo {
+	info := p.ToPageInfo(total)
+	if n == 0 {
+		return info
+	}
+
+	var (
+		full     = int64(n) == p.PageSize
+		backward = p.Cursor != nil && p.Cursor.Before
+	)
+	if full || backward {
+		info.Next = cursor(n - 1)
+	}
+	if (p.Cursor != nil || p.Page > 1) && (full || !backward) {
+		if prev, err := store.DecodeCursor(cursor(0)); err == nil {
+			prev.Before = true
+			info.Prev = prev.Encode()
+		}
+	}
+	return info
+}
+
 func Pagination(pageName, sizeName string, defaultSize int64) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
@@ -1,28 +1,73 @@
 package request
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/store"
 )
 
 type PinlQuery struct {
-	Query string
-	Tags  []string
-	NoTag field.NullBool
+	Query    string
+	Content  field.NullBool
+	Tags     []string
+	NoTag    field.NullBool
+	Sort     string
+	PresetID string
 }
 
 func ParsePinlQuery(r *http.Request) (*PinlQuery, error) {
 	query := PinlQuery{
-		Query: r.URL.Query().Get("q"),
-		Tags:  QueryCsv(r, "tag"),
-		NoTag: QueryBool(r, "notag"),
+		Query:   r.URL.Query().Get("q"),
+		Content: QueryBool(r, "content"),
+		Tags:    QueryCsv(r, "tag"),
+		NoTag:   QueryBool(r, "notag"),
 	}
+	query.Sort = r.URL.Query().Get("sort")
+	query.PresetID = r.URL.Query().Get("preset")
 	return &query, nil
 }
 
+// PinlSorts maps the keys of sort to the orders of pinls. Keys
+// prefixed with "-" sort in descending order.
+var PinlSorts = map[string]store.PinlOrder{
+	"created":   store.PinlOrderByOldest,
+	"-created":  store.PinlOrderByLatest,
+	"updated":   store.PinlOrderByUpdated,
+	"-updated":  store.PinlOrderByUpdatedDesc,
+	"title":     store.PinlOrderByTitle,
+	"-title":    store.PinlOrderByTitleDesc,
+	"host":      store.PinlOrderByHost,
+	"-host":     store.PinlOrderByHostDesc,
+	"released":  store.PinlOrderByReleased,
+	"-released": store.PinlOrderByReleasedDesc,
+	"stars":     store.PinlOrderByStars,
+	"-stars":    store.PinlOrderByStarsDesc,
+	"position":  store.PinlOrderByPosition,
+	"-position": store.PinlOrderByPositionDesc,
+	"relevance": store.PinlOrderByRelevance,
+}
+
+// ParsePinlSort parses the comma separated keys of sort.
+func ParsePinlSort(sort string) ([]store.PinlOrder, error) {
+	orders := make([]store.PinlOrder, 0)
+	for _, key := range strings.Split(sort, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		order, ok := PinlSorts[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort %q", key)
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
 type TagQuery struct {
 	Query     string
 	Names     []string
@@ -63,6 +108,29 @@ func ParseStatQuery(r *http.Request) (*StatQuery, error) {
 	return &query, nil
 }
 
+type JobQuery struct {
+	Names      []string
+	Status     field.NullValue
+	TargetIDs  []string
+	TargetName string
+}
+
+func ParseJobQuery(r *http.Request) (*JobQuery, error) {
+	query := JobQuery{
+		Names:      QueryCsv(r, "name"),
+		TargetIDs:  QueryCsv(r, "target_id"),
+		TargetName: r.URL.Query().Get("target_name"),
+	}
+	if statusq := r.URL.Query().Get("status"); statusq != "" {
+		status, err := model.ParseJobStatus(statusq)
+		if err != nil {
+			return nil, err
+		}
+		query.Status = field.NewNullValue(status)
+	}
+	return &query, nil
+}
+
 func QueryCsv(r *http.Request, paramName string) []string {
 	out := make([]string, 0)
 	qv := r.URL.Query().Get(paramName)
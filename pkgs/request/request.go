@@ -8,3 +8,23 @@ import (
 func JSON(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
+
+// BaseURL returns the scheme and host of the request. The headers
+// X-Forwarded-Proto and X-Forwarded-Host are only honored if
+// trustProxy is set, as they can be forged by clients without proxy.
+func BaseURL(r *http.Request, trustProxy bool) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if trustProxy {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if fwdHost := r.Header.Get("X-Forwarded-Host"); fwdHost != "" {
+			host = fwdHost
+		}
+	}
+	return scheme + "://" + host
+}
@@ -40,6 +40,11 @@ type PageInfo struct {
 	TotalCount int64 `json:"totalCount"`
 	Page       int64 `json:"page"`
 	PageSize   int64 `json:"pageSize"`
+
+	// Next and Prev are the cursors of next and previous pages. Empty if
+	// there is no more page or the list cannot be paginated by cursor.
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
 
 func ListJSON(w http.ResponseWriter, v interface{}, info *PageInfo, code int) error {
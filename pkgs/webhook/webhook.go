@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Pinmonl-Event"
+	HeaderDelivery  = "X-Pinmonl-Delivery"
+	HeaderSignature = "X-Pinmonl-Signature"
+
+	userAgent = "Pinmonl-Webhook"
+)
+
+// DefaultClient is used when no client is passed to Send.
+var DefaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Request is the webhook to be sent.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// StatusError is returned when the receiver does not respond
+// with 2xx status.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook: http status %d", e.Code)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether the signature header matches body.
+func Verify(secret, body []byte, signature string) bool {
+	expected := "sha256=" + Sign(secret, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Send posts the request body and returns the response status code.
+func Send(ctx context.Context, client *http.Client, r *Request) (int, error) {
+	if client == nil {
+		client = DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, r.Event)
+	req.Header.Set(HeaderDelivery, r.DeliveryID)
+	if r.Secret != "" {
+		req.Header.Set(HeaderSignature, "sha256="+Sign([]byte(r.Secret), r.Body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, &StatusError{Code: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	var (
+		body   = []byte(`{"event":"release"}`)
+		secret = "secret"
+		header http.Header
+		got    []byte
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		got, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	code, err := Send(context.TODO(), nil, &Request{
+		URL:        srv.URL,
+		Secret:     secret,
+		Event:      "release",
+		DeliveryID: "delivery-id-1",
+		Body:       body,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, body, got)
+	assert.Equal(t, "release", header.Get(HeaderEvent))
+	assert.Equal(t, "delivery-id-1", header.Get(HeaderDelivery))
+	assert.True(t, Verify([]byte(secret), got, header.Get(HeaderSignature)))
+	assert.False(t, Verify([]byte("other"), got, header.Get(HeaderSignature)))
+
+	code, err = Send(context.TODO(), nil, &Request{
+		URL:  srv.URL + "/fail",
+		Body: body,
+	})
+	assert.Equal(t, http.StatusBadGateway, code)
+	assert.Equal(t, &StatusError{Code: http.StatusBadGateway}, err)
+	assert.Empty(t, header.Get(HeaderSignature))
+}
@@ -1,6 +1,7 @@
 package pubsub
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 type Hub struct {
 	clients   map[*Client]bool
 	broadcast chan Message
+	done      chan struct{}
 
 	TokenSecret []byte
 	TokenExpire time.Duration
@@ -23,6 +25,7 @@ func NewHub(tokenSecret []byte, tokenExpire time.Duration, tokenIssuer string, u
 	return &Hub{
 		clients:   make(map[*Client]bool),
 		broadcast: make(chan Message),
+		done:      make(chan struct{}),
 
 		TokenSecret: tokenSecret,
 		TokenExpire: tokenExpire,
@@ -31,9 +34,17 @@ func NewHub(tokenSecret []byte, tokenExpire time.Duration, tokenIssuer string, u
 	}
 }
 
-func (h *Hub) Start() error {
+// Start delivers the messages until ctx is done, then the clients
+// are closed.
+func (h *Hub) Start(ctx context.Context) error {
 	for {
 		select {
+		case <-ctx.Done():
+			close(h.done)
+			for c := range h.clients {
+				h.Unregister(c)
+			}
+			return nil
 		case msg := <-h.broadcast:
 			for c := range h.clients {
 				if !c.IsSubscribed(msg.Topic()) {
@@ -51,11 +62,15 @@ func (h *Hub) Start() error {
 			}
 		}
 	}
-	return nil
 }
 
+// Broadcast sends the message to the subscribed clients, it is
+// dropped if the hub is stopped.
 func (h *Hub) Broadcast(msg Message) error {
-	h.broadcast <- msg
+	select {
+	case h.broadcast <- msg:
+	case <-h.done:
+	}
 	return nil
 }
 
@@ -1,11 +1,12 @@
 package pubsub
 
 import (
+	"context"
 	"net/http"
 )
 
 type Pubsuber interface {
-	Start() error
+	Start(context.Context) error
 	Register(*Client) error
 	Unregister(*Client) error
 	Broadcast(Message) error
@@ -0,0 +1,156 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/mailer"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+var digestTextTmpl = template.Must(template.New("digest").Parse(
+	`New releases since {{.Since.Format "2006-01-02 15:04 MST"}}
+{{range .Groups}}
+[{{if .Tag}}{{.Tag}}{{else}}Untagged{{end}}]
+{{range .Releases}}- {{if .Pinl.Title}}{{.Pinl.Title}}{{else}}{{.Pinl.URL}}{{end}}: {{.Stat.Value}} ({{.Pkg.Provider}} {{.Pkg.ProviderURI}})
+  {{.Pinl.URL}}
+{{end}}{{end}}`))
+
+var digestHTMLTmpl = htmltemplate.Must(htmltemplate.New("digest").Parse(
+	`<p>New releases since {{.Since.Format "2006-01-02 15:04 MST"}}</p>
+{{range .Groups}}<h3>{{if .Tag}}{{.Tag}}{{else}}Untagged{{end}}</h3>
+<ul>
+{{range .Releases}}<li><a href="{{.Pinl.URL}}">{{if .Pinl.Title}}{{.Pinl.Title}}{{else}}{{.Pinl.URL}}{{end}}</a>: <strong>{{.Stat.Value}}</strong> ({{.Pkg.Provider}} {{.Pkg.ProviderURI}})</li>
+{{end}}</ul>
+{{end}}`))
+
+type digestData struct {
+	Since  time.Time
+	Groups []*storeutils.DigestGroup
+}
+
+// SendDigest emails the new releases since the last digest of the
+// user and moves the watermark forward. Both are done in PreRun.
+type SendDigest struct {
+	DigestID string
+}
+
+func init() {
+	Register("send_digest", func() Job { return &SendDigest{} })
+}
+
+func NewSendDigest(digestID string) *SendDigest {
+	return &SendDigest{
+		DigestID: digestID,
+	}
+}
+
+func (s *SendDigest) String() string {
+	return "send_digest"
+}
+
+func (s *SendDigest) Describe() []string {
+	return []string{
+		s.String(),
+		s.DigestID,
+	}
+}
+
+func (s *SendDigest) Target() model.Morphable {
+	return model.Digest{ID: s.DigestID}
+}
+
+func (s *SendDigest) RunAt() time.Time {
+	return time.Time{}
+}
+
+func (s *SendDigest) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
+func (s *SendDigest) PreRun(ctx context.Context) error {
+	stores := StoresFrom(ctx)
+	if stores == nil {
+		return ErrNoStores
+	}
+	ml := MailerFrom(ctx)
+	if ml == nil {
+		return ErrNoMailer
+	}
+
+	digest, err := stores.Digests.Find(ctx, s.DigestID)
+	if err != nil {
+		return err
+	}
+	if digest == nil || !digest.Enabled {
+		return nil
+	}
+
+	until := field.Now()
+	since := digest.Since()
+	groups, err := storeutils.ListDigestReleases(ctx, storeutils.DigestStores{
+		Monpkgs:   stores.Monpkgs,
+		Pinls:     stores.Pinls,
+		Stats:     stores.Stats,
+		Taggables: stores.Taggables,
+	}, digest.UserID, since, until.Time())
+	if err != nil {
+		return err
+	}
+
+	if len(groups) > 0 {
+		msg, err := s.render(digest, &digestData{Since: since, Groups: groups})
+		if err != nil {
+			return Permanent(err)
+		}
+		if err := ml.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	// The watermark moves right after sending, not in the transaction
+	// of Run, so that a retry does not send the digest again.
+	digest.LastSentAt = until
+	if err := stores.Digests.Update(ctx, digest); err != nil {
+		return Permanent(err)
+	}
+	return nil
+}
+
+func (s *SendDigest) render(digest *model.Digest, data *digestData) (*mailer.Message, error) {
+	var text, html bytes.Buffer
+	if err := digestTextTmpl.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := digestHTMLTmpl.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	n := 0
+	for _, group := range data.Groups {
+		n += len(group.Releases)
+	}
+	subject := fmt.Sprintf("Pinmonl: %d new releases", n)
+	if n == 1 {
+		subject = "Pinmonl: 1 new release"
+	}
+
+	return &mailer.Message{
+		To:      []string{digest.Email},
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (s *SendDigest) Run(ctx context.Context) ([]Job, error) {
+	return nil, nil
+}
+
+var _ Job = &SendDigest{}
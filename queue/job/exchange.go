@@ -2,6 +2,7 @@ package job
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/pinmonl/pinmonl/model"
@@ -13,6 +14,9 @@ import (
 	"github.com/pinmonl/pinmonl/store/storeutils"
 )
 
+// fetchPageSize is the page size of the lists fetched from exchange.
+const fetchPageSize = 100
+
 type FetchMonl struct {
 	MonlID string
 
@@ -21,6 +25,10 @@ type FetchMonl struct {
 	stats map[*model.Pkg][]*model.Stat
 }
 
+func init() {
+	Register("fetch_monl", func() Job { return &FetchMonl{} })
+}
+
 func NewFetchMonl(monlID string) *FetchMonl {
 	return &FetchMonl{
 		MonlID: monlID,
@@ -46,6 +54,10 @@ func (f *FetchMonl) RunAt() time.Time {
 	return time.Time{}
 }
 
+func (f *FetchMonl) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
 func (f *FetchMonl) PreRun(ctx context.Context) error {
 	stores := StoresFrom(ctx)
 
@@ -63,16 +75,22 @@ func (f *FetchMonl) PreRun(ctx context.Context) error {
 
 	pOpts := &pinmonl.PkgListOpts{}
 	pOpts.URL = monl.URL
-	pOpts.Size = -1
-	pResp, err := client.PkgList(pOpts)
-	if err != nil {
-		return err
+	pOpts.Size = fetchPageSize
+	mpList := make([]*pinmonl.Monpkg, 0)
+	for pIter := client.PkgIterator(pOpts); ; {
+		page, err := pIter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		mpList = append(mpList, page...)
 	}
 
 	f.pkgs = make(map[*model.Pkg]model.MonpkgKind)
 	f.stats = make(map[*model.Pkg][]*model.Stat)
-	for i := range pResp.Data {
-		mpsrc := pResp.Data[i]
+	for _, mpsrc := range mpList {
 		pu, err := f.parseURI(mpsrc)
 		if err != nil {
 			return err
@@ -90,23 +108,34 @@ func (f *FetchMonl) PreRun(ctx context.Context) error {
 		}
 		f.pkgs[pkg] = model.MonpkgKind(mpsrc.Kind)
 
+		// FetchedAt keeps the time crawled by exchange, the stats are
+		// unchanged if the pkg is not crawled again since last fetch.
+		crawledAt := mpsrc.Pkg.FetchedAt
+		if pkg.ID != "" && !crawledAt.Time().IsZero() && pkg.FetchedAt.Time().Equal(crawledAt.Time()) {
+			continue
+		}
+		pkg.FetchedAt = crawledAt
+
 		sOpts := &pinmonl.StatListOpts{}
 		sOpts.Latest = field.NewNullBool(true)
 		sOpts.Pkgs = []string{mpsrc.PkgID}
-		sOpts.Size = -1
-		sResp, err := client.StatList(sOpts)
-		if err != nil {
-			return err
-		}
-
-		stats := make([]*model.Stat, len(sResp.Data))
-		for j := range sResp.Data {
-			ssrc := sResp.Data[j]
-			stat, err := f.parseStat(ssrc)
+		sOpts.Size = fetchPageSize
+		stats := make([]*model.Stat, 0)
+		for sIter := client.StatIterator(sOpts); ; {
+			page, err := sIter.Next()
+			if err == io.EOF {
+				break
+			}
 			if err != nil {
 				return err
 			}
-			stats[j] = stat
+			for _, ssrc := range page {
+				stat, err := f.parseStat(ssrc)
+				if err != nil {
+					return err
+				}
+				stats = append(stats, stat)
+			}
 		}
 		f.stats[pkg] = stats
 	}
@@ -128,9 +157,12 @@ func (f *FetchMonl) Run(ctx context.Context) ([]Job, error) {
 		return nil, err
 	}
 
+	releases := model.StatList{}
 	for pkg, kind := range f.pkgs {
 		var err error
-		pkg.FetchedAt = field.Now()
+		if pkg.FetchedAt.Time().IsZero() {
+			pkg.FetchedAt = field.Now()
+		}
 		if pkg.ID == "" {
 			err = stores.Pkgs.Create(ctx, pkg)
 		} else {
@@ -152,24 +184,62 @@ func (f *FetchMonl) Run(ctx context.Context) ([]Job, error) {
 			return nil, err
 		}
 
+		// Skip the pkg whose stats are not fetched.
+		if _, fetched := f.stats[pkg]; !fetched {
+			continue
+		}
+
 		prevStats, err := stores.Stats.List(ctx, &store.StatOpts{
 			PkgIDs: []string{pkg.ID},
 		})
 		if err != nil {
 			return nil, err
 		}
+
+		prevReleases := make(map[releaseKey]*model.Stat)
 		for _, prevStat := range prevStats {
-			if _, err := stores.Stats.Delete(ctx, prevStat.ID); err != nil {
-				return nil, err
+			key := releaseKey{prevStat.Kind, prevStat.Value}
+			if prevStat.ParentID == "" && model.IsReleaseStatKind(prevStat.Kind) && prevReleases[key] == nil {
+				prevReleases[key] = prevStat
 			}
 		}
 
+		// Releases found in previous fetch are updated in place, so that
+		// their ids and the time first seen are kept.
+		kept := make(map[string]bool)
 		for i := range f.stats[pkg] {
 			stat := f.stats[pkg][i]
 			stat.PkgID = pkg.ID
+
+			isRelease := stat.ParentID == "" && model.IsReleaseStatKind(stat.Kind)
+			prevStat := prevReleases[releaseKey{stat.Kind, stat.Value}]
+			if isRelease && prevStat != nil && !kept[prevStat.ID] {
+				stat.ID = prevStat.ID
+				stat.CreatedAt = prevStat.CreatedAt
+				if err := stores.Stats.Update(ctx, stat); err != nil {
+					return nil, err
+				}
+				kept[prevStat.ID] = true
+				continue
+			}
+
 			if err := stores.Stats.Create(ctx, stat); err != nil {
 				return nil, err
 			}
+
+			// Notify the release which is not found in previous fetch.
+			if len(prevReleases) > 0 && isRelease && prevStat == nil {
+				releases = append(releases, stat)
+			}
+		}
+
+		for _, prevStat := range prevStats {
+			if kept[prevStat.ID] {
+				continue
+			}
+			if _, err := stores.Stats.Delete(ctx, prevStat.ID); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -183,9 +253,17 @@ func (f *FetchMonl) Run(ctx context.Context) ([]Job, error) {
 		hub.Broadcast(message.NewPinlUpdated(pinls[i]))
 	}
 
+	if len(releases) > 0 {
+		return []Job{NewNotifyRelease(releases.Keys())}, nil
+	}
 	return nil, nil
 }
 
+type releaseKey struct {
+	kind  model.StatKind
+	value string
+}
+
 func (f *FetchMonl) parseURI(src *pinmonl.Monpkg) (*pkguri.PkgURI, error) {
 	psrc := src.Pkg
 	return &pkguri.PkgURI{
@@ -197,6 +275,16 @@ func (f *FetchMonl) parseURI(src *pinmonl.Monpkg) (*pkguri.PkgURI, error) {
 }
 
 func (f *FetchMonl) parseStat(src *pinmonl.Stat) (*model.Stat, error) {
+	var assets model.StatAssetList
+	for _, a := range src.Assets {
+		assets = append(assets, &model.StatAsset{
+			Name:          a.Name,
+			URL:           a.URL,
+			ContentType:   a.ContentType,
+			Size:          a.Size,
+			DownloadCount: a.DownloadCount,
+		})
+	}
 	return &model.Stat{
 		RecordedAt:  src.RecordedAt,
 		Kind:        model.StatKind(src.Kind),
@@ -206,6 +294,8 @@ func (f *FetchMonl) parseStat(src *pinmonl.Stat) (*model.Stat, error) {
 		Weight:      src.Weight,
 		IsLatest:    src.IsLatest,
 		HasChildren: src.HasChildren,
+		Body:        src.Body,
+		Assets:      assets,
 	}, nil
 }
 
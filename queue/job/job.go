@@ -8,6 +8,7 @@ import (
 
 	"github.com/pinmonl/pinmonl/exchange"
 	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/mailer"
 	"github.com/pinmonl/pinmonl/pubsub"
 	"github.com/pinmonl/pinmonl/store"
 )
@@ -26,6 +27,10 @@ type Job interface {
 	// be executed.
 	RunAt() time.Time
 
+	// RetryPolicy reports how the job is retried when
+	// PreRun or Run fails.
+	RetryPolicy() RetryPolicy
+
 	// PreRun starts fetching the data for Run.
 	PreRun(context.Context) error
 
@@ -38,6 +43,7 @@ var (
 	ErrNoStores          = errors.New("job: stores is missing")
 	ErrNoPubsuber        = errors.New("job: pubsuber is missing")
 	ErrNoExchangeManager = errors.New("job: exchange manager is missing")
+	ErrNoMailer          = errors.New("job: mailer is missing")
 )
 
 type CtxKey int
@@ -46,6 +52,7 @@ const (
 	StoresCtxKey CtxKey = iota
 	ExchangeManagerCtxKey
 	PubsuberCtxKey
+	MailerCtxKey
 )
 
 func WithStores(ctx context.Context, stores *store.Stores) context.Context {
@@ -83,3 +90,15 @@ func PubsuberFrom(ctx context.Context) pubsub.Pubsuber {
 	}
 	return hub
 }
+
+func WithMailer(ctx context.Context, ml *mailer.Mailer) context.Context {
+	return context.WithValue(ctx, MailerCtxKey, ml)
+}
+
+func MailerFrom(ctx context.Context) *mailer.Mailer {
+	ml, ok := ctx.Value(MailerCtxKey).(*mailer.Mailer)
+	if !ok {
+		return nil
+	}
+	return ml
+}
@@ -21,10 +21,15 @@ import (
 // database.
 type MonlCrawler struct {
 	MonlID   string
-	monl     *model.Monl
-	reports  map[string]provider.Report
-	parentID string
-	derived  []string
+	ParentID string `json:",omitempty"`
+
+	monl    *model.Monl
+	reports map[string]provider.Report
+	derived []string
+}
+
+func init() {
+	Register("monl_crawler", func() Job { return &MonlCrawler{} })
 }
 
 func NewMonlCrawler(monlID string) *MonlCrawler {
@@ -32,7 +37,7 @@ func NewMonlCrawler(monlID string) *MonlCrawler {
 }
 
 func (m *MonlCrawler) WithParentID(parentID string) *MonlCrawler {
-	m.parentID = parentID
+	m.ParentID = parentID
 	return m
 }
 
@@ -55,6 +60,10 @@ func (m *MonlCrawler) RunAt() time.Time {
 	return time.Time{}
 }
 
+func (m *MonlCrawler) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
 func (m *MonlCrawler) PreRun(ctx context.Context) error {
 	stores := StoresFrom(ctx)
 
@@ -109,7 +118,7 @@ func (m *MonlCrawler) PreRun(ctx context.Context) error {
 
 		// Derived only when there is no parent
 		// to avoid recursive derive.
-		if m.parentID == "" {
+		if m.ParentID == "" {
 			urls, err := repo.Derived()
 			if err != nil {
 				continue
@@ -129,6 +138,7 @@ func (m *MonlCrawler) Run(ctx context.Context) ([]Job, error) {
 	stores := StoresFrom(ctx)
 
 	jobs := make([]Job, 0)
+	releases := model.StatList{}
 	for uri := range m.reports {
 		report := m.reports[uri]
 		logrus.Debugf("job monl: %q start", report)
@@ -147,10 +157,11 @@ func (m *MonlCrawler) Run(ctx context.Context) ([]Job, error) {
 		}
 
 		// Save monler report.
-		pkg, _, err := storeutils.SaveProviderReport(ctx, stores.Pkgs, stores.Stats, report, false)
+		pkg, _, newReleases, err := storeutils.SaveProviderReport(ctx, stores.Pkgs, stores.Stats, report, false)
 		if err != nil {
 			return nil, err
 		}
+		releases = append(releases, newReleases...)
 
 		// Find or create the relation between monl and pkg.
 		monpkg, err := stores.Monpkgs.FindOrCreate(ctx, &model.Monpkg{
@@ -168,10 +179,10 @@ func (m *MonlCrawler) Run(ctx context.Context) ([]Job, error) {
 		}
 
 		// Save for derived relation.
-		if m.parentID != "" {
+		if m.ParentID != "" {
 			// Search for relation.
 			found, err := stores.Monpkgs.List(ctx, &store.MonpkgOpts{
-				MonlIDs: []string{m.parentID},
+				MonlIDs: []string{m.ParentID},
 				PkgIDs:  []string{pkg.ID},
 			})
 			if err != nil {
@@ -183,7 +194,7 @@ func (m *MonlCrawler) Run(ctx context.Context) ([]Job, error) {
 
 			// Insert as derived if not existed.
 			rel := &model.Monpkg{
-				MonlID: m.parentID,
+				MonlID: m.ParentID,
 				PkgID:  pkg.ID,
 				Kind:   model.MonpkgDerived,
 			}
@@ -215,6 +226,10 @@ func (m *MonlCrawler) Run(ctx context.Context) ([]Job, error) {
 		}
 	}
 
+	if len(releases) > 0 {
+		jobs = append(jobs, NewNotifyRelease(releases.Keys()))
+	}
+
 	m.monl.FetchedAt = field.Now()
 	err := stores.Monls.Update(ctx, m.monl)
 	if err != nil {
@@ -0,0 +1,228 @@
+package job
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/webhook"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/pinmonl/pinmonl/store/storeutils"
+)
+
+// NotifyRelease matches the new releases with the notify rules and
+// creates the webhook deliveries.
+type NotifyRelease struct {
+	StatIDs []string
+}
+
+func init() {
+	Register("notify_release", func() Job { return &NotifyRelease{} })
+}
+
+func NewNotifyRelease(statIDs []string) *NotifyRelease {
+	return &NotifyRelease{
+		StatIDs: statIDs,
+	}
+}
+
+func (n *NotifyRelease) String() string {
+	return "notify_release"
+}
+
+// Describe identifies the job by the digest of stat IDs, so that the
+// key stays bounded however many releases are found. The IDs are kept
+// in the payload.
+func (n *NotifyRelease) Describe() []string {
+	return []string{
+		n.String(),
+		statIDsDigest(n.StatIDs),
+	}
+}
+
+// statIDsDigest returns the hex-encoded SHA-256 of the sorted IDs.
+func statIDsDigest(ids []string) string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (n *NotifyRelease) Target() model.Morphable {
+	return nil
+}
+
+func (n *NotifyRelease) RunAt() time.Time {
+	return time.Time{}
+}
+
+func (n *NotifyRelease) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
+func (n *NotifyRelease) PreRun(ctx context.Context) error {
+	return nil
+}
+
+func (n *NotifyRelease) Run(ctx context.Context) ([]Job, error) {
+	stores := StoresFrom(ctx)
+	if stores == nil {
+		return nil, ErrNoStores
+	}
+
+	stats, err := stores.Stats.FindMany(ctx, n.StatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	notices, err := storeutils.MatchReleaseRules(ctx, storeutils.NotifyStores{
+		Monpkgs:     stores.Monpkgs,
+		NotifyRules: stores.NotifyRules,
+		Pinls:       stores.Pinls,
+		Pkgs:        stores.Pkgs,
+		Taggables:   stores.Taggables,
+	}, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0)
+	for _, notice := range notices {
+		// Skip if the release has been delivered by the rule.
+		found, err := stores.NotifyDeliveries.Count(ctx, &store.NotifyDeliveryOpts{
+			RuleIDs: []string{notice.Rule.ID},
+			StatIDs: []string{notice.Stat.ID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found > 0 {
+			continue
+		}
+
+		payload, err := notice.Payload()
+		if err != nil {
+			return nil, err
+		}
+		delivery := &model.NotifyDelivery{
+			RuleID:  notice.Rule.ID,
+			StatID:  notice.Stat.ID,
+			Event:   storeutils.NotifyEventRelease,
+			Payload: string(payload),
+			Status:  model.DeliveryPending,
+		}
+		if err := stores.NotifyDeliveries.Create(ctx, delivery); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, NewDeliverWebhook(delivery.ID))
+	}
+	return jobs, nil
+}
+
+var _ Job = &NotifyRelease{}
+
+// DeliverWebhook posts the payload of delivery to the rule url.
+//
+// The request is sent in PreRun so that the delivery result is kept
+// regardless of the transaction, and the job is retried by the queue
+// when the receiver fails.
+type DeliverWebhook struct {
+	DeliveryID string
+}
+
+func init() {
+	Register("deliver_webhook", func() Job { return &DeliverWebhook{} })
+}
+
+func NewDeliverWebhook(deliveryID string) *DeliverWebhook {
+	return &DeliverWebhook{
+		DeliveryID: deliveryID,
+	}
+}
+
+func (d *DeliverWebhook) String() string {
+	return "deliver_webhook"
+}
+
+func (d *DeliverWebhook) Describe() []string {
+	return []string{
+		d.String(),
+		d.DeliveryID,
+	}
+}
+
+func (d *DeliverWebhook) Target() model.Morphable {
+	return model.NotifyDelivery{ID: d.DeliveryID}
+}
+
+func (d *DeliverWebhook) RunAt() time.Time {
+	return time.Time{}
+}
+
+func (d *DeliverWebhook) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
+func (d *DeliverWebhook) PreRun(ctx context.Context) error {
+	stores := StoresFrom(ctx)
+	if stores == nil {
+		return ErrNoStores
+	}
+
+	delivery, err := stores.NotifyDeliveries.Find(ctx, d.DeliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery == nil || delivery.Status == model.DeliveryDelivered {
+		return nil
+	}
+	rule, err := stores.NotifyRules.Find(ctx, delivery.RuleID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return nil
+	}
+
+	code, senderr := webhook.Send(ctx, nil, &webhook.Request{
+		URL:        rule.URL,
+		Secret:     rule.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       []byte(delivery.Payload),
+	})
+
+	delivery.Attempts++
+	delivery.ResponseCode = code
+	if senderr != nil {
+		delivery.Status = model.DeliveryFailed
+		delivery.Message = senderr.Error()
+	} else {
+		delivery.Status = model.DeliveryDelivered
+		delivery.Message = ""
+		delivery.DeliveredAt = field.Now()
+	}
+	if err := stores.NotifyDeliveries.Update(ctx, delivery); err != nil {
+		return err
+	}
+
+	// Client errors, except rate limit, would not recover by retry.
+	var serr *webhook.StatusError
+	if errors.As(senderr, &serr) && serr.Code >= 400 && serr.Code < 500 && serr.Code != http.StatusTooManyRequests {
+		return Permanent(senderr)
+	}
+	return senderr
+}
+
+func (d *DeliverWebhook) Run(ctx context.Context) ([]Job, error) {
+	return nil, nil
+}
+
+var _ Job = &DeliverWebhook{}
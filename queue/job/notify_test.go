@@ -0,0 +1,31 @@
+package job
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyReleaseDescribe(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("stat-id-%d", i)
+	}
+	key := strings.Join(NewNotifyRelease(ids).Describe(), "::")
+	assert.True(t, len(key) <= 250)
+
+	assert.Equal(t,
+		NewNotifyRelease([]string{"stat-id-1", "stat-id-2"}).Describe(),
+		NewNotifyRelease([]string{"stat-id-2", "stat-id-1"}).Describe())
+	assert.NotEqual(t,
+		NewNotifyRelease([]string{"stat-id-1"}).Describe(),
+		NewNotifyRelease([]string{"stat-id-1", "stat-id-2"}).Describe())
+
+	payload, err := Marshal(NewNotifyRelease(ids))
+	assert.Nil(t, err)
+	got, err := Restore("notify_release", payload)
+	assert.Nil(t, err)
+	assert.Equal(t, ids, got.(*NotifyRelease).StatIDs)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/card"
 	"github.com/pinmonl/pinmonl/pkgs/monlutils"
 	"github.com/pinmonl/pinmonl/store/storeutils"
 )
@@ -17,6 +18,10 @@ type PinlUpdated struct {
 	PinlID string
 }
 
+func init() {
+	Register("pinl_updated", func() Job { return &PinlUpdated{} })
+}
+
 func NewPinlUpdated(pinlID string) *PinlUpdated {
 	return &PinlUpdated{
 		PinlID: pinlID,
@@ -42,6 +47,10 @@ func (p *PinlUpdated) RunAt() time.Time {
 	return time.Time{}
 }
 
+func (p *PinlUpdated) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
 func (p *PinlUpdated) PreRun(ctx context.Context) error {
 	return nil
 }
@@ -70,8 +79,8 @@ func (p *PinlUpdated) Run(ctx context.Context) ([]Job, error) {
 		jobs = append(jobs, NewMonlCrawler(monl.ID))
 	}
 
-	pinl.MonlID = monl.ID
-	err = stores.Pinls.Update(ctx, pinl)
+	// The updated time is kept, e.g. restored from bookmarks or archive.
+	err = stores.Pinls.UpdateMonl(ctx, pinl.ID, monl.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -79,3 +88,81 @@ func (p *PinlUpdated) Run(ctx context.Context) ([]Job, error) {
 }
 
 var _ Job = &PinlUpdated{}
+
+// FetchPinlContent captures the page content of pinl for full-text
+// search.
+type FetchPinlContent struct {
+	PinlID string
+
+	content string
+}
+
+func init() {
+	Register("fetch_pinl_content", func() Job { return &FetchPinlContent{} })
+}
+
+func NewFetchPinlContent(pinlID string) *FetchPinlContent {
+	return &FetchPinlContent{
+		PinlID: pinlID,
+	}
+}
+
+func (f *FetchPinlContent) String() string {
+	return "fetch_pinl_content"
+}
+
+func (f *FetchPinlContent) Describe() []string {
+	return []string{
+		f.String(),
+		f.PinlID,
+	}
+}
+
+func (f *FetchPinlContent) Target() model.Morphable {
+	return model.Pinl{ID: f.PinlID}
+}
+
+func (f *FetchPinlContent) RunAt() time.Time {
+	return time.Time{}
+}
+
+func (f *FetchPinlContent) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
+func (f *FetchPinlContent) PreRun(ctx context.Context) error {
+	stores := StoresFrom(ctx)
+	if stores == nil {
+		return ErrNoStores
+	}
+
+	pinl, err := stores.Pinls.Find(ctx, f.PinlID)
+	if err != nil {
+		return err
+	}
+	if pinl == nil {
+		return nil
+	}
+
+	c, err := card.NewCard(pinl.URL)
+	if err != nil {
+		return err
+	}
+	f.content = c.Content()
+	return nil
+}
+
+func (f *FetchPinlContent) Run(ctx context.Context) ([]Job, error) {
+	stores := StoresFrom(ctx)
+	if stores == nil {
+		return nil, ErrNoStores
+	}
+
+	err := stores.Pinls.UpdateContent(ctx, f.PinlID, f.content)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+var _ Job = &FetchPinlContent{}
@@ -17,6 +17,10 @@ type PkgCrawler struct {
 	report provider.Report
 }
 
+func init() {
+	Register("pkg_self_update", func() Job { return &PkgCrawler{} })
+}
+
 func NewPkgCrawler(pkgID string) *PkgCrawler {
 	return &PkgCrawler{
 		PkgID: pkgID,
@@ -42,6 +46,10 @@ func (p *PkgCrawler) RunAt() time.Time {
 	return time.Time{}
 }
 
+func (p *PkgCrawler) RetryPolicy() RetryPolicy {
+	return DefaultRetryPolicy
+}
+
 func (p *PkgCrawler) PreRun(ctx context.Context) error {
 	stores := StoresFrom(ctx)
 	pkg, err := stores.Pkgs.Find(ctx, p.PkgID)
@@ -70,8 +78,14 @@ func (p *PkgCrawler) PreRun(ctx context.Context) error {
 func (p *PkgCrawler) Run(ctx context.Context) ([]Job, error) {
 	stores := StoresFrom(ctx)
 	defer p.report.Close()
-	_, _, err := storeutils.SaveProviderReport(ctx, stores.Pkgs, stores.Stats, p.report, true)
-	return nil, err
+	_, _, releases, err := storeutils.SaveProviderReport(ctx, stores.Pkgs, stores.Stats, p.report, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) > 0 {
+		return []Job{NewNotifyRelease(releases.Keys())}, nil
+	}
+	return nil, nil
 }
 
 var _ Job = &PkgCrawler{}
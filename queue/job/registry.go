@@ -0,0 +1,48 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]func() Job)
+)
+
+// Register makes the job restorable by its name, which is
+// the value of String().
+func Register(name string, fn func() Job) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = fn
+}
+
+// Marshal encodes the exported fields of the job.
+func Marshal(job Job) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Restore creates the job by name and decodes the payload
+// produced by Marshal.
+func Restore(name, payload string) (Job, error) {
+	registryMu.RLock()
+	fn, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("job: %q is not registered", name)
+	}
+
+	job := fn()
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), job); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
@@ -0,0 +1,34 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		job Job
+	}{
+		{NewPinlUpdated("pinl-id-1")},
+		{NewFetchPinlContent("pinl-id-1")},
+		{NewMonlCrawler("monl-id-1").WithParentID("monl-id-2")},
+		{NewPkgCrawler("pkg-id-1")},
+		{NewFetchMonl("monl-id-1")},
+		{NewNotifyRelease([]string{"stat-id-1", "stat-id-2"})},
+		{NewDeliverWebhook("delivery-id-1")},
+		{NewSendDigest("digest-id-1")},
+	}
+
+	for _, test := range tests {
+		payload, err := Marshal(test.job)
+		assert.Nil(t, err)
+
+		got, err := Restore(test.job.String(), payload)
+		assert.Nil(t, err)
+		assert.Equal(t, test.job.Describe(), got.Describe())
+	}
+
+	_, err := Restore("unknown", "{}")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,92 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler"
+	"github.com/pinmonl/pinmonl/monler/provider"
+)
+
+// RetryPolicy defines how many times the failed job is tried
+// and how long to wait between attempts.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy retries 5 times with backoff from 30 seconds
+// up to 1 hour.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Backoff:     30 * time.Second,
+	MaxBackoff:  time.Hour,
+}
+
+// Delay reports the waiting time after the given attempt, which
+// doubles on every attempt.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// CanRetry reports whether the job can be tried again after
+// the attempt failed with err.
+func (p RetryPolicy) CanRetry(attempt int, err error) bool {
+	return attempt < p.MaxAttempts && IsRetryable(err)
+}
+
+// permanentErrors are the errors which would not recover by retry.
+var permanentErrors = []error{
+	sql.ErrNoRows,
+	monler.ErrUnknownProvider,
+	provider.ErrNotFound,
+	provider.ErrNotSupport,
+	provider.ErrNoPing,
+	ErrNoStores,
+	ErrNoPubsuber,
+	ErrNoExchangeManager,
+	ErrNoMailer,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks the error as not retryable.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsRetryable reports whether the error is transient, for example
+// the http error from the providers.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pe *permanentError
+	if errors.As(err, &pe) {
+		return false
+	}
+	for _, perr := range permanentErrors {
+		if errors.Is(err, perr) {
+			return false
+		}
+	}
+	return true
+}
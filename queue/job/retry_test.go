@@ -0,0 +1,38 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pinmonl/pinmonl/monler/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+		MaxBackoff:  10 * time.Second,
+	}
+
+	assert.Equal(t, time.Second, p.Delay(1))
+	assert.Equal(t, 2*time.Second, p.Delay(2))
+	assert.Equal(t, 8*time.Second, p.Delay(4))
+	assert.Equal(t, 10*time.Second, p.Delay(5))
+	assert.Equal(t, 10*time.Second, p.Delay(100))
+}
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3}
+	errHTTP := errors.New("http status 502")
+
+	assert.True(t, p.CanRetry(1, errHTTP))
+	assert.True(t, p.CanRetry(2, errHTTP))
+	assert.False(t, p.CanRetry(3, errHTTP))
+	assert.False(t, p.CanRetry(1, provider.ErrNotFound))
+	assert.False(t, p.CanRetry(1, fmt.Errorf("monl: %w", provider.ErrNotFound)))
+	assert.False(t, p.CanRetry(1, Permanent(errHTTP)))
+	assert.False(t, p.CanRetry(1, nil))
+}
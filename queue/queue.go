@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -10,12 +11,19 @@ import (
 	"github.com/pinmonl/pinmonl/database"
 	"github.com/pinmonl/pinmonl/exchange"
 	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/mailer"
 	"github.com/pinmonl/pinmonl/pubsub"
 	"github.com/pinmonl/pinmonl/queue/job"
 	"github.com/pinmonl/pinmonl/store"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	ErrJobNotDead = errors.New("queue: job is not dead")
+	ErrJobPending = errors.New("queue: job is pending")
+)
+
 type Manager struct {
 	*sync.Mutex
 	queue     chan *workerJob
@@ -24,9 +32,17 @@ type Manager struct {
 	txer      database.Txer
 	errchs    map[string][]chan error
 
+	persist    bool
+	wake       chan struct{}
+	stop       chan struct{}
+	stopOnce   *sync.Once
+	staleAfter time.Duration
+	retention  time.Duration
+
 	stores   *store.Stores
 	exchange *exchange.Manager
 	hub      pubsub.Pubsuber
+	mailer   *mailer.Mailer
 }
 
 func NewManager(txer database.Txer, maxJob, workerN int) (*Manager, error) {
@@ -44,40 +60,120 @@ func NewManager(txer database.Txer, maxJob, workerN int) (*Manager, error) {
 		readyPool: readyPool,
 		workers:   workers,
 		errchs:    make(map[string][]chan error),
+
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		stopOnce:   &sync.Once{},
+		staleAfter: 30 * time.Minute,
 	}, nil
 }
 
-func (m *Manager) Start() error {
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
+// Start runs the workers until ctx is done or Stop is called. The
+// running jobs are interrupted by ctx only, and Start returns after
+// they end.
+func (m *Manager) Start(ctx context.Context) error {
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	go func() {
-		m.start()
-		wg.Done()
+		select {
+		case <-m.stop:
+			cancel()
+		case <-loopCtx.Done():
+		}
 	}()
 
-	for _, w := range m.workers {
+	wg := &sync.WaitGroup{}
+	if m.persisted() {
+		wg.Add(1)
+		go func() {
+			m.poll(loopCtx)
+			wg.Done()
+		}()
+	}
+	if m.stores != nil && m.retention > 0 {
 		wg.Add(1)
 		go func() {
-			w.start()
+			m.prune(loopCtx)
 			wg.Done()
 		}()
 	}
 
+	for _, w := range m.workers {
+		wg.Add(1)
+		go func(w worker) {
+			w.start(ctx)
+			wg.Done()
+		}(w)
+	}
+
+	m.start(loopCtx)
+
+	// Stop the workers, they exit after the running jobs.
+	for _, w := range m.workers {
+		close(w.assigned)
+	}
 	wg.Wait()
+
+	m.release()
 	return nil
 }
 
-func (m *Manager) start() error {
+// Stop stops taking the queued jobs and lets the running jobs end,
+// unless the context of Start is done.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *Manager) start(ctx context.Context) error {
 	for {
 		select {
-		case job := <-m.queue:
-			w := <-m.readyPool
-			w <- job
+		case wjob := <-m.queue:
+			select {
+			case w := <-m.readyPool:
+				w <- wjob
+			case <-ctx.Done():
+				m.releaseJob(wjob)
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
 		}
 	}
-	return nil
 }
 
+// release puts the claimed jobs which are not started back
+// to the database queue.
+func (m *Manager) release() {
+	if !m.persisted() {
+		return
+	}
+
+	for {
+		select {
+		case wjob := <-m.queue:
+			m.releaseJob(wjob)
+		default:
+			return
+		}
+	}
+}
+
+// releaseJob puts the claimed job back to the database queue.
+func (m *Manager) releaseJob(wjob *workerJob) {
+	if !m.persisted() {
+		return
+	}
+	if err := wjob.releaseRecord(context.Background()); err != nil {
+		logrus.Debugf("queue: job %s release err(%s)", wjob.job.Describe(), err)
+	}
+}
+
+// Add queues the job and returns the channel which receives the result
+// when the job is done. In persisted mode, the job may be claimed by any
+// instance, so the channel receives once the job is persisted, use Run
+// to wait for the result instead.
 func (m *Manager) Add(job job.Job) <-chan error {
 	ctx := context.TODO()
 	has, cherr := m.enqueue(job)
@@ -85,28 +181,292 @@ func (m *Manager) Add(job job.Job) <-chan error {
 		return cherr
 	}
 
+	if m.persisted() {
+		err := m.addPending(ctx, job)
+		if err != nil {
+			logrus.Debugf("queue: job %s persist err(%s)", job.Describe(), err)
+		}
+		if qerr := m.dequeue(job, err); qerr != nil {
+			logrus.Debugf("queue: dequeue err(%v)", qerr)
+		}
+		return cherr
+	}
+
 	wjob := newWorkerJob(m, job)
 	if err := wjob.startRecord(ctx); err != nil {
-		cherr <- err
-		return cherr
+		logrus.Debugf("queue: job %s record err(%s)", job.Describe(), err)
 	}
 
-	go func() {
-		if time.Now().Before(job.RunAt()) {
-			d := time.Until(job.RunAt())
-			logrus.Debugf("queue: job %s will be added after %s", job.Describe(), d)
+	go m.dispatch(wjob)
+	return cherr
+}
+
+// Run runs the job in the caller without retry and returns the result.
+// If the same job is running in this instance, its result is returned
+// instead.
+func (m *Manager) Run(ctx context.Context, j job.Job) error {
+	has, cherr := m.enqueue(j)
+	if has {
+		return <-cherr
+	}
+
+	wjob := newWorkerJob(m, j)
+	wjob.noRetry = true
+	if err := wjob.startRecord(ctx); err != nil {
+		logrus.Debugf("queue: job %s record err(%s)", j.Describe(), err)
+	}
+	err := wjob.run(ctx)
+	if qerr := m.dequeue(j, err); qerr != nil {
+		logrus.Debugf("queue: dequeue err(%v)", qerr)
+	}
+	return err
+}
+
+// Persisted reports whether the jobs are kept in the database.
+func (m *Manager) Persisted() bool {
+	return m.persisted()
+}
+
+// dispatch sends the job to the queue at its RunAt and sends
+// again whenever the job is rescheduled for retry.
+func (m *Manager) dispatch(wjob *workerJob) {
+	for {
+		if d := time.Until(wjob.RunAt()); d > 0 {
+			logrus.Debugf("queue: job %s will be added after %s", wjob.job.Describe(), d)
 			<-time.After(d)
 		}
-		logrus.Debugf("queue: added job %s", job.Describe())
+		logrus.Debugf("queue: added job %s", wjob.job.Describe())
 		m.queue <- wjob
 
-		// Wait until job is completed.
+		// Wait until job is completed. The waiters receive the result
+		// of each attempt, so they are not blocked by the retries.
 		err := <-wjob.done
-		if qerr := m.dequeue(job, err); qerr != nil {
+		if qerr := m.respond(wjob.job, err, !wjob.retrying); qerr != nil {
 			logrus.Debugf("queue: dequeue err(%v)", qerr)
 		}
-	}()
-	return cherr
+		if wjob.retrying {
+			continue
+		}
+		return
+	}
+}
+
+// Requeue runs the dead job again from the first attempt.
+func (m *Manager) Requeue(ctx context.Context, record *model.Job) error {
+	if record.Status != model.JobDead {
+		return ErrJobNotDead
+	}
+	j, err := job.Restore(record.Name, record.Payload)
+	if err != nil {
+		return err
+	}
+
+	pending, err := m.hasPending(ctx, j)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrJobPending
+	}
+
+	record2 := *record
+	record2.Status = model.JobQueued
+	record2.Message = ""
+	record2.Attempts = 0
+	record2.RunAt = field.Now()
+	record2.StartedAt = field.Time{}
+	record2.EndedAt = field.Time{}
+	record2.Duration = 0
+	if m.stores != nil {
+		if err := m.stores.Jobs.Update(ctx, &record2); err != nil {
+			return err
+		}
+	}
+	*record = record2
+
+	if m.persisted() {
+		m.notify()
+		return nil
+	}
+
+	m.enqueue(j)
+	wjob := newWorkerJob(m, j)
+	wjob.record = &record2
+	go m.dispatch(wjob)
+	return nil
+}
+
+// hasPending reports whether the job is waiting or running.
+func (m *Manager) hasPending(ctx context.Context, j job.Job) (bool, error) {
+	k := m.jobKey(j)
+	m.Lock()
+	_, ok := m.errchs[k]
+	m.Unlock()
+	if ok {
+		return true, nil
+	}
+	if !m.persisted() {
+		return false, nil
+	}
+
+	found, err := m.stores.Jobs.List(ctx, &store.JobOpts{
+		Keys:     []string{k},
+		Statuses: []model.JobStatus{model.JobQueued},
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(found) > 0, nil
+}
+
+func (m *Manager) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// addPending saves the job as queued unless the same job is
+// already waiting in the database.
+func (m *Manager) addPending(ctx context.Context, j job.Job) error {
+	found, err := m.stores.Jobs.List(ctx, &store.JobOpts{
+		Keys:     []string{m.jobKey(j)},
+		Statuses: []model.JobStatus{model.JobQueued},
+	})
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		wjob := newWorkerJob(m, j)
+		if err := wjob.createRecord(ctx, model.JobQueued); err != nil {
+			return err
+		}
+		logrus.Debugf("queue: persisted job %s", j.Describe())
+	}
+
+	m.notify()
+	return nil
+}
+
+// poll loads the queued jobs from the database and runs them
+// as many as the queue can hold.
+func (m *Manager) poll(ctx context.Context) {
+	requeue := func() {
+		n, err := m.stores.Jobs.RequeueStale(ctx, time.Now().Add(-m.staleAfter), m.runningKeys())
+		if err != nil {
+			logrus.Debugf("queue: requeue err(%s)", err)
+		} else if n > 0 {
+			logrus.Debugf("queue: requeued %d stale jobs", n)
+		}
+	}
+
+	requeue()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	staleTicker := time.NewTicker(m.staleAfter)
+	defer staleTicker.Stop()
+	for {
+		if err := m.claim(ctx); err != nil {
+			logrus.Debugf("queue: claim err(%s)", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.wake:
+		case <-staleTicker.C:
+			requeue()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// prune deletes the history of the ended jobs older than retention
+// hourly.
+func (m *Manager) prune(ctx context.Context) {
+	deleteEnded := func() {
+		n, err := m.stores.Jobs.DeleteEnded(ctx, time.Now().Add(-m.retention))
+		if err != nil {
+			logrus.Debugf("queue: prune err(%s)", err)
+		} else if n > 0 {
+			logrus.Debugf("queue: pruned %d ended jobs", n)
+		}
+	}
+
+	deleteEnded()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deleteEnded()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// claim marks the due jobs as in progress and sends them to the queue.
+func (m *Manager) claim(ctx context.Context) error {
+	limit := cap(m.queue) - len(m.queue)
+	if limit <= 0 {
+		return nil
+	}
+
+	var (
+		records model.JobList
+		outerr  error
+	)
+	txerr := m.txer.TxFunc(ctx, func(ctx context.Context) bool {
+		// Skip the jobs running in this instance.
+		records, outerr = m.stores.Jobs.ClaimPending(ctx, time.Now(), uint64(limit), m.runningKeys())
+		return outerr == nil
+	})
+	if outerr != nil {
+		return outerr
+	}
+	if txerr != nil {
+		return txerr
+	}
+
+	for _, record := range records {
+		j, err := job.Restore(record.Name, record.Payload)
+		if err != nil {
+			wjob := &workerJob{stores: m.stores, record: record, started: time.Now()}
+			wjob.endRecord(ctx, model.JobFailed, err.Error())
+			logrus.Debugf("queue: job %s restore err(%s)", record.Key, err)
+			continue
+		}
+
+		m.enqueue(j)
+		wjob := newWorkerJob(m, j)
+		wjob.record = record
+		wjob.attempts = record.Attempts
+		logrus.Debugf("queue: claimed job %s", j.Describe())
+		m.queue <- wjob
+
+		// The retry is saved as queued record and may be claimed
+		// by another instance, so it is dequeued anyway.
+		go func() {
+			err := <-wjob.done
+			if qerr := m.dequeue(wjob.job, err); qerr != nil {
+				logrus.Debugf("queue: dequeue err(%v)", qerr)
+			}
+		}()
+	}
+	return nil
+}
+
+// runningKeys returns the keys of the jobs waiting or running in this
+// instance.
+func (m *Manager) runningKeys() []string {
+	m.Lock()
+	defer m.Unlock()
+	keys := make([]string, 0, len(m.errchs))
+	for k := range m.errchs {
+		keys = append(keys, k)
+	}
+	return keys
 }
 
 func (m *Manager) enqueue(job job.Job) (bool, chan error) {
@@ -115,16 +475,18 @@ func (m *Manager) enqueue(job job.Job) (bool, chan error) {
 
 	ch := make(chan error)
 	k := m.jobKey(job)
-	has := false
-	if chs, ok := m.errchs[k]; ok && len(chs) > 0 {
-		has = true
-	}
-
+	_, has := m.errchs[k]
 	m.errchs[k] = append(m.errchs[k], ch)
 	return has, ch
 }
 
 func (m *Manager) dequeue(job job.Job, err error) error {
+	return m.respond(job, err, true)
+}
+
+// respond sends err to the waiters of the job. The job is kept as
+// pending unless done, which is the case of retry.
+func (m *Manager) respond(job job.Job, err error, done bool) error {
 	m.Lock()
 	defer m.Unlock()
 	k := m.jobKey(job)
@@ -143,7 +505,11 @@ func (m *Manager) dequeue(job job.Job, err error) error {
 		default:
 		}
 	}
-	delete(m.errchs, k)
+	if done {
+		delete(m.errchs, k)
+	} else {
+		m.errchs[k] = nil
+	}
 	return nil
 }
 
@@ -166,6 +532,35 @@ func (m *Manager) Pubsuber(hub pubsub.Pubsuber) *Manager {
 	return m
 }
 
+func (m *Manager) Mailer(ml *mailer.Mailer) *Manager {
+	m.mailer = ml
+	return m
+}
+
+// Persist keeps the pending jobs in the database, so that they
+// survive restarts and can be shared by multiple instances.
+func (m *Manager) Persist(enabled bool) *Manager {
+	m.persist = enabled
+	return m
+}
+
+// Retention keeps the history of the ended jobs for the duration,
+// zero keeps them forever.
+func (m *Manager) Retention(d time.Duration) *Manager {
+	m.retention = d
+	return m
+}
+
+func (m *Manager) persisted() bool {
+	return m.persist && m.stores != nil
+}
+
+// heartbeatEvery is the interval of heartbeat of the running jobs, which
+// is frequent enough to keep them from being requeued as stale.
+func (m *Manager) heartbeatEvery() time.Duration {
+	return m.staleAfter / 3
+}
+
 // worker is the job runner of the queue.
 type worker struct {
 	readyPool chan chan *workerJob
@@ -179,16 +574,17 @@ func newWorker(readyPool chan chan *workerJob) worker {
 	}
 }
 
-func (w worker) start() error {
+// start runs the assigned jobs with ctx until the assigned channel
+// is closed.
+func (w worker) start(ctx context.Context) error {
 	for {
 		w.readyPool <- w.assigned
-		select {
-		case job := <-w.assigned:
-			ctx := context.TODO()
-			job.Run(ctx)
+		job, ok := <-w.assigned
+		if !ok {
+			return nil
 		}
+		job.Run(ctx)
 	}
-	return nil
 }
 
 // workerJob handles the returned data after job is done.
@@ -198,9 +594,16 @@ type workerJob struct {
 	stores   *store.Stores
 	exchange *exchange.Manager
 	hub      pubsub.Pubsuber
+	mailer   *mailer.Mailer
 	job      job.Job
 	record   *model.Job
+	started  time.Time
 	done     chan error
+
+	attempts int
+	runAt    time.Time
+	retrying bool
+	noRetry  bool
 }
 
 func newWorkerJob(mgr *Manager, job job.Job) *workerJob {
@@ -210,6 +613,7 @@ func newWorkerJob(mgr *Manager, job job.Job) *workerJob {
 		stores:   mgr.stores,
 		exchange: mgr.exchange,
 		hub:      mgr.hub,
+		mailer:   mgr.mailer,
 		job:      job,
 		done:     make(chan error, 1),
 	}
@@ -219,20 +623,44 @@ func (w *workerJob) Run(ctx context.Context) error {
 	return w.run(ctx)
 }
 
-func (w *workerJob) run(ctx context.Context) error {
+// RunAt reports the scheduled time of the retry or the job.
+func (w *workerJob) RunAt() time.Time {
+	if !w.runAt.IsZero() {
+		return w.runAt
+	}
+	return w.job.RunAt()
+}
+
+// run runs the job with ctx. The records are saved regardless of the
+// cancellation of ctx.
+func (w *workerJob) run(ctx context.Context) (outerr error) {
 	var (
-		nexts  []job.Job
-		outerr error
+		nexts []job.Job
+		rctx  = context.Background()
 	)
 
+	// Notifies the queue whenever the job ends.
+	defer func() {
+		w.done <- outerr
+	}()
+
 	// Inject services
 	ctx = job.WithStores(ctx, w.stores)
 	ctx = job.WithExchangeManager(ctx, w.exchange)
 	ctx = job.WithPubsuber(ctx, w.hub)
+	ctx = job.WithMailer(ctx, w.mailer)
+
+	w.started = time.Now()
+	w.attempts++
+	w.retrying = false
+	if err := w.markRecord(rctx); err != nil {
+		logrus.Debugf("queue: job %s record err(%s)", w.job.Describe(), err)
+	}
+	defer w.heartbeat()()
 
 	if err := w.job.PreRun(ctx); err != nil {
-		w.endRecord(ctx, model.JobFailed, err.Error())
 		logrus.Debugf("queue: job err with (%s)", err)
+		w.fail(ctx, err)
 		return err
 	}
 
@@ -245,34 +673,136 @@ func (w *workerJob) run(ctx context.Context) error {
 		}
 		nexts = jobs
 		logrus.Debugf("queue: job %s done", w.job.Describe())
+		w.touchRecord(ctx)
 		return true
 	})
 	if txerr != nil {
 		logrus.Debugf("queue: job %s tx err(%s)", w.job.Describe(), txerr)
-		return txerr
+		if outerr == nil {
+			outerr = txerr
+		}
 	}
 
 	for _, job := range nexts {
 		w.mgr.Add(job)
 	}
 
-	var (
-		status  = model.JobCompleted
-		message = ""
-	)
 	if outerr != nil {
-		status, message = model.JobFailed, outerr.Error()
+		w.fail(ctx, outerr)
+		return outerr
 	}
-	if err := w.endRecord(ctx, status, message); err != nil {
-		return err
+	if err := w.endRecord(rctx, model.JobCompleted, ""); err != nil {
+		logrus.Debugf("queue: job %s record err(%s)", w.job.Describe(), err)
 	}
 	return nil
 }
 
+// heartbeat updates the heartbeat of the persisted record periodically
+// until the returned func is called, so that the running job is not
+// requeued as stale by other instances. On sqlite3, the heartbeat waits
+// for the lock held by the transaction of job, which is covered by
+// touchRecord.
+func (w *workerJob) heartbeat() (stop func()) {
+	if w.record == nil || w.stores == nil || !w.mgr.persisted() {
+		return func() {}
+	}
+
+	var (
+		id   = w.record.ID
+		done = make(chan struct{})
+	)
+	go func() {
+		ticker := time.NewTicker(w.mgr.heartbeatEvery())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.stores.Jobs.Heartbeat(context.Background(), id, time.Now()); err != nil {
+					logrus.Debugf("queue: job %s heartbeat err(%s)", w.job.Describe(), err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// touchRecord updates the heartbeat within the transaction of job, so
+// that the heartbeat blocked by a long transaction is up to date once
+// the transaction commits.
+func (w *workerJob) touchRecord(ctx context.Context) {
+	if w.record == nil || w.stores == nil || !w.mgr.persisted() {
+		return
+	}
+	if err := w.stores.Jobs.Heartbeat(ctx, w.record.ID, time.Now()); err != nil {
+		logrus.Debugf("queue: job %s heartbeat err(%s)", w.job.Describe(), err)
+	}
+}
+
+// fail reschedules the job if its retry policy allows, otherwise
+// the job ends as failed, or dead if all attempts are used. The job
+// interrupted by ctx is put back to the database queue without using
+// an attempt if persisted, otherwise it ends as failed.
+func (w *workerJob) fail(ctx context.Context, err error) {
+	interrupted := ctx.Err() != nil
+	ctx = context.Background()
+	if interrupted {
+		logrus.Debugf("queue: job %s interrupted", w.job.Describe())
+		if w.mgr.persisted() {
+			w.mgr.releaseJob(w)
+			return
+		}
+		if rerr := w.endRecord(ctx, model.JobFailed, err.Error()); rerr != nil {
+			logrus.Debugf("queue: job %s record err(%s)", w.job.Describe(), rerr)
+		}
+		return
+	}
+
+	policy := w.job.RetryPolicy()
+	if !w.noRetry && policy.CanRetry(w.attempts, err) {
+		w.retrying = true
+		w.runAt = time.Now().Add(policy.Delay(w.attempts))
+		logrus.Debugf("queue: job %s will retry at %s", w.job.Describe(), w.runAt)
+		if rerr := w.retryRecord(ctx, err.Error()); rerr != nil {
+			logrus.Debugf("queue: job %s record err(%s)", w.job.Describe(), rerr)
+		}
+		return
+	}
+
+	status := model.JobFailed
+	if job.IsRetryable(err) {
+		status = model.JobDead
+	}
+	if rerr := w.endRecord(ctx, status, err.Error()); rerr != nil {
+		logrus.Debugf("queue: job %s record err(%s)", w.job.Describe(), rerr)
+	}
+}
+
+// startRecord saves the job into history when it is added to the queue.
 func (w *workerJob) startRecord(ctx context.Context) error {
+	return w.createRecord(ctx, model.JobInProgress)
+}
+
+// createRecord saves the job with the payload for restoring.
+func (w *workerJob) createRecord(ctx context.Context, status model.JobStatus) error {
+	payload, err := job.Marshal(w.job)
+	if err != nil {
+		return err
+	}
+
+	runAt := w.job.RunAt()
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
 	record := &model.Job{
 		Name:     w.job.String(),
 		Describe: strings.Join(w.job.Describe(), ", "),
+		Key:      w.mgr.jobKey(w.job),
+		Payload:  payload,
+		Status:   status,
+		RunAt:    field.Time(runAt.UTC()),
 	}
 
 	if target := w.job.Target(); target != nil {
@@ -280,29 +810,79 @@ func (w *workerJob) startRecord(ctx context.Context) error {
 		record.TargetName = target.MorphName()
 	}
 
-	// err := w.stores.Jobs.Create(ctx, record)
-	// if err != nil {
-	// 	return err
-	// }
+	if w.stores != nil {
+		err := w.stores.Jobs.Create(ctx, record)
+		if err != nil {
+			return err
+		}
+	}
 	w.record = record
 	return nil
 }
 
+// markRecord sets the queued record in progress when the job starts.
+func (w *workerJob) markRecord(ctx context.Context) error {
+	if w.record == nil || w.record.Status != model.JobQueued {
+		return nil
+	}
+
+	record := *w.record
+	record.Status = model.JobInProgress
+	return w.saveRecord(ctx, &record)
+}
+
+// releaseRecord puts the record back to queue as not started.
+func (w *workerJob) releaseRecord(ctx context.Context) error {
+	if w.record == nil {
+		return nil
+	}
+
+	record := *w.record
+	record.Status = model.JobQueued
+	record.StartedAt = field.Time{}
+	return w.saveRecord(ctx, &record)
+}
+
+// retryRecord puts the record back to queue with the time of
+// next attempt.
+func (w *workerJob) retryRecord(ctx context.Context, message string) error {
+	if w.record == nil {
+		return nil
+	}
+
+	record := *w.record
+	record.Status = model.JobQueued
+	record.Message = message
+	record.Attempts = w.attempts
+	record.RunAt = field.Time(w.runAt.UTC())
+	record.StartedAt = field.Time(w.started.Round(time.Second).UTC())
+	record.Duration = time.Since(w.started).Milliseconds()
+	return w.saveRecord(ctx, &record)
+}
+
+// endRecord updates the result and duration of the job.
 func (w *workerJob) endRecord(ctx context.Context, status model.JobStatus, message string) error {
 	if w.record == nil {
 		return nil
 	}
 
 	record := *w.record
-	// record.Status = status
-	// record.Message = message
-	// record.EndedAt = field.Now()
-	// err := w.stores.Jobs.Update(ctx, &record)
-	// if err != nil {
-	// 	w.done <- err
-	// 	return err
-	// }
-	w.done <- nil
-	w.record = &record
+	record.Status = status
+	record.Message = message
+	record.StartedAt = field.Time(w.started.Round(time.Second).UTC())
+	record.EndedAt = field.Now()
+	record.Duration = time.Since(w.started).Milliseconds()
+	record.Attempts = w.attempts
+	return w.saveRecord(ctx, &record)
+}
+
+func (w *workerJob) saveRecord(ctx context.Context, record *model.Job) error {
+	if w.stores != nil {
+		err := w.stores.Jobs.Update(ctx, record)
+		if err != nil {
+			return err
+		}
+	}
+	w.record = record
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/pinmonl/pinmonl/exchange"
+	"github.com/pinmonl/pinmonl/model/field"
 	"github.com/pinmonl/pinmonl/queue"
 	"github.com/pinmonl/pinmonl/queue/job"
 	"github.com/pinmonl/pinmonl/store"
@@ -18,10 +19,10 @@ type ClientRunner struct {
 	Stores   *store.Stores
 
 	ExchangeEnabled bool
+	DigestEnabled   bool
 }
 
-func (c *ClientRunner) Start() error {
-	ctx := context.TODO()
+func (c *ClientRunner) Start(ctx context.Context) error {
 	if err := c.bootstrap(ctx); err != nil {
 		return err
 	}
@@ -36,6 +37,14 @@ func (c *ClientRunner) Start() error {
 		}()
 	}
 
+	if c.DigestEnabled {
+		wg.Add(1)
+		go func() {
+			c.regularSendDigests(ctx)
+			wg.Done()
+		}()
+	}
+
 	wg.Wait()
 	return nil
 }
@@ -110,8 +119,45 @@ func (c *ClientRunner) keepExchangeAlive(ctx context.Context) error {
 			if c.Exchange.HasUser() {
 				c.Exchange.Alive()
 			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *ClientRunner) regularSendDigests(ctx context.Context) error {
+	ticker := time.NewTicker(time.Hour)
+	defer func() {
+		ticker.Stop()
+	}()
+	c.sendDigests(ctx, time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			c.sendDigests(ctx, now)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *ClientRunner) sendDigests(ctx context.Context, now time.Time) error {
+	dList, err := c.Stores.Digests.List(ctx, &store.DigestOpts{
+		Enabled: field.NewNullBool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for _, digest := range dList {
+		if !digest.IsDue(now) {
+			continue
 		}
+		c.Queue.Add(job.NewSendDigest(digest.ID))
+		n++
 	}
+	logrus.Debugf("runner: %d digests queued", n)
 	return nil
 }
 
@@ -1,5 +1,8 @@
 package runner
 
+import "context"
+
 type Runner interface {
-	Start() error
+	// Start runs the scheduled tasks until ctx is done.
+	Start(context.Context) error
 }
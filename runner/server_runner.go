@@ -16,8 +16,7 @@ type ServerRunner struct {
 	Stores *store.Stores
 }
 
-func (s *ServerRunner) Start() error {
-	ctx := context.TODO()
+func (s *ServerRunner) Start(ctx context.Context) error {
 	if err := s.bootstrap(ctx); err != nil {
 		return err
 	}
@@ -76,6 +75,8 @@ func (s *ServerRunner) regularUpdatePkgs(ctx context.Context) error {
 		case <-ticker.C:
 			before := time.Now().Add(-1 * interval)
 			s.updatePkgs(ctx, before)
+		case <-ctx.Done():
+			return nil
 		}
 	}
 }
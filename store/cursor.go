@@ -0,0 +1,195 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+// ErrInvalidCursor is returned if the cursor cannot be decoded or does
+// not match the sort orders.
+var ErrInvalidCursor = errors.New("store: invalid cursor")
+
+// Cursor is the position of a row in the sorted list. Keys are the
+// values of sort orders and ID breaks the tie, so that the next page
+// is not shifted by the rows added in between. The page before the
+// row is selected if Before is true.
+type Cursor struct {
+	Keys   []interface{}
+	ID     string
+	Before bool
+}
+
+// CreatedCursor returns the encoded cursor of row in the list sorted by
+// (created_at, id), which is the default order of lists.
+func CreatedCursor(createdAt field.Time, id string) string {
+	return Cursor{Keys: []interface{}{createdAt.Time().UTC()}, ID: id}.Encode()
+}
+
+// IDCursor returns the encoded cursor of row in the list sorted by id,
+// which is the default order of the lists without created_at.
+func IDCursor(id string) string {
+	return Cursor{ID: id}.Encode()
+}
+
+type cursorKey struct {
+	Time *time.Time `json:"t,omitempty"`
+	Num  *float64   `json:"n,omitempty"`
+	Str  *string    `json:"s,omitempty"`
+}
+
+type cursorData struct {
+	Keys   []cursorKey `json:"k"`
+	ID     string      `json:"i"`
+	Before bool        `json:"b,omitempty"`
+}
+
+// Encode returns the opaque string of cursor.
+func (c Cursor) Encode() string {
+	data := cursorData{ID: c.ID, Before: c.Before, Keys: make([]cursorKey, len(c.Keys))}
+	for i, key := range c.Keys {
+		switch v := key.(type) {
+		case time.Time:
+			data.Keys[i].Time = &v
+		case int64:
+			n := float64(v)
+			data.Keys[i].Num = &n
+		case float64:
+			data.Keys[i].Num = &v
+		case []byte:
+			s := string(v)
+			data.Keys[i].Str = &s
+		case string:
+			data.Keys[i].Str = &v
+		}
+	}
+	b, _ := json.Marshal(data)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes the string returned by Cursor.Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var data cursorData
+	if err := json.Unmarshal(b, &data); err != nil || data.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{ID: data.ID, Before: data.Before, Keys: make([]interface{}, len(data.Keys))}
+	for i, key := range data.Keys {
+		switch {
+		case key.Time != nil:
+			c.Keys[i] = *key.Time
+		case key.Num != nil:
+			c.Keys[i] = *key.Num
+		case key.Str != nil:
+			c.Keys[i] = *key.Str
+		}
+	}
+	return c, nil
+}
+
+// sortKey is the expression of sort order.
+type sortKey struct {
+	expr string
+	args []interface{}
+	desc bool
+}
+
+func (k sortKey) orderBy() string {
+	if k.desc {
+		return k.expr + " DESC"
+	}
+	return k.expr + " ASC"
+}
+
+func (k sortKey) compare(op string, value interface{}) squirrel.Sqlizer {
+	args := append(append([]interface{}{}, k.args...), value)
+	return squirrel.Expr(k.expr+" "+op+" ?", args...)
+}
+
+// keysetAfter returns the condition of rows after the values of keys,
+// which are compared one by one until they are not equal.
+func keysetAfter(keys []sortKey, values []interface{}) squirrel.Sqlizer {
+	or := squirrel.Or{}
+	for i := range keys {
+		and := squirrel.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, keys[j].compare("=", values[j]))
+		}
+		if keys[i].desc {
+			and = append(and, keys[i].compare("<", values[i]))
+		} else {
+			and = append(and, keys[i].compare(">", values[i]))
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+// Values of created_at for the rows without it, which are formatted as
+// the timestamp bound by the SQLite driver.
+const nullCreatedAt = "'0001-01-01 00:00:00+00:00'"
+
+// createdKeys returns the keys of (created_at, id) of table.
+func createdKeys(table string, desc bool) []sortKey {
+	return []sortKey{
+		{expr: "COALESCE(" + table + ".created_at, " + nullCreatedAt + ")", desc: desc},
+		{expr: table + ".id", desc: desc},
+	}
+}
+
+// idKeys returns the key of id of table.
+func idKeys(table string) []sortKey {
+	return []sortKey{{expr: table + ".id"}}
+}
+
+// addCursor sorts by keys, which end with id, and selects the page of
+// cursor. The rows before cursor are sorted in reverse, which should
+// be reversed back after scan. Nil keys mean that the list is sorted
+// by other orders and cannot be paginated by cursor.
+func addCursor(b squirrel.SelectBuilder, keys []sortKey, cursor *Cursor) (squirrel.SelectBuilder, error) {
+	if cursor == nil {
+		for _, key := range keys {
+			b = b.OrderByClause(key.orderBy(), key.args...)
+		}
+		return b, nil
+	}
+	if len(keys) == 0 || len(cursor.Keys) != len(keys)-1 {
+		return b, ErrInvalidCursor
+	}
+
+	if cursor.Before {
+		reversed := make([]sortKey, len(keys))
+		for i, key := range keys {
+			key.desc = !key.desc
+			reversed[i] = key
+		}
+		keys = reversed
+	}
+	for _, key := range keys {
+		b = b.OrderByClause(key.orderBy(), key.args...)
+	}
+	values := append(append([]interface{}{}, cursor.Keys...), cursor.ID)
+	return b.Where(keysetAfter(keys, values)), nil
+}
+
+// reverseList reverses the list selected before cursor.
+func reverseList(list interface{}, cursor *Cursor) {
+	if cursor == nil || !cursor.Before {
+		return
+	}
+	n := reflect.ValueOf(list).Len()
+	swap := reflect.Swapper(list)
+	for i := 0; i < n/2; i++ {
+		swap(i, n-1-i)
+	}
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cursor Cursor
+		expect *Cursor
+	}{
+		{
+			cursor: Cursor{ID: "id-1"},
+			expect: &Cursor{ID: "id-1", Keys: []interface{}{}},
+		},
+		{
+			cursor: Cursor{ID: "id-1", Keys: []interface{}{now, int64(10), 1.5, []byte("a"), "b"}},
+			expect: &Cursor{ID: "id-1", Keys: []interface{}{now, float64(10), 1.5, "a", "b"}},
+		},
+		{
+			cursor: Cursor{ID: "id-1", Keys: []interface{}{now}, Before: true},
+			expect: &Cursor{ID: "id-1", Keys: []interface{}{now}, Before: true},
+		},
+	}
+	for _, test := range tests {
+		got, err := DecodeCursor(test.cursor.Encode())
+		assert.Nil(t, err)
+		assert.Equal(t, test.expect, got)
+	}
+
+	for _, s := range []string{"", "!!", "e30"} {
+		_, err := DecodeCursor(s)
+		assert.Equal(t, ErrInvalidCursor, err, s)
+	}
+}
+
+func TestAddCursor(t *testing.T) {
+	var (
+		base = squirrel.Select("*").From("jobs")
+		keys = createdKeys("jobs", true)
+		now  = time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		cursor *Cursor
+		query  string
+		args   []interface{}
+		err    error
+	}{
+		{
+			cursor: nil,
+			query:  "SELECT * FROM jobs ORDER BY COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') DESC, jobs.id DESC",
+		},
+		{
+			cursor: &Cursor{Keys: []interface{}{now}, ID: "job-id-1"},
+			query: "SELECT * FROM jobs WHERE ((COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') < ?) OR " +
+				"(COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') = ? AND jobs.id < ?)) " +
+				"ORDER BY COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') DESC, jobs.id DESC",
+			args: []interface{}{now, now, "job-id-1"},
+		},
+		{
+			cursor: &Cursor{Keys: []interface{}{now}, ID: "job-id-1", Before: true},
+			query: "SELECT * FROM jobs WHERE ((COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') > ?) OR " +
+				"(COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') = ? AND jobs.id > ?)) " +
+				"ORDER BY COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') ASC, jobs.id ASC",
+			args: []interface{}{now, now, "job-id-1"},
+		},
+		{
+			cursor: &Cursor{ID: "job-id-1"},
+			err:    ErrInvalidCursor,
+		},
+	}
+	for _, test := range tests {
+		b, err := addCursor(base, keys, test.cursor)
+		if test.err != nil {
+			assert.Equal(t, test.err, err)
+			continue
+		}
+		assert.Nil(t, err)
+		query, args, err := b.ToSql()
+		assert.Nil(t, err)
+		assert.Equal(t, test.query, query)
+		assert.Equal(t, test.args, args)
+	}
+
+	_, err := addCursor(base, nil, &Cursor{ID: "job-id-1"})
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	list := []string{"c", "b", "a"}
+	reverseList(list, &Cursor{ID: "job-id-1", Before: true})
+	assert.Equal(t, []string{"a", "b", "c"}, list)
+}
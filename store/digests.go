@@ -0,0 +1,229 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+type Digests struct {
+	*Store
+}
+
+type DigestOpts struct {
+	ListOpts
+	UserID  string
+	UserIDs []string
+	Enabled field.NullBool
+}
+
+func NewDigests(s *Store) *Digests {
+	return &Digests{s}
+}
+
+func (d Digests) table() string {
+	return "digests"
+}
+
+func (d *Digests) List(ctx context.Context, opts *DigestOpts) (model.DigestList, error) {
+	if opts == nil {
+		opts = &DigestOpts{}
+	}
+
+	qb := d.RunnableBuilder(ctx).
+		Select(d.columns()...).From(d.table())
+	qb = d.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(d.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	qb = addPagination(qb, opts)
+	rows, err := qb.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*model.Digest, 0)
+	for rows.Next() {
+		digest, err := d.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, digest)
+	}
+	reverseList(list, opts.Cursor)
+	return list, nil
+}
+
+func (d *Digests) Count(ctx context.Context, opts *DigestOpts) (int64, error) {
+	if opts == nil {
+		opts = &DigestOpts{}
+	}
+
+	qb := d.RunnableBuilder(ctx).
+		Select("count(*)").From(d.table())
+	qb = d.bindOpts(qb, opts)
+	row := qb.QueryRow()
+	var count int64
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (d *Digests) Find(ctx context.Context, id string) (*model.Digest, error) {
+	qb := d.RunnableBuilder(ctx).
+		Select(d.columns()...).From(d.table()).
+		Where("id = ?", id)
+	row := qb.QueryRow()
+	digest, err := d.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// FindUser finds the digest of the user.
+func (d *Digests) FindUser(ctx context.Context, userID string) (*model.Digest, error) {
+	qb := d.RunnableBuilder(ctx).
+		Select(d.columns()...).From(d.table()).
+		Where("user_id = ?", userID).
+		Limit(1)
+	row := qb.QueryRow()
+	digest, err := d.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+func (d Digests) bindOpts(b squirrel.SelectBuilder, opts *DigestOpts) squirrel.SelectBuilder {
+	if opts == nil {
+		return b
+	}
+
+	if opts.UserID != "" {
+		opts.UserIDs = append(opts.UserIDs, opts.UserID)
+	}
+	if len(opts.UserIDs) > 0 {
+		b = b.Where(squirrel.Eq{"user_id": opts.UserIDs})
+	}
+
+	if opts.Enabled.Valid {
+		b = b.Where("enabled = ?", opts.Enabled.Value())
+	}
+
+	return b
+}
+
+func (d Digests) columns() []string {
+	return []string{
+		d.table() + ".id",
+		d.table() + ".user_id",
+		d.table() + ".email",
+		d.table() + ".frequency",
+		d.table() + ".enabled",
+		d.table() + ".last_sent_at",
+		d.table() + ".created_at",
+		d.table() + ".updated_at",
+	}
+}
+
+func (d Digests) scanColumns(digest *model.Digest) []interface{} {
+	return []interface{}{
+		&digest.ID,
+		&digest.UserID,
+		&digest.Email,
+		&digest.Frequency,
+		&digest.Enabled,
+		&digest.LastSentAt,
+		&digest.CreatedAt,
+		&digest.UpdatedAt,
+	}
+}
+
+func (d Digests) scan(row database.RowScanner) (*model.Digest, error) {
+	var digest model.Digest
+	err := row.Scan(d.scanColumns(&digest)...)
+	if err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+func (d *Digests) Create(ctx context.Context, digest *model.Digest) error {
+	digest2 := *digest
+	digest2.ID = newID()
+	digest2.CreatedAt = timestamp()
+	digest2.UpdatedAt = timestamp()
+
+	qb := d.RunnableBuilder(ctx).
+		Insert(d.table()).
+		Columns(
+			"id",
+			"user_id",
+			"email",
+			"frequency",
+			"enabled",
+			"last_sent_at",
+			"created_at",
+			"updated_at").
+		Values(
+			digest2.ID,
+			digest2.UserID,
+			digest2.Email,
+			digest2.Frequency,
+			digest2.Enabled,
+			digest2.LastSentAt,
+			digest2.CreatedAt,
+			digest2.UpdatedAt)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*digest = digest2
+	return nil
+}
+
+func (d *Digests) Update(ctx context.Context, digest *model.Digest) error {
+	digest2 := *digest
+	digest2.UpdatedAt = timestamp()
+
+	qb := d.RunnableBuilder(ctx).
+		Update(d.table()).
+		Set("user_id", digest2.UserID).
+		Set("email", digest2.Email).
+		Set("frequency", digest2.Frequency).
+		Set("enabled", digest2.Enabled).
+		Set("last_sent_at", digest2.LastSentAt).
+		Set("updated_at", digest2.UpdatedAt).
+		Where("id = ?", digest2.ID)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*digest = digest2
+	return nil
+}
+
+func (d *Digests) Delete(ctx context.Context, id string) (int64, error) {
+	qb := d.RunnableBuilder(ctx).
+		Delete(d.table()).
+		Where("id = ?", id)
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pinmonl/pinmonl/database/dbtest"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigests(t *testing.T) {
+	db, mock, err := dbtest.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ctx := context.TODO()
+	s := NewStore(db)
+	digests := NewDigests(s)
+
+	t.Run("list", testDigestsList(ctx, digests, mock))
+	t.Run("count", testDigestsCount(ctx, digests, mock))
+	t.Run("find", testDigestsFind(ctx, digests, mock))
+	t.Run("findUser", testDigestsFindUser(ctx, digests, mock))
+	t.Run("create", testDigestsCreate(ctx, digests, mock))
+	t.Run("update", testDigestsUpdate(ctx, digests, mock))
+	t.Run("delete", testDigestsDelete(ctx, digests, mock))
+}
+
+func testDigestsList(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			prefix = "SELECT (.+) FROM digests"
+			opts   *DigestOpts
+			list   []*model.Digest
+			err    error
+		)
+
+		// Test nil opts.
+		opts = nil
+		mock.ExpectQuery(prefix).
+			WillReturnRows(sqlmock.NewRows(digests.columns()).
+				AddRow("digest-id-1", "user-id-1", "user@example.com", "daily", true, nil, nil, nil))
+		list, err = digests.List(ctx, opts)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, model.DailyDigest, list[0].Frequency)
+		}
+
+		// Test filter by enabled.
+		opts = &DigestOpts{
+			Enabled: field.NewNullBool(true),
+		}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE enabled = ?"), prefix)).
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows(digests.columns()))
+		_, err = digests.List(ctx, opts)
+		assert.Nil(t, err)
+	}
+}
+
+func testDigestsCount(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("SELECT count(*) FROM digests WHERE user_id IN (?)")
+			opts  *DigestOpts
+			count int64
+			err   error
+		)
+
+		opts = &DigestOpts{UserID: "user-id-1"}
+		mock.ExpectQuery(query).
+			WithArgs("user-id-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
+				AddRow(1))
+		count, err = digests.Count(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	}
+}
+
+func testDigestsFind(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query  = "SELECT (.+) FROM digests WHERE id = \\?"
+			id     string
+			digest *model.Digest
+			err    error
+		)
+
+		id = "digest-id-1"
+		mock.ExpectQuery(query).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(digests.columns()).
+				AddRow(id, "user-id-1", "user@example.com", "weekly", true, nil, nil, nil))
+		digest, err = digests.Find(ctx, id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, digest) {
+			assert.Equal(t, id, digest.ID)
+		}
+	}
+}
+
+func testDigestsFindUser(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query  = "SELECT (.+) FROM digests WHERE user_id = \\? LIMIT 1"
+			digest *model.Digest
+			err    error
+		)
+
+		mock.ExpectQuery(query).
+			WithArgs("user-id-2").
+			WillReturnRows(sqlmock.NewRows(digests.columns()))
+		digest, err = digests.FindUser(ctx, "user-id-2")
+		assert.Nil(t, err)
+		assert.Nil(t, digest)
+	}
+}
+
+func testDigestsCreate(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			digest *model.Digest
+			err    error
+		)
+
+		digest = &model.Digest{}
+		expectDigestsCreate(mock, digest)
+		err = digests.Create(ctx, digest)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, digest.ID)
+		assert.NotEmpty(t, digest.CreatedAt)
+	}
+}
+
+func expectDigestsCreate(mock sqlmock.Sqlmock, digest *model.Digest) {
+	mock.ExpectExec("INSERT INTO digests").
+		WithArgs(
+			sqlmock.AnyArg(),
+			digest.UserID,
+			digest.Email,
+			digest.Frequency,
+			digest.Enabled,
+			digest.LastSentAt,
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testDigestsUpdate(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			digest *model.Digest
+			err    error
+		)
+
+		digest = &model.Digest{ID: "digest-id-1"}
+		expectDigestsUpdate(mock, digest)
+		err = digests.Update(ctx, digest)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, digest.UpdatedAt)
+	}
+}
+
+func expectDigestsUpdate(mock sqlmock.Sqlmock, digest *model.Digest) {
+	mock.ExpectExec("UPDATE digests (.+) WHERE id = \\?").
+		WithArgs(
+			digest.UserID,
+			digest.Email,
+			digest.Frequency,
+			digest.Enabled,
+			digest.LastSentAt,
+			sqlmock.AnyArg(),
+			digest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testDigestsDelete(ctx context.Context, digests *Digests, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("DELETE FROM digests WHERE id = ?")
+			id    string
+			n     int64
+			err   error
+		)
+
+		id = "digest-id-1"
+		mock.ExpectExec(query).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		n, err = digests.Delete(ctx, id)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
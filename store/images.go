@@ -15,6 +15,7 @@ type Images struct {
 
 type ImageOpts struct {
 	ListOpts
+	IDs     []string
 	Targets model.MorphableList
 }
 
@@ -34,6 +35,10 @@ func (i *Images) List(ctx context.Context, opts *ImageOpts) (model.ImageList, er
 	qb := i.RunnableBuilder(ctx).
 		Select(i.columns()...).From(i.table())
 	qb = i.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(i.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -48,6 +53,7 @@ func (i *Images) List(ctx context.Context, opts *ImageOpts) (model.ImageList, er
 		}
 		list = append(list, image)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -88,6 +94,10 @@ func (i Images) bindOpts(b squirrel.SelectBuilder, opts *ImageOpts) squirrel.Sel
 		return b
 	}
 
+	if len(opts.IDs) > 0 {
+		b = b.Where(squirrel.Eq{"id": opts.IDs})
+	}
+
 	if len(opts.Targets) > 0 && !opts.Targets.IsMixed() {
 		b = b.Where("target_name = ?", opts.Targets.MorphName()).
 			Where(squirrel.Eq{"target_id": opts.Targets.MorphKeys()})
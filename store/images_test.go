@@ -62,6 +62,14 @@ func testImagesList(ctx context.Context, images *Images, mock sqlmock.Sqlmock) f
 			WillReturnRows(sqlmock.NewRows(images.columns()))
 		_, err = images.List(ctx, opts)
 		assert.Nil(t, err)
+
+		// Test filter by ids.
+		opts = &ImageOpts{IDs: []string{"image-id-1", "image-id-2"}}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE id IN (?,?)"), prefix)).
+			WithArgs("image-id-1", "image-id-2").
+			WillReturnRows(sqlmock.NewRows(images.columns()))
+		_, err = images.List(ctx, opts)
+		assert.Nil(t, err)
 	}
 }
 
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/pinmonl/pinmonl/database"
@@ -20,13 +21,23 @@ type JobOpts struct {
 	TargetIDs  []string
 	TargetName string
 	Names      []string
+	Keys       []string
 	Status     field.NullValue
+	Statuses   []model.JobStatus
 	NotEnded   field.NullBool
 
+	Orders []JobOrder
+
 	joinMonls bool
 	joinPkgs  bool
 }
 
+type JobOrder int
+
+const (
+	JobOrderByLatest JobOrder = iota
+)
+
 func NewJobs(s *Store) *Jobs {
 	return &Jobs{s}
 }
@@ -43,6 +54,10 @@ func (j *Jobs) List(ctx context.Context, opts *JobOpts) (model.JobList, error) {
 	qb := j.RunnableBuilder(ctx).
 		Select(j.columns()...).From(j.table())
 	qb = j.bindOpts(qb, opts)
+	qb, err := addCursor(qb, j.sortKeys(opts), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -57,6 +72,7 @@ func (j *Jobs) List(ctx context.Context, opts *JobOpts) (model.JobList, error) {
 		}
 		list = append(list, job)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -65,9 +81,12 @@ func (j *Jobs) Count(ctx context.Context, opts *JobOpts) (int64, error) {
 		opts = &JobOpts{}
 	}
 
+	o2 := *opts
+	o2.Orders = nil
+
 	qb := j.RunnableBuilder(ctx).
 		Select("count(*)").From(j.table())
-	qb = j.bindOpts(qb, opts)
+	qb = j.bindOpts(qb, &o2)
 	row := qb.QueryRow()
 	var count int64
 	err := row.Scan(&count)
@@ -112,12 +131,20 @@ func (j Jobs) bindOpts(b squirrel.SelectBuilder, opts *JobOpts) squirrel.SelectB
 		b = b.Where(squirrel.Eq{"name": opts.Names})
 	}
 
+	if len(opts.Keys) > 0 {
+		b = b.Where(squirrel.Eq{"job_key": opts.Keys})
+	}
+
 	if opts.Status.Valid {
 		if vs, ok := opts.Status.Value().(model.JobStatus); ok {
 			b = b.Where("status = ?", vs)
 		}
 	}
 
+	if len(opts.Statuses) > 0 {
+		b = b.Where(squirrel.Eq{"status": opts.Statuses})
+	}
+
 	if opts.NotEnded.Valid {
 		b = b.Where("ended_at IS NULL")
 	}
@@ -125,17 +152,36 @@ func (j Jobs) bindOpts(b squirrel.SelectBuilder, opts *JobOpts) squirrel.SelectB
 	return b
 }
 
+// sortKeys returns the keys of (created_at, id) in the direction of
+// orders.
+func (j Jobs) sortKeys(opts *JobOpts) []sortKey {
+	desc := false
+	for _, order := range opts.Orders {
+		if order == JobOrderByLatest {
+			desc = true
+		}
+	}
+	return createdKeys(j.table(), desc)
+}
+
 func (j Jobs) columns() []string {
 	return []string{
 		j.table() + ".id",
 		j.table() + ".name",
 		j.table() + ".describe",
+		j.table() + ".job_key",
+		j.table() + ".payload",
 		j.table() + ".target_id",
 		j.table() + ".target_name",
 		j.table() + ".status",
 		j.table() + ".message",
+		j.table() + ".attempts",
+		j.table() + ".run_at",
 		j.table() + ".created_at",
+		j.table() + ".started_at",
 		j.table() + ".ended_at",
+		j.table() + ".duration",
+		j.table() + ".heartbeat_at",
 	}
 }
 
@@ -144,12 +190,19 @@ func (j Jobs) scanColumns(job *model.Job) []interface{} {
 		&job.ID,
 		&job.Name,
 		&job.Describe,
+		&job.Key,
+		&job.Payload,
 		&job.TargetID,
 		&job.TargetName,
 		&job.Status,
 		&job.Message,
+		&job.Attempts,
+		&job.RunAt,
 		&job.CreatedAt,
+		&job.StartedAt,
 		&job.EndedAt,
+		&job.Duration,
+		&job.HeartbeatAt,
 	}
 }
 
@@ -173,22 +226,36 @@ func (j *Jobs) Create(ctx context.Context, job *model.Job) error {
 			"id",
 			"name",
 			"describe",
+			"job_key",
+			"payload",
 			"target_id",
 			"target_name",
 			"status",
 			"message",
+			"attempts",
+			"run_at",
 			"created_at",
-			"ended_at").
+			"started_at",
+			"ended_at",
+			"duration",
+			"heartbeat_at").
 		Values(
 			job2.ID,
 			job2.Name,
 			job2.Describe,
+			job2.Key,
+			job2.Payload,
 			job2.TargetID,
 			job2.TargetName,
 			job2.Status,
 			job2.Message,
+			job2.Attempts,
+			job2.RunAt,
 			job2.CreatedAt,
-			job2.EndedAt)
+			job2.StartedAt,
+			job2.EndedAt,
+			job2.Duration,
+			job2.HeartbeatAt)
 	_, err := qb.Exec()
 	if err != nil {
 		return err
@@ -204,11 +271,18 @@ func (j *Jobs) Update(ctx context.Context, job *model.Job) error {
 		Update(j.table()).
 		Set("name", job2.Name).
 		Set("describe", job2.Describe).
+		Set("job_key", job2.Key).
+		Set("payload", job2.Payload).
 		Set("target_id", job2.TargetID).
 		Set("target_name", job2.TargetName).
 		Set("status", job2.Status).
 		Set("message", job2.Message).
+		Set("attempts", job2.Attempts).
+		Set("run_at", job2.RunAt).
+		Set("started_at", job2.StartedAt).
 		Set("ended_at", job2.EndedAt).
+		Set("duration", job2.Duration).
+		Set("heartbeat_at", job2.HeartbeatAt).
 		Where("id = ?", job2.ID)
 	_, err := qb.Exec()
 	if err != nil {
@@ -229,6 +303,107 @@ func (j *Jobs) Delete(ctx context.Context, id string) (int64, error) {
 	return res.RowsAffected()
 }
 
+// ClaimPending marks at most limit of the due queued jobs as in progress
+// and returns them, except the jobs of the keys which are running. It
+// should be called within TxFunc, the rows are locked by FOR UPDATE on
+// postgres, and on sqlite3 the claims of the process are serialized by
+// the transaction, which holds database.Locker until commit.
+func (j *Jobs) ClaimPending(ctx context.Context, now time.Time, limit uint64, running []string) (model.JobList, error) {
+	qb := j.RunnableBuilder(ctx).
+		Select(j.columns()...).From(j.table()).
+		Where("status = ?", model.JobQueued).
+		Where(squirrel.Or{
+			squirrel.Expr("run_at IS NULL"),
+			squirrel.Expr("run_at <= ?", now.UTC()),
+		}).
+		OrderBy("run_at", "created_at").
+		Limit(limit)
+	if len(running) > 0 {
+		qb = qb.Where(squirrel.NotEq{"job_key": running})
+	}
+	if j.db.DriverName() == "postgres" {
+		qb = qb.Suffix("FOR UPDATE SKIP LOCKED")
+	}
+	rows, err := qb.Query()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*model.Job, 0)
+	for rows.Next() {
+		job, err := j.scan(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, job)
+	}
+	rows.Close()
+
+	for _, job := range list {
+		job.Status = model.JobInProgress
+		job.StartedAt = field.Time(now.UTC())
+		job.HeartbeatAt = field.Time(now.UTC())
+		err := j.Update(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Heartbeat updates the time of heartbeat of the running job, which
+// keeps it from being requeued by RequeueStale.
+func (j *Jobs) Heartbeat(ctx context.Context, id string, now time.Time) error {
+	qb := j.RunnableBuilder(ctx).
+		Update(j.table()).
+		Set("heartbeat_at", now.UTC()).
+		Where("id = ?", id).
+		Where("status = ?", model.JobInProgress)
+	_, err := qb.Exec()
+	return err
+}
+
+// RequeueStale puts back the unfinished jobs whose last heartbeat, or
+// claim if none, is before the time, except the jobs of the keys which
+// are running. It recovers the jobs left by a stopped process.
+func (j *Jobs) RequeueStale(ctx context.Context, before time.Time, running []string) (int64, error) {
+	qb := j.RunnableBuilder(ctx).
+		Update(j.table()).
+		Set("status", model.JobQueued).
+		Set("started_at", nil).
+		Set("heartbeat_at", nil).
+		Where("status = ?", model.JobInProgress).
+		Where("ended_at IS NULL").
+		Where("payload <> ''").
+		Where(squirrel.Or{
+			squirrel.Expr("COALESCE(heartbeat_at, started_at) IS NULL"),
+			squirrel.Expr("COALESCE(heartbeat_at, started_at) < ?", before.UTC()),
+		})
+	if len(running) > 0 {
+		qb = qb.Where(squirrel.NotEq{"job_key": running})
+	}
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DeleteEnded deletes the history of the jobs which ended before the
+// time. The queued and running jobs are kept.
+func (j *Jobs) DeleteEnded(ctx context.Context, before time.Time) (int64, error) {
+	qb := j.RunnableBuilder(ctx).
+		Delete(j.table()).
+		Where(squirrel.Eq{"status": []model.JobStatus{model.JobCompleted, model.JobFailed, model.JobDead}}).
+		Where("ended_at IS NOT NULL").
+		Where("ended_at < ?", before.UTC())
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 func (o *JobOpts) JoinMonls() *JobOpts {
 	o2 := *o
 	o2.joinMonls = true
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pinmonl/pinmonl/database/dbtest"
 	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,6 +29,10 @@ func TestJobs(t *testing.T) {
 	t.Run("create", testJobsCreate(ctx, jobs, mock))
 	t.Run("update", testJobsUpdate(ctx, jobs, mock))
 	t.Run("delete", testJobsDelete(ctx, jobs, mock))
+	t.Run("claimPending", testJobsClaimPending(ctx, jobs, mock))
+	t.Run("heartbeat", testJobsHeartbeat(ctx, jobs, mock))
+	t.Run("requeueStale", testJobsRequeueStale(ctx, jobs, mock))
+	t.Run("deleteEnded", testJobsDeleteEnded(ctx, jobs, mock))
 }
 
 func testJobsList(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*testing.T) {
@@ -42,7 +48,7 @@ func testJobsList(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*t
 		opts = nil
 		mock.ExpectQuery(prefix).
 			WillReturnRows(sqlmock.NewRows(jobs.columns()).
-				AddRow("job-id-1", "target-1", "target", "", "description", 1, "job/png", nil, nil))
+				AddRow("job-id-1", "target-1", "target", "", "", "", "description", 1, "job/png", 0, nil, nil, nil, nil, 0, nil))
 		list, err = jobs.List(ctx, opts)
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(list))
@@ -62,6 +68,28 @@ func testJobsList(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*t
 			WillReturnRows(sqlmock.NewRows(jobs.columns()))
 		_, err = jobs.List(ctx, opts)
 		assert.Nil(t, err)
+
+		// Test filter by status and order by latest.
+		opts = &JobOpts{
+			Status: field.NewNullValue(model.JobFailed),
+			Orders: []JobOrder{JobOrderByLatest},
+		}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE status = ? ORDER BY COALESCE(jobs.created_at, '0001-01-01 00:00:00+00:00') DESC, jobs.id DESC"), prefix)).
+			WithArgs(model.JobFailed).
+			WillReturnRows(sqlmock.NewRows(jobs.columns()))
+		_, err = jobs.List(ctx, opts)
+		assert.Nil(t, err)
+
+		// Test filter by keys and statuses.
+		opts = &JobOpts{
+			Keys:     []string{"pinl_updated::pinl-id-1"},
+			Statuses: []model.JobStatus{model.JobQueued, model.JobInProgress},
+		}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE job_key IN (?) AND status IN (?,?)"), prefix)).
+			WithArgs("pinl_updated::pinl-id-1", model.JobQueued, model.JobInProgress).
+			WillReturnRows(sqlmock.NewRows(jobs.columns()))
+		_, err = jobs.List(ctx, opts)
+		assert.Nil(t, err)
 	}
 }
 
@@ -97,7 +125,7 @@ func testJobsFind(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*t
 		mock.ExpectQuery(query).
 			WithArgs(id).
 			WillReturnRows(sqlmock.NewRows(jobs.columns()).
-				AddRow(id, "target-1", "target", "", "description", 1, "job/png", nil, nil))
+				AddRow(id, "target-1", "target", "", "", "", "description", 1, "job/png", 0, nil, nil, nil, nil, 0, nil))
 		job, err = jobs.Find(ctx, id)
 		assert.Nil(t, err)
 		if assert.NotNil(t, job) {
@@ -128,12 +156,19 @@ func expectJobsCreate(mock sqlmock.Sqlmock, job *model.Job) {
 			sqlmock.AnyArg(),
 			job.Name,
 			job.Describe,
+			job.Key,
+			job.Payload,
 			job.TargetID,
 			job.TargetName,
 			job.Status,
 			job.Message,
+			job.Attempts,
+			job.RunAt,
 			sqlmock.AnyArg(),
-			job.EndedAt).
+			job.StartedAt,
+			job.EndedAt,
+			job.Duration,
+			job.HeartbeatAt).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
 
@@ -156,11 +191,18 @@ func expectJobsUpdate(mock sqlmock.Sqlmock, job *model.Job) {
 		WithArgs(
 			job.Name,
 			job.Describe,
+			job.Key,
+			job.Payload,
 			job.TargetID,
 			job.TargetName,
 			job.Status,
 			job.Message,
+			job.Attempts,
+			job.RunAt,
+			job.StartedAt,
 			job.EndedAt,
+			job.Duration,
+			job.HeartbeatAt,
 			job.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
@@ -183,3 +225,94 @@ func testJobsDelete(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(
 		assert.Equal(t, int64(1), n)
 	}
 }
+
+func testJobsClaimPending(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("SELECT ") + "(.+)" +
+				regexp.QuoteMeta(" FROM jobs WHERE status = ? AND (run_at IS NULL OR run_at <= ?) AND job_key NOT IN (?) ORDER BY run_at, created_at LIMIT 2")
+			now  = time.Now().UTC()
+			list model.JobList
+			err  error
+		)
+
+		mock.ExpectQuery(query).
+			WithArgs(model.JobQueued, now, "pinl_updated::pinl-id-2").
+			WillReturnRows(sqlmock.NewRows(jobs.columns()).
+				AddRow("job-id-1", "pinl_updated", "", "pinl_updated::pinl-id-1", "{}", "", "", model.JobQueued, "", 0, nil, nil, nil, nil, 0, nil))
+		expectJobsUpdate(mock, &model.Job{
+			ID:          "job-id-1",
+			Name:        "pinl_updated",
+			Key:         "pinl_updated::pinl-id-1",
+			Payload:     "{}",
+			Status:      model.JobInProgress,
+			StartedAt:   field.Time(now),
+			HeartbeatAt: field.Time(now),
+		})
+		list, err = jobs.ClaimPending(ctx, now, 2, []string{"pinl_updated::pinl-id-2"})
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, model.JobInProgress, list[0].Status)
+		}
+	}
+}
+
+func testJobsHeartbeat(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("UPDATE jobs SET heartbeat_at = ? WHERE id = ? AND status = ?")
+			now   = time.Now().UTC()
+			err   error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs(now, "job-id-1", model.JobInProgress).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = jobs.Heartbeat(ctx, "job-id-1", now)
+		assert.Nil(t, err)
+	}
+}
+
+func testJobsRequeueStale(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query  = regexp.QuoteMeta("UPDATE jobs SET status = ?, started_at = ?, heartbeat_at = ? WHERE status = ? AND ended_at IS NULL AND payload <> '' AND (COALESCE(heartbeat_at, started_at) IS NULL OR COALESCE(heartbeat_at, started_at) < ?)")
+			before = time.Now().UTC()
+			n      int64
+			err    error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs(model.JobQueued, nil, nil, model.JobInProgress, before).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		n, err = jobs.RequeueStale(ctx, before, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// Skip the running keys.
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET status = ?, started_at = ?, heartbeat_at = ? WHERE status = ? AND ended_at IS NULL AND payload <> '' AND (COALESCE(heartbeat_at, started_at) IS NULL OR COALESCE(heartbeat_at, started_at) < ?) AND job_key NOT IN (?)")).
+			WithArgs(model.JobQueued, nil, nil, model.JobInProgress, before, "key-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		n, err = jobs.RequeueStale(ctx, before, []string{"key-1"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
+
+func testJobsDeleteEnded(ctx context.Context, jobs *Jobs, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query  = regexp.QuoteMeta("DELETE FROM jobs WHERE status IN (?,?,?) AND ended_at IS NOT NULL AND ended_at < ?")
+			before = time.Now().UTC()
+			n      int64
+			err    error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs(model.JobCompleted, model.JobFailed, model.JobDead, before).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		n, err = jobs.DeleteEnded(ctx, before)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+	}
+}
@@ -36,6 +36,10 @@ func (m *Monls) List(ctx context.Context, opts *MonlOpts) (model.MonlList, error
 	qb := m.RunnableBuilder(ctx).
 		Select(m.columns()...).From(m.table())
 	qb = m.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(m.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -50,6 +54,7 @@ func (m *Monls) List(ctx context.Context, opts *MonlOpts) (model.MonlList, error
 		}
 		list = append(list, monl)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -41,6 +41,10 @@ func (m *Monpkgs) List(ctx context.Context, opts *MonpkgOpts) (model.MonpkgList,
 	qb := m.RunnableBuilder(ctx).
 		Select(m.columns()...).From(m.table())
 	qb = m.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(m.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -55,6 +59,7 @@ func (m *Monpkgs) List(ctx context.Context, opts *MonpkgOpts) (model.MonpkgList,
 		}
 		list = append(list, monpkg)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -121,6 +126,10 @@ func (m *Monpkgs) ListWithPkg(ctx context.Context, opts *MonpkgOpts) (model.Monp
 		Columns(Pkgs{}.columns()...).
 		From(m.table())
 	qb = m.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(m.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -141,6 +150,7 @@ func (m *Monpkgs) ListWithPkg(ctx context.Context, opts *MonpkgOpts) (model.Monp
 		mmp.Pkg = &mp
 		list = append(list, &mmp)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -155,6 +165,10 @@ func (m *Monpkgs) ListWithMonl(ctx context.Context, opts *MonpkgOpts) (model.Mon
 		Columns(Monls{}.columns()...).
 		From(m.table())
 	qb = m.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(m.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -175,6 +189,7 @@ func (m *Monpkgs) ListWithMonl(ctx context.Context, opts *MonpkgOpts) (model.Mon
 		mmp.Monl = &mm
 		list = append(list, &mmp)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
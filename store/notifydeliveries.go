@@ -0,0 +1,251 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+type NotifyDeliveries struct {
+	*Store
+}
+
+type NotifyDeliveryOpts struct {
+	ListOpts
+	RuleIDs []string
+	StatIDs []string
+	Status  field.NullValue
+
+	Orders []NotifyDeliveryOrder
+}
+
+type NotifyDeliveryOrder int
+
+const (
+	NotifyDeliveryOrderByLatest NotifyDeliveryOrder = iota
+)
+
+func NewNotifyDeliveries(s *Store) *NotifyDeliveries {
+	return &NotifyDeliveries{s}
+}
+
+func (n NotifyDeliveries) table() string {
+	return "notify_deliveries"
+}
+
+func (n *NotifyDeliveries) List(ctx context.Context, opts *NotifyDeliveryOpts) (model.NotifyDeliveryList, error) {
+	if opts == nil {
+		opts = &NotifyDeliveryOpts{}
+	}
+
+	qb := n.RunnableBuilder(ctx).
+		Select(n.columns()...).From(n.table())
+	qb = n.bindOpts(qb, opts)
+	qb, err := addCursor(qb, n.sortKeys(opts), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	qb = addPagination(qb, opts)
+	rows, err := qb.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*model.NotifyDelivery, 0)
+	for rows.Next() {
+		delivery, err := n.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, delivery)
+	}
+	reverseList(list, opts.Cursor)
+	return list, nil
+}
+
+func (n *NotifyDeliveries) Count(ctx context.Context, opts *NotifyDeliveryOpts) (int64, error) {
+	if opts == nil {
+		opts = &NotifyDeliveryOpts{}
+	}
+
+	o2 := *opts
+	o2.Orders = nil
+
+	qb := n.RunnableBuilder(ctx).
+		Select("count(*)").From(n.table())
+	qb = n.bindOpts(qb, &o2)
+	row := qb.QueryRow()
+	var count int64
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (n *NotifyDeliveries) Find(ctx context.Context, id string) (*model.NotifyDelivery, error) {
+	qb := n.RunnableBuilder(ctx).
+		Select(n.columns()...).From(n.table()).
+		Where("id = ?", id)
+	row := qb.QueryRow()
+	delivery, err := n.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (n NotifyDeliveries) bindOpts(b squirrel.SelectBuilder, opts *NotifyDeliveryOpts) squirrel.SelectBuilder {
+	if opts == nil {
+		return b
+	}
+
+	if len(opts.RuleIDs) > 0 {
+		b = b.Where(squirrel.Eq{"rule_id": opts.RuleIDs})
+	}
+
+	if len(opts.StatIDs) > 0 {
+		b = b.Where(squirrel.Eq{"stat_id": opts.StatIDs})
+	}
+
+	if opts.Status.Valid {
+		if vs, ok := opts.Status.Value().(model.DeliveryStatus); ok {
+			b = b.Where("status = ?", vs)
+		}
+	}
+
+	return b
+}
+
+// sortKeys returns the keys of (created_at, id) in the direction of
+// orders.
+func (n NotifyDeliveries) sortKeys(opts *NotifyDeliveryOpts) []sortKey {
+	desc := false
+	for _, order := range opts.Orders {
+		if order == NotifyDeliveryOrderByLatest {
+			desc = true
+		}
+	}
+	return createdKeys(n.table(), desc)
+}
+
+func (n NotifyDeliveries) columns() []string {
+	return []string{
+		n.table() + ".id",
+		n.table() + ".rule_id",
+		n.table() + ".stat_id",
+		n.table() + ".event",
+		n.table() + ".payload",
+		n.table() + ".status",
+		n.table() + ".attempts",
+		n.table() + ".response_code",
+		n.table() + ".message",
+		n.table() + ".created_at",
+		n.table() + ".delivered_at",
+	}
+}
+
+func (n NotifyDeliveries) scanColumns(delivery *model.NotifyDelivery) []interface{} {
+	return []interface{}{
+		&delivery.ID,
+		&delivery.RuleID,
+		&delivery.StatID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&delivery.Message,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}
+}
+
+func (n NotifyDeliveries) scan(row database.RowScanner) (*model.NotifyDelivery, error) {
+	var delivery model.NotifyDelivery
+	err := row.Scan(n.scanColumns(&delivery)...)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (n *NotifyDeliveries) Create(ctx context.Context, delivery *model.NotifyDelivery) error {
+	delivery2 := *delivery
+	delivery2.ID = newID()
+	delivery2.CreatedAt = timestamp()
+
+	qb := n.RunnableBuilder(ctx).
+		Insert(n.table()).
+		Columns(
+			"id",
+			"rule_id",
+			"stat_id",
+			"event",
+			"payload",
+			"status",
+			"attempts",
+			"response_code",
+			"message",
+			"created_at",
+			"delivered_at").
+		Values(
+			delivery2.ID,
+			delivery2.RuleID,
+			delivery2.StatID,
+			delivery2.Event,
+			delivery2.Payload,
+			delivery2.Status,
+			delivery2.Attempts,
+			delivery2.ResponseCode,
+			delivery2.Message,
+			delivery2.CreatedAt,
+			delivery2.DeliveredAt)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*delivery = delivery2
+	return nil
+}
+
+func (n *NotifyDeliveries) Update(ctx context.Context, delivery *model.NotifyDelivery) error {
+	delivery2 := *delivery
+
+	qb := n.RunnableBuilder(ctx).
+		Update(n.table()).
+		Set("rule_id", delivery2.RuleID).
+		Set("stat_id", delivery2.StatID).
+		Set("event", delivery2.Event).
+		Set("payload", delivery2.Payload).
+		Set("status", delivery2.Status).
+		Set("attempts", delivery2.Attempts).
+		Set("response_code", delivery2.ResponseCode).
+		Set("message", delivery2.Message).
+		Set("delivered_at", delivery2.DeliveredAt).
+		Where("id = ?", delivery2.ID)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*delivery = delivery2
+	return nil
+}
+
+func (n *NotifyDeliveries) DeleteByRule(ctx context.Context, ruleID string) (int64, error) {
+	qb := n.RunnableBuilder(ctx).
+		Delete(n.table()).
+		Where("rule_id = ?", ruleID)
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pinmonl/pinmonl/database/dbtest"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyDeliveries(t *testing.T) {
+	db, mock, err := dbtest.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ctx := context.TODO()
+	s := NewStore(db)
+	deliveries := NewNotifyDeliveries(s)
+
+	t.Run("list", testNotifyDeliveriesList(ctx, deliveries, mock))
+	t.Run("count", testNotifyDeliveriesCount(ctx, deliveries, mock))
+	t.Run("find", testNotifyDeliveriesFind(ctx, deliveries, mock))
+	t.Run("create", testNotifyDeliveriesCreate(ctx, deliveries, mock))
+	t.Run("update", testNotifyDeliveriesUpdate(ctx, deliveries, mock))
+	t.Run("deleteByRule", testNotifyDeliveriesDeleteByRule(ctx, deliveries, mock))
+}
+
+func testNotifyDeliveriesList(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			prefix = "SELECT (.+) FROM notify_deliveries"
+			opts   *NotifyDeliveryOpts
+			list   []*model.NotifyDelivery
+			err    error
+		)
+
+		// Test nil opts.
+		opts = nil
+		mock.ExpectQuery(prefix).
+			WillReturnRows(sqlmock.NewRows(deliveries.columns()).
+				AddRow("delivery-id-1", "rule-id-1", "stat-id-1", "release", "{}", 0, 1, 502, "bad gateway", nil, nil))
+		list, err = deliveries.List(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+
+		// Test filter by rules, stats, status and order by latest.
+		opts = &NotifyDeliveryOpts{
+			RuleIDs: []string{"rule-id-1"},
+			StatIDs: []string{"stat-id-1", "stat-id-2"},
+			Status:  field.NewNullValue(model.DeliveryFailed),
+			Orders:  []NotifyDeliveryOrder{NotifyDeliveryOrderByLatest},
+		}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE rule_id IN (?) AND stat_id IN (?,?) AND status = ? ORDER BY COALESCE(notify_deliveries.created_at, '0001-01-01 00:00:00+00:00') DESC, notify_deliveries.id DESC"), prefix)).
+			WithArgs("rule-id-1", "stat-id-1", "stat-id-2", model.DeliveryFailed).
+			WillReturnRows(sqlmock.NewRows(deliveries.columns()))
+		_, err = deliveries.List(ctx, opts)
+		assert.Nil(t, err)
+	}
+}
+
+func testNotifyDeliveriesCount(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("SELECT count(*) FROM notify_deliveries WHERE rule_id IN (?)")
+			opts  *NotifyDeliveryOpts
+			count int64
+			err   error
+		)
+
+		opts = &NotifyDeliveryOpts{
+			RuleIDs: []string{"rule-id-1"},
+			Orders:  []NotifyDeliveryOrder{NotifyDeliveryOrderByLatest},
+		}
+		mock.ExpectQuery(query).
+			WithArgs("rule-id-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
+				AddRow(1))
+		count, err = deliveries.Count(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	}
+}
+
+func testNotifyDeliveriesFind(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query    = "SELECT (.+) FROM notify_deliveries WHERE id = \\?"
+			id       string
+			delivery *model.NotifyDelivery
+			err      error
+		)
+
+		id = "delivery-id-1"
+		mock.ExpectQuery(query).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(deliveries.columns()).
+				AddRow(id, "rule-id-1", "stat-id-1", "release", "{}", 1, 1, 200, "", nil, nil))
+		delivery, err = deliveries.Find(ctx, id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, delivery) {
+			assert.Equal(t, id, delivery.ID)
+			assert.Equal(t, model.DeliveryDelivered, delivery.Status)
+		}
+	}
+}
+
+func testNotifyDeliveriesCreate(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			delivery *model.NotifyDelivery
+			err      error
+		)
+
+		delivery = &model.NotifyDelivery{}
+		expectNotifyDeliveriesCreate(mock, delivery)
+		err = deliveries.Create(ctx, delivery)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, delivery.ID)
+		assert.NotEmpty(t, delivery.CreatedAt)
+	}
+}
+
+func expectNotifyDeliveriesCreate(mock sqlmock.Sqlmock, delivery *model.NotifyDelivery) {
+	mock.ExpectExec("INSERT INTO notify_deliveries").
+		WithArgs(
+			sqlmock.AnyArg(),
+			delivery.RuleID,
+			delivery.StatID,
+			delivery.Event,
+			delivery.Payload,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.ResponseCode,
+			delivery.Message,
+			sqlmock.AnyArg(),
+			delivery.DeliveredAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testNotifyDeliveriesUpdate(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			delivery *model.NotifyDelivery
+			err      error
+		)
+
+		delivery = &model.NotifyDelivery{ID: "delivery-id-1"}
+		expectNotifyDeliveriesUpdate(mock, delivery)
+		err = deliveries.Update(ctx, delivery)
+		assert.Nil(t, err)
+	}
+}
+
+func expectNotifyDeliveriesUpdate(mock sqlmock.Sqlmock, delivery *model.NotifyDelivery) {
+	mock.ExpectExec("UPDATE notify_deliveries (.+) WHERE id = \\?").
+		WithArgs(
+			delivery.RuleID,
+			delivery.StatID,
+			delivery.Event,
+			delivery.Payload,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.ResponseCode,
+			delivery.Message,
+			delivery.DeliveredAt,
+			delivery.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testNotifyDeliveriesDeleteByRule(ctx context.Context, deliveries *NotifyDeliveries, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("DELETE FROM notify_deliveries WHERE rule_id = ?")
+			n     int64
+			err   error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs("rule-id-1").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		n, err = deliveries.DeleteByRule(ctx, "rule-id-1")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+	}
+}
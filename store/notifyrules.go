@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+)
+
+type NotifyRules struct {
+	*Store
+}
+
+type NotifyRuleOpts struct {
+	ListOpts
+	UserID  string
+	UserIDs []string
+	Enabled field.NullBool
+}
+
+func NewNotifyRules(s *Store) *NotifyRules {
+	return &NotifyRules{s}
+}
+
+func (n NotifyRules) table() string {
+	return "notify_rules"
+}
+
+func (n *NotifyRules) List(ctx context.Context, opts *NotifyRuleOpts) (model.NotifyRuleList, error) {
+	if opts == nil {
+		opts = &NotifyRuleOpts{}
+	}
+
+	qb := n.RunnableBuilder(ctx).
+		Select(n.columns()...).From(n.table())
+	qb = n.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(n.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	qb = addPagination(qb, opts)
+	rows, err := qb.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*model.NotifyRule, 0)
+	for rows.Next() {
+		rule, err := n.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, rule)
+	}
+	reverseList(list, opts.Cursor)
+	return list, nil
+}
+
+func (n *NotifyRules) Count(ctx context.Context, opts *NotifyRuleOpts) (int64, error) {
+	if opts == nil {
+		opts = &NotifyRuleOpts{}
+	}
+
+	qb := n.RunnableBuilder(ctx).
+		Select("count(*)").From(n.table())
+	qb = n.bindOpts(qb, opts)
+	row := qb.QueryRow()
+	var count int64
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (n *NotifyRules) Find(ctx context.Context, id string) (*model.NotifyRule, error) {
+	qb := n.RunnableBuilder(ctx).
+		Select(n.columns()...).From(n.table()).
+		Where("id = ?", id)
+	row := qb.QueryRow()
+	rule, err := n.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (n NotifyRules) bindOpts(b squirrel.SelectBuilder, opts *NotifyRuleOpts) squirrel.SelectBuilder {
+	if opts == nil {
+		return b
+	}
+
+	if opts.UserID != "" {
+		opts.UserIDs = append(opts.UserIDs, opts.UserID)
+	}
+	if len(opts.UserIDs) > 0 {
+		b = b.Where(squirrel.Eq{"user_id": opts.UserIDs})
+	}
+
+	if opts.Enabled.Valid {
+		b = b.Where("enabled = ?", opts.Enabled.Value())
+	}
+
+	return b
+}
+
+func (n NotifyRules) columns() []string {
+	return []string{
+		n.table() + ".id",
+		n.table() + ".user_id",
+		n.table() + ".name",
+		n.table() + ".url",
+		n.table() + ".secret",
+		n.table() + ".tag_names",
+		n.table() + ".pinl_ids",
+		n.table() + ".pkg_ids",
+		n.table() + ".kinds",
+		n.table() + ".constraint_expr",
+		n.table() + ".level",
+		n.table() + ".exclude_prerelease",
+		n.table() + ".enabled",
+		n.table() + ".created_at",
+		n.table() + ".updated_at",
+	}
+}
+
+func (n NotifyRules) scanColumns(rule *model.NotifyRule) []interface{} {
+	return []interface{}{
+		&rule.ID,
+		&rule.UserID,
+		&rule.Name,
+		&rule.URL,
+		&rule.Secret,
+		&rule.TagNames,
+		&rule.PinlIDs,
+		&rule.PkgIDs,
+		&rule.Kinds,
+		&rule.Constraint,
+		&rule.Level,
+		&rule.ExcludePrerelease,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	}
+}
+
+func (n NotifyRules) scan(row database.RowScanner) (*model.NotifyRule, error) {
+	var rule model.NotifyRule
+	err := row.Scan(n.scanColumns(&rule)...)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (n *NotifyRules) Create(ctx context.Context, rule *model.NotifyRule) error {
+	rule2 := *rule
+	rule2.ID = newID()
+	rule2.CreatedAt = timestamp()
+	rule2.UpdatedAt = timestamp()
+
+	qb := n.RunnableBuilder(ctx).
+		Insert(n.table()).
+		Columns(
+			"id",
+			"user_id",
+			"name",
+			"url",
+			"secret",
+			"tag_names",
+			"pinl_ids",
+			"pkg_ids",
+			"kinds",
+			"constraint_expr",
+			"level",
+			"exclude_prerelease",
+			"enabled",
+			"created_at",
+			"updated_at").
+		Values(
+			rule2.ID,
+			rule2.UserID,
+			rule2.Name,
+			rule2.URL,
+			rule2.Secret,
+			rule2.TagNames,
+			rule2.PinlIDs,
+			rule2.PkgIDs,
+			rule2.Kinds,
+			rule2.Constraint,
+			rule2.Level,
+			rule2.ExcludePrerelease,
+			rule2.Enabled,
+			rule2.CreatedAt,
+			rule2.UpdatedAt)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*rule = rule2
+	return nil
+}
+
+func (n *NotifyRules) Update(ctx context.Context, rule *model.NotifyRule) error {
+	rule2 := *rule
+	rule2.UpdatedAt = timestamp()
+
+	qb := n.RunnableBuilder(ctx).
+		Update(n.table()).
+		Set("user_id", rule2.UserID).
+		Set("name", rule2.Name).
+		Set("url", rule2.URL).
+		Set("secret", rule2.Secret).
+		Set("tag_names", rule2.TagNames).
+		Set("pinl_ids", rule2.PinlIDs).
+		Set("pkg_ids", rule2.PkgIDs).
+		Set("kinds", rule2.Kinds).
+		Set("constraint_expr", rule2.Constraint).
+		Set("level", rule2.Level).
+		Set("exclude_prerelease", rule2.ExcludePrerelease).
+		Set("enabled", rule2.Enabled).
+		Set("updated_at", rule2.UpdatedAt).
+		Where("id = ?", rule2.ID)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*rule = rule2
+	return nil
+}
+
+func (n *NotifyRules) Delete(ctx context.Context, id string) (int64, error) {
+	qb := n.RunnableBuilder(ctx).
+		Delete(n.table()).
+		Where("id = ?", id)
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
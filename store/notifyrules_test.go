@@ -0,0 +1,201 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pinmonl/pinmonl/database/dbtest"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyRules(t *testing.T) {
+	db, mock, err := dbtest.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ctx := context.TODO()
+	s := NewStore(db)
+	rules := NewNotifyRules(s)
+
+	t.Run("list", testNotifyRulesList(ctx, rules, mock))
+	t.Run("count", testNotifyRulesCount(ctx, rules, mock))
+	t.Run("find", testNotifyRulesFind(ctx, rules, mock))
+	t.Run("create", testNotifyRulesCreate(ctx, rules, mock))
+	t.Run("update", testNotifyRulesUpdate(ctx, rules, mock))
+	t.Run("delete", testNotifyRulesDelete(ctx, rules, mock))
+}
+
+func testNotifyRulesList(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			prefix = "SELECT (.+) FROM notify_rules"
+			opts   *NotifyRuleOpts
+			list   []*model.NotifyRule
+			err    error
+		)
+
+		// Test nil opts.
+		opts = nil
+		mock.ExpectQuery(prefix).
+			WillReturnRows(sqlmock.NewRows(rules.columns()).
+				AddRow("rule-id-1", "user-id-1", "releases", "https://example.com/hook", "secret",
+					`["go"]`, "", "", `["tag"]`, ">=1.0.0", "major", true, true, nil, nil))
+		list, err = rules.List(ctx, opts)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, field.StringList{"go"}, list[0].TagNames)
+			assert.Nil(t, list[0].PinlIDs)
+			assert.Equal(t, model.MajorRelease, list[0].Level)
+		}
+
+		// Test filter by users and enabled.
+		opts = &NotifyRuleOpts{
+			UserIDs: []string{"user-id-1", "user-id-2"},
+			Enabled: field.NewNullBool(true),
+		}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s WHERE user_id IN (?,?) AND enabled = ?"), prefix)).
+			WithArgs("user-id-1", "user-id-2", true).
+			WillReturnRows(sqlmock.NewRows(rules.columns()))
+		_, err = rules.List(ctx, opts)
+		assert.Nil(t, err)
+	}
+}
+
+func testNotifyRulesCount(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("SELECT count(*) FROM notify_rules WHERE user_id IN (?)")
+			opts  *NotifyRuleOpts
+			count int64
+			err   error
+		)
+
+		opts = &NotifyRuleOpts{UserID: "user-id-1"}
+		mock.ExpectQuery(query).
+			WithArgs("user-id-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
+				AddRow(1))
+		count, err = rules.Count(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	}
+}
+
+func testNotifyRulesFind(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = "SELECT (.+) FROM notify_rules WHERE id = \\?"
+			id    string
+			rule  *model.NotifyRule
+			err   error
+		)
+
+		id = "rule-id-1"
+		mock.ExpectQuery(query).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(rules.columns()).
+				AddRow(id, "user-id-1", "releases", "https://example.com/hook", "secret",
+					"", "", "", "", "", "", false, true, nil, nil))
+		rule, err = rules.Find(ctx, id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, rule) {
+			assert.Equal(t, id, rule.ID)
+		}
+	}
+}
+
+func testNotifyRulesCreate(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			rule *model.NotifyRule
+			err  error
+		)
+
+		rule = &model.NotifyRule{TagNames: field.StringList{"go"}}
+		expectNotifyRulesCreate(mock, rule)
+		err = rules.Create(ctx, rule)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, rule.ID)
+		assert.NotEmpty(t, rule.CreatedAt)
+	}
+}
+
+func expectNotifyRulesCreate(mock sqlmock.Sqlmock, rule *model.NotifyRule) {
+	mock.ExpectExec("INSERT INTO notify_rules").
+		WithArgs(
+			sqlmock.AnyArg(),
+			rule.UserID,
+			rule.Name,
+			rule.URL,
+			rule.Secret,
+			`["go"]`,
+			"",
+			"",
+			"",
+			rule.Constraint,
+			rule.Level,
+			rule.ExcludePrerelease,
+			rule.Enabled,
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testNotifyRulesUpdate(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			rule *model.NotifyRule
+			err  error
+		)
+
+		rule = &model.NotifyRule{ID: "rule-id-1"}
+		expectNotifyRulesUpdate(mock, rule)
+		err = rules.Update(ctx, rule)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, rule.UpdatedAt)
+	}
+}
+
+func expectNotifyRulesUpdate(mock sqlmock.Sqlmock, rule *model.NotifyRule) {
+	mock.ExpectExec("UPDATE notify_rules (.+) WHERE id = \\?").
+		WithArgs(
+			rule.UserID,
+			rule.Name,
+			rule.URL,
+			rule.Secret,
+			"",
+			"",
+			"",
+			"",
+			rule.Constraint,
+			rule.Level,
+			rule.ExcludePrerelease,
+			rule.Enabled,
+			sqlmock.AnyArg(),
+			rule.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testNotifyRulesDelete(ctx context.Context, rules *NotifyRules, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("DELETE FROM notify_rules WHERE id = ?")
+			id    string
+			n     int64
+			err   error
+		)
+
+		id = "rule-id-1"
+		mock.ExpectExec(query).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		n, err = rules.Delete(ctx, id)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
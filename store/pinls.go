@@ -9,6 +9,7 @@ import (
 	"github.com/pinmonl/pinmonl/database"
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/pinlutils"
 )
 
 type Pinls struct {
@@ -24,19 +25,44 @@ type PinlOpts struct {
 	Query   string
 	Status  field.NullValue
 	URL     string
+	URLs    []string
+
+	// SearchContent includes the page content in search of Query.
+	SearchContent bool
 
 	TagIDs          []string
 	TagNames        []string
 	TagNamePatterns []string
 	NoTag           field.NullBool
 
+	// Filter is the filter expression parsed from search query.
+	Filter PinlFilter
+
 	Orders []PinlOrder
+
+	// PositionTagID is the tag of manual position, which is required
+	// by PinlOrderByPosition.
+	PositionTagID string
 }
 
 type PinlOrder int
 
 const (
 	PinlOrderByLatest PinlOrder = iota
+	PinlOrderByRelevance
+	PinlOrderByOldest
+	PinlOrderByTitle
+	PinlOrderByTitleDesc
+	PinlOrderByUpdated
+	PinlOrderByUpdatedDesc
+	PinlOrderByHost
+	PinlOrderByHostDesc
+	PinlOrderByReleased
+	PinlOrderByReleasedDesc
+	PinlOrderByStars
+	PinlOrderByStarsDesc
+	PinlOrderByPosition
+	PinlOrderByPositionDesc
 )
 
 func NewPinls(s *Store) *Pinls {
@@ -52,9 +78,30 @@ func (p *Pinls) List(ctx context.Context, opts *PinlOpts) (model.PinlList, error
 		opts = &PinlOpts{}
 	}
 
+	keys, keyset := p.sortKeys(opts)
+
 	qb := p.RunnableBuilder(ctx).
 		Select(p.columns()...).From(p.table())
+	snippet, snippetArgs, withSnippet := p.snippetColumn(opts)
+	if withSnippet {
+		qb = qb.Column(snippet, snippetArgs...)
+	}
+	// The values of sort orders are selected for the cursor, except
+	// id which is the last key.
+	if keyset {
+		for _, key := range keys[:len(keys)-1] {
+			qb = qb.Column(key.expr, key.args...)
+		}
+	}
 	qb = p.bindOpts(qb, opts)
+	if keyset {
+		var err error
+		if qb, err = addCursor(qb, keys, opts.Cursor); err != nil {
+			return nil, err
+		}
+	} else if opts.Cursor != nil {
+		return nil, ErrInvalidCursor
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -63,12 +110,29 @@ func (p *Pinls) List(ctx context.Context, opts *PinlOpts) (model.PinlList, error
 	defer rows.Close()
 	list := make([]*model.Pinl, 0)
 	for rows.Next() {
-		pinl, err := p.scan(rows)
-		if err != nil {
+		var pinl model.Pinl
+		dest := p.scanColumns(&pinl)
+		if withSnippet {
+			dest = append(dest, &pinl.Snippet)
+		}
+		var cursor Cursor
+		if keyset {
+			cursor.Keys = make([]interface{}, len(keys)-1)
+			for i := range cursor.Keys {
+				dest = append(dest, &cursor.Keys[i])
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
-		list = append(list, pinl)
+		pinl.Snippet = highlightSnippet(pinl.Snippet)
+		if keyset {
+			cursor.ID = pinl.ID
+			pinl.Cursor = cursor.Encode()
+		}
+		list = append(list, &pinl)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -128,11 +192,7 @@ func (p Pinls) bindOpts(b squirrel.SelectBuilder, opts *PinlOpts) squirrel.Selec
 	}
 
 	if opts.Query != "" {
-		b = b.Where(squirrel.Or{
-			squirrel.Expr("title like ?", "%"+opts.Query+"%"),
-			squirrel.Expr("description like ?", "%"+opts.Query+"%"),
-			squirrel.Expr("url like ?", "%"+opts.Query+"%"),
-		})
+		b = p.bindSearch(b, opts)
 	}
 
 	if opts.Status.Valid {
@@ -142,7 +202,11 @@ func (p Pinls) bindOpts(b squirrel.SelectBuilder, opts *PinlOpts) squirrel.Selec
 	}
 
 	if opts.URL != "" {
-		b = b.Where("url = ?", opts.URL)
+		b = b.Where(p.table()+".url = ?", opts.URL)
+	}
+
+	if len(opts.URLs) > 0 {
+		b = b.Where(squirrel.Eq{p.table() + ".url": opts.URLs})
 	}
 
 	if len(opts.TagIDs) > 0 {
@@ -204,10 +268,21 @@ func (p Pinls) bindOpts(b squirrel.SelectBuilder, opts *PinlOpts) squirrel.Selec
 		b = b.Where(sq)
 	}
 
+	if opts.Filter != nil {
+		b = b.Where(opts.Filter.toSql(p))
+	}
+
+	// Orders paginated by cursor are added in List.
+	if _, keyset := p.sortKeys(opts); keyset {
+		return b
+	}
 	for _, order := range opts.Orders {
-		switch order {
-		case PinlOrderByLatest:
-			b = b.OrderBy("created_at DESC")
+		if order == PinlOrderByRelevance {
+			b = p.orderByRelevance(b, opts)
+			continue
+		}
+		if key, ok := p.sortKey(order, opts); ok {
+			b = b.OrderByClause(key.orderBy(), key.args...)
 		}
 	}
 
@@ -270,6 +345,7 @@ func (p *Pinls) Create(ctx context.Context, pinl *model.Pinl) error {
 			"description",
 			"image_id",
 			"status",
+			"host",
 			"created_at",
 			"updated_at").
 		Values(
@@ -281,6 +357,7 @@ func (p *Pinls) Create(ctx context.Context, pinl *model.Pinl) error {
 			pinl2.Description,
 			pinl2.ImageID,
 			pinl2.Status,
+			pinlutils.Host(pinl2.URL),
 			pinl2.CreatedAt,
 			pinl2.UpdatedAt)
 	_, err := qb.Exec()
@@ -304,6 +381,7 @@ func (p *Pinls) Update(ctx context.Context, pinl *model.Pinl) error {
 		Set("description", pinl2.Description).
 		Set("image_id", pinl2.ImageID).
 		Set("status", pinl2.Status).
+		Set("host", pinlutils.Host(pinl2.URL)).
 		Set("updated_at", pinl2.UpdatedAt).
 		Where("id = ?", pinl2.ID)
 	_, err := qb.Exec()
@@ -314,6 +392,33 @@ func (p *Pinls) Update(ctx context.Context, pinl *model.Pinl) error {
 	return nil
 }
 
+// UpdateContent saves the page content for search.
+func (p *Pinls) UpdateContent(ctx context.Context, id, content string) error {
+	qb := p.RunnableBuilder(ctx).
+		Update(p.table()).
+		Set("content", content).
+		Where("id = ?", id)
+	_, err := qb.Exec()
+	return err
+}
+
+// UpdateMonl links the pinl to the monl. The updated time is kept, as
+// it is not changed by user, e.g. the time restored from bookmarks.
+func (p *Pinls) UpdateMonl(ctx context.Context, id, monlID string) error {
+	qb := p.RunnableBuilder(ctx).
+		Update(p.table()).
+		Set("monl_id", monlID).
+		Where("id = ?", id)
+	_, err := qb.Exec()
+	return err
+}
+
+// UpdateTimestamps sets the created and updated time of the pinl,
+// e.g. restored from archive.
+func (p *Pinls) UpdateTimestamps(ctx context.Context, pinl *model.Pinl) error {
+	return p.updateTimestamps(ctx, p.table(), pinl.ID, pinl.CreatedAt, pinl.UpdatedAt)
+}
+
 func (p *Pinls) Delete(ctx context.Context, id string) (int64, error) {
 	qb := p.RunnableBuilder(ctx).
 		Delete(p.table()).
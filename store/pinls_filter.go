@@ -0,0 +1,222 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/model"
+)
+
+// PinlFilter is a node of the filter expression of pinls, which is
+// usually parsed from the search query.
+type PinlFilter interface {
+	toSql(p Pinls) squirrel.Sqlizer
+}
+
+// PinlFilterAnd matches the pinls matching all filters.
+type PinlFilterAnd []PinlFilter
+
+// PinlFilterOr matches the pinls matching any filter.
+type PinlFilterOr []PinlFilter
+
+// PinlFilterNot matches the pinls not matching Filter.
+type PinlFilterNot struct {
+	Filter PinlFilter
+}
+
+// PinlFilterText matches by full-text search. Phrase requires the
+// words to be adjacent.
+type PinlFilterText struct {
+	Text   string
+	Phrase bool
+}
+
+// PinlFilterTag matches the pinls having a tag of name pattern.
+type PinlFilterTag struct {
+	NamePattern string
+}
+
+// PinlFilterURL matches the pinls whose url contains URL.
+type PinlFilterURL struct {
+	URL string
+}
+
+// PinlFilterProvider matches the pinls monitored by provider.
+type PinlFilterProvider struct {
+	Provider string
+}
+
+// PinlFilterHas matches the pinls having tag, pkg or stat of kind.
+type PinlFilterHas struct {
+	What string
+}
+
+// PinlFilterReleased matches by the time of latest release.
+type PinlFilterReleased struct {
+	Op   string
+	Time time.Time
+}
+
+// PinlFilterStat matches by the numeric value of latest stat.
+type PinlFilterStat struct {
+	Kind  model.StatKind
+	Op    string
+	Value float64
+}
+
+// Values of PinlFilterHas.
+const (
+	PinlHasTag     = "tag"
+	PinlHasPkg     = "pkg"
+	PinlHasRelease = "release"
+)
+
+var pinlReleaseKinds = []model.StatKind{model.TagStat}
+
+type notSqlizer struct {
+	squirrel.Sqlizer
+}
+
+func (n notSqlizer) ToSql() (string, []interface{}, error) {
+	sql, args, err := n.Sqlizer.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT (" + sql + ")", args, nil
+}
+
+func (f PinlFilterAnd) toSql(p Pinls) squirrel.Sqlizer {
+	and := make(squirrel.And, len(f))
+	for i, sub := range f {
+		and[i] = sub.toSql(p)
+	}
+	return and
+}
+
+func (f PinlFilterOr) toSql(p Pinls) squirrel.Sqlizer {
+	or := make(squirrel.Or, len(f))
+	for i, sub := range f {
+		or[i] = sub.toSql(p)
+	}
+	return or
+}
+
+func (f PinlFilterNot) toSql(p Pinls) squirrel.Sqlizer {
+	return notSqlizer{f.Filter.toSql(p)}
+}
+
+func (f PinlFilterText) toSql(p Pinls) squirrel.Sqlizer {
+	terms := searchTerms(f.Text)
+	if len(terms) > 0 {
+		switch p.db.DriverName() {
+		case "sqlite3":
+			match := ftsMatchQuery(terms, false)
+			if f.Phrase {
+				match = ftsPhraseQuery(terms)
+			}
+			return squirrel.Expr(p.table()+".id IN (SELECT pinl_id FROM "+p.ftsTable()+" WHERE "+p.ftsTable()+" MATCH ?)", match)
+		case "postgres":
+			query := tsQuery(terms, false)
+			if f.Phrase {
+				query = tsPhraseQuery(terms)
+			}
+			return squirrel.Expr(p.table()+".search_vector @@ to_tsquery('simple', ?)", query)
+		}
+	}
+
+	pattern := "%" + f.Text + "%"
+	return squirrel.Or{
+		squirrel.Expr(p.table()+".title like ?", pattern),
+		squirrel.Expr(p.table()+".description like ?", pattern),
+		squirrel.Expr(p.table()+".url like ?", pattern),
+	}
+}
+
+func (f PinlFilterTag) toSql(p Pinls) squirrel.Sqlizer {
+	return squirrel.Select("1").
+		From(Taggables{}.table()).
+		Join(fmt.Sprintf("%s ON %[1]s.id = %s.tag_id", Tags{}.table(), Taggables{}.table())).
+		Where("target_id = "+p.table()+".id").
+		Where("target_name = ?", model.Pinl{}.MorphName()).
+		Where("name like ?", f.NamePattern).
+		Prefix("EXISTS (").
+		Suffix(")")
+}
+
+func (f PinlFilterURL) toSql(p Pinls) squirrel.Sqlizer {
+	return squirrel.Expr(p.table()+".url like ?", "%"+f.URL+"%")
+}
+
+func (f PinlFilterProvider) toSql(p Pinls) squirrel.Sqlizer {
+	return p.pkgsExists().
+		Join(fmt.Sprintf("%s ON %[1]s.id = %s.pkg_id", Pkgs{}.table(), Monpkgs{}.table())).
+		Where(Pkgs{}.table()+".provider = ?", f.Provider)
+}
+
+func (f PinlFilterHas) toSql(p Pinls) squirrel.Sqlizer {
+	switch f.What {
+	case PinlHasTag:
+		return squirrel.Select("1").
+			From(Taggables{}.table()).
+			Where("target_id = "+p.table()+".id").
+			Where("target_name = ?", model.Pinl{}.MorphName()).
+			Prefix("EXISTS (").
+			Suffix(")")
+	case PinlHasPkg:
+		return p.pkgsExists()
+	case PinlHasRelease:
+		return p.statsExists().
+			Where(squirrel.Eq{Stats{}.table() + ".kind": pinlReleaseKinds})
+	}
+	return p.statsExists().
+		Where(Stats{}.table()+".kind = ?", model.StatKind(f.What))
+}
+
+func (f PinlFilterReleased) toSql(p Pinls) squirrel.Sqlizer {
+	return p.statsExists().
+		Where(squirrel.Eq{Stats{}.table() + ".kind": pinlReleaseKinds}).
+		Where(Stats{}.table()+".is_latest = ?", true).
+		Where(Stats{}.table()+".recorded_at "+f.Op+" ?", f.Time)
+}
+
+func (f PinlFilterStat) toSql(p Pinls) squirrel.Sqlizer {
+	return p.statsExists().
+		Where(Stats{}.table()+".kind = ?", f.Kind).
+		Where(Stats{}.table()+".is_latest = ?", true).
+		Where("CAST("+Stats{}.table()+".value AS NUMERIC) "+f.Op+" ?", f.Value)
+}
+
+// pkgsExists returns the subquery of pkgs monitored by pinl. The
+// subqueries use "?" placeholders, which are numbered by the outer
+// query on postgres, as nested builders would be numbered from $1 again.
+func (p Pinls) pkgsExists() squirrel.SelectBuilder {
+	return squirrel.Select("1").
+		From(Monpkgs{}.table()).
+		Where(Monpkgs{}.table() + ".monl_id = " + p.table() + ".monl_id").
+		Prefix("EXISTS (").
+		Suffix(")")
+}
+
+// statsExists returns the subquery of stats of the pkgs monitored
+// by pinl.
+func (p Pinls) statsExists() squirrel.SelectBuilder {
+	return p.pkgsExists().
+		Join(fmt.Sprintf("%s ON %[1]s.pkg_id = %s.pkg_id", Stats{}.table(), Monpkgs{}.table()))
+}
+
+// ftsPhraseQuery builds the FTS5 query which matches the adjacent
+// terms.
+func ftsPhraseQuery(terms []string) string {
+	return `{title description url} : "` + strings.Join(terms, " ") + `"`
+}
+
+// tsPhraseQuery builds the tsquery which matches the adjacent terms.
+func tsPhraseQuery(terms []string) string {
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = strings.ToLower(term) + ":ABC"
+	}
+	return strings.Join(lexemes, " <-> ")
+}
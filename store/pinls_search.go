@@ -0,0 +1,126 @@
+package store
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Markers of the matched terms in snippet, which are turned into
+// highlight tags after the snippet is escaped.
+const (
+	snippetStart = "\x02"
+	snippetStop  = "\x03"
+)
+
+func (p Pinls) ftsTable() string {
+	return "pinls_fts"
+}
+
+// bindSearch filters pinls by full-text search. LIKE is used if the
+// driver has no full-text search support.
+func (p Pinls) bindSearch(b squirrel.SelectBuilder, opts *PinlOpts) squirrel.SelectBuilder {
+	terms := searchTerms(opts.Query)
+	if len(terms) > 0 {
+		switch p.db.DriverName() {
+		case "sqlite3":
+			return b.Join(p.ftsTable()+" ON "+p.ftsTable()+".pinl_id = "+p.table()+".id").
+				Where(p.ftsTable()+" MATCH ?", ftsMatchQuery(terms, opts.SearchContent))
+		case "postgres":
+			return b.Where(p.table()+".search_vector @@ to_tsquery('simple', ?)", tsQuery(terms, opts.SearchContent))
+		}
+	}
+
+	return b.Where(squirrel.Or{
+		squirrel.Expr(p.table()+".title like ?", "%"+opts.Query+"%"),
+		squirrel.Expr(p.table()+".description like ?", "%"+opts.Query+"%"),
+		squirrel.Expr(p.table()+".url like ?", "%"+opts.Query+"%"),
+	})
+}
+
+// orderByRelevance sorts the search result by rank.
+func (p Pinls) orderByRelevance(b squirrel.SelectBuilder, opts *PinlOpts) squirrel.SelectBuilder {
+	terms := searchTerms(opts.Query)
+	if len(terms) == 0 {
+		return b
+	}
+	switch p.db.DriverName() {
+	case "sqlite3":
+		// Weights follow the columns: pinl_id, title, description, url
+		// and content.
+		return b.OrderBy("bm25(" + p.ftsTable() + ", 0.0, 10.0, 5.0, 2.0, 1.0)")
+	case "postgres":
+		return b.OrderByClause("ts_rank("+p.table()+".search_vector, to_tsquery('simple', ?)) DESC", tsQuery(terms, opts.SearchContent))
+	}
+	return b
+}
+
+// snippetColumn returns the column of the highlighted snippet of
+// search result.
+func (p Pinls) snippetColumn(opts *PinlOpts) (string, []interface{}, bool) {
+	terms := searchTerms(opts.Query)
+	if len(terms) == 0 {
+		return "", nil, false
+	}
+	switch p.db.DriverName() {
+	case "sqlite3":
+		return "snippet(" + p.ftsTable() + ", -1, ?, ?, '...', 16)",
+			[]interface{}{snippetStart, snippetStop}, true
+	case "postgres":
+		doc := "COALESCE(" + p.table() + ".title, '') || ' ' || COALESCE(" + p.table() + ".description, '')"
+		if opts.SearchContent {
+			doc += " || ' ' || " + p.table() + ".content"
+		}
+		return "ts_headline('simple', " + doc + ", to_tsquery('simple', ?), ?)",
+			[]interface{}{
+				tsQuery(terms, opts.SearchContent),
+				"StartSel=" + snippetStart + ", StopSel=" + snippetStop + ", MinWords=8, MaxWords=16",
+			}, true
+	}
+	return "", nil, false
+}
+
+// searchTerms splits the query into words.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// ftsMatchQuery builds the FTS5 query which matches all terms by
+// prefix.
+func ftsMatchQuery(terms []string, withContent bool) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"*`
+	}
+	query := strings.Join(quoted, " ")
+	if !withContent {
+		query = "{title description url} : (" + query + ")"
+	}
+	return query
+}
+
+// tsQuery builds the tsquery which matches all terms by prefix.
+// Content is weighted D so that it is excluded by the weight filter.
+func tsQuery(terms []string, withContent bool) string {
+	suffix := ":*"
+	if !withContent {
+		suffix += "ABC"
+	}
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = strings.ToLower(term) + suffix
+	}
+	return strings.Join(lexemes, " & ")
+}
+
+// highlightSnippet escapes the snippet and marks the matched terms.
+func highlightSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, snippetStart, "<mark>")
+	snippet = strings.ReplaceAll(snippet, snippetStop, "</mark>")
+	return snippet
+}
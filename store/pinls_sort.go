@@ -0,0 +1,104 @@
+package store
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/model"
+)
+
+// sortKeys returns the keys of orders followed by id. False is returned
+// if the orders cannot be paginated by cursor, i.e. sorted by rank.
+func (p Pinls) sortKeys(opts *PinlOpts) ([]sortKey, bool) {
+	if len(opts.Orders) == 0 {
+		return nil, false
+	}
+
+	keys := make([]sortKey, 0, len(opts.Orders)+1)
+	for _, order := range opts.Orders {
+		if order == PinlOrderByRelevance {
+			if opts.Query != "" {
+				return nil, false
+			}
+			continue
+		}
+		if key, ok := p.sortKey(order, opts); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	id := sortKey{expr: p.table() + ".id"}
+	if len(keys) > 0 {
+		id.desc = keys[len(keys)-1].desc
+	}
+	return append(keys, id), true
+}
+
+// sortKey returns the expression of order. Orders that cannot be
+// applied, e.g. position without tag, are skipped.
+func (p Pinls) sortKey(order PinlOrder, opts *PinlOpts) (sortKey, bool) {
+	switch order {
+	case PinlOrderByLatest, PinlOrderByOldest:
+		return sortKey{
+			expr: p.table() + ".created_at",
+			desc: order == PinlOrderByLatest,
+		}, true
+	case PinlOrderByTitle, PinlOrderByTitleDesc:
+		return sortKey{
+			expr: "LOWER(COALESCE(" + p.table() + ".title, ''))",
+			desc: order == PinlOrderByTitleDesc,
+		}, true
+	case PinlOrderByUpdated, PinlOrderByUpdatedDesc:
+		return sortKey{
+			expr: p.table() + ".updated_at",
+			desc: order == PinlOrderByUpdatedDesc,
+		}, true
+	case PinlOrderByHost, PinlOrderByHostDesc:
+		return sortKey{
+			expr: p.table() + ".host",
+			desc: order == PinlOrderByHostDesc,
+		}, true
+	case PinlOrderByReleased, PinlOrderByReleasedDesc:
+		// Pinls without release are the oldest.
+		sq := p.latestStatsQuery("MAX(" + Stats{}.table() + ".recorded_at)").
+			Where(squirrel.Eq{Stats{}.table() + ".kind": pinlReleaseKinds})
+		return subqueryKey(sq, "'0001-01-01 00:00:00'", order == PinlOrderByReleasedDesc)
+	case PinlOrderByStars, PinlOrderByStarsDesc:
+		sq := p.latestStatsQuery("MAX(CAST("+Stats{}.table()+".value AS NUMERIC))").
+			Where(Stats{}.table()+".kind = ?", model.StarCountStat)
+		return subqueryKey(sq, "-1", order == PinlOrderByStarsDesc)
+	case PinlOrderByPosition, PinlOrderByPositionDesc:
+		if opts.PositionTagID == "" {
+			return sortKey{}, false
+		}
+		sq := squirrel.Select(Taggables{}.table()+".position").
+			From(Taggables{}.table()).
+			Where(Taggables{}.table()+".target_id = "+p.table()+".id").
+			Where(Taggables{}.table()+".target_name = ?", model.Pinl{}.MorphName()).
+			Where(Taggables{}.table()+".tag_id = ?", opts.PositionTagID)
+		return subqueryKey(sq, "0", order == PinlOrderByPositionDesc)
+	}
+	return sortKey{}, false
+}
+
+// latestStatsQuery returns the subquery of latest stats of the pkgs
+// monitored by pinl.
+func (p Pinls) latestStatsQuery(column string) squirrel.SelectBuilder {
+	return squirrel.Select(column).
+		From(Monpkgs{}.table()).
+		Join(Stats{}.table()+" ON "+Stats{}.table()+".pkg_id = "+Monpkgs{}.table()+".pkg_id").
+		Where(Monpkgs{}.table()+".monl_id = "+p.table()+".monl_id").
+		Where(Stats{}.table()+".is_latest = ?", true)
+}
+
+// subqueryKey returns the key of subquery, which is replaced by
+// fallback if no row is found.
+func subqueryKey(sq squirrel.SelectBuilder, fallback string, desc bool) (sortKey, bool) {
+	query, args, err := sq.ToSql()
+	if err != nil {
+		return sortKey{}, false
+	}
+	return sortKey{
+		expr: "COALESCE((" + query + "), " + fallback + ")",
+		args: args,
+		desc: desc,
+	}, true
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/database"
 	"github.com/pinmonl/pinmonl/database/dbtest"
 	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/pinlutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +30,8 @@ func TestPinls(t *testing.T) {
 	t.Run("find", testPinlsFind(ctx, pinls, mock))
 	t.Run("create", testPinlsCreate(ctx, pinls, mock))
 	t.Run("update", testPinlsUpdate(ctx, pinls, mock))
+	t.Run("update monl", testPinlsUpdateMonl(ctx, pinls, mock))
+	t.Run("update timestamps", testPinlsUpdateTimestamps(ctx, pinls, mock))
 	t.Run("delete", testPinlsDelete(ctx, pinls, mock))
 }
 
@@ -46,6 +53,66 @@ func testPinlsList(ctx context.Context, pinls *Pinls, mock sqlmock.Sqlmock) func
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(list))
 
+		// Test filter by urls.
+		opts = &PinlOpts{URLs: []string{"http://somewhere.com", "http://elsewhere.com"}}
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT "+pinls.columns()[0])+"(.+)"+regexp.QuoteMeta("FROM pinls WHERE pinls.url IN (?,?)")).
+			WithArgs("http://somewhere.com", "http://elsewhere.com").
+			WillReturnRows(sqlmock.NewRows(pinls.columns()).
+				AddRow("pinl-id-1", "user-id-1", "monl-id-1", "http://somewhere.com", "title", "description", "", model.Active, nil, nil))
+		list, err = pinls.List(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+
+		// Test search without full-text support.
+		opts = &PinlOpts{Query: "keyword", Orders: []PinlOrder{PinlOrderByRelevance}}
+		mock.ExpectQuery(regexp.QuoteMeta("FROM pinls WHERE (pinls.title like ? OR pinls.description like ? OR pinls.url like ?)")).
+			WithArgs("%keyword%", "%keyword%", "%keyword%").
+			WillReturnRows(sqlmock.NewRows(pinls.columns()).
+				AddRow("pinl-id-1", "user-id-1", "monl-id-1", "http://somewhere.com", "title", "keyword", "", model.Active, nil, nil))
+		list, err = pinls.List(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+
+		// Test filter expression.
+		opts = &PinlOpts{Filter: PinlFilterAnd{
+			PinlFilterProvider{Provider: "npm"},
+			PinlFilterNot{Filter: PinlFilterStat{Kind: model.StarCountStat, Op: ">", Value: 1000}},
+		}}
+		mock.ExpectQuery(regexp.QuoteMeta("FROM pinls WHERE (EXISTS ( SELECT 1 FROM monpkgs JOIN pkgs ON pkgs.id = monpkgs.pkg_id WHERE monpkgs.monl_id = pinls.monl_id AND pkgs.provider = ? ) AND "+
+			"NOT (EXISTS ( SELECT 1 FROM monpkgs JOIN stats ON stats.pkg_id = monpkgs.pkg_id WHERE monpkgs.monl_id = pinls.monl_id AND stats.kind = ? AND stats.is_latest = ? AND CAST(stats.value AS NUMERIC) > ? )))")).
+			WithArgs("npm", model.StarCountStat, true, float64(1000)).
+			WillReturnRows(sqlmock.NewRows(pinls.columns()).
+				AddRow("pinl-id-1", "user-id-1", "monl-id-1", "http://somewhere.com", "title", "description", "", model.Active, nil, nil))
+		list, err = pinls.List(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+
+		// Test sort by title after cursor.
+		opts = &PinlOpts{
+			Orders: []PinlOrder{PinlOrderByTitle},
+		}
+		opts.Cursor = &Cursor{Keys: []interface{}{"title"}, ID: "pinl-id-1"}
+		title := "LOWER(COALESCE(pinls.title, ''))"
+		mock.ExpectQuery(regexp.QuoteMeta(", "+title+" FROM pinls WHERE (("+title+" > ?) OR ("+title+" = ? AND pinls.id > ?)) ORDER BY "+title+" ASC, pinls.id ASC")).
+			WithArgs("title", "title", "pinl-id-1").
+			WillReturnRows(sqlmock.NewRows(append(pinls.columns(), title)).
+				AddRow("pinl-id-2", "user-id-1", "monl-id-1", "http://somewhere.com", "title", "description", "", model.Active, nil, nil, "title"))
+		list, err = pinls.List(ctx, opts)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			cursor, err := DecodeCursor(list[0].Cursor)
+			assert.Nil(t, err)
+			assert.Equal(t, &Cursor{Keys: []interface{}{"title"}, ID: "pinl-id-2"}, cursor)
+		}
+
+		// Test cursor not matching the orders.
+		opts = &PinlOpts{
+			Orders: []PinlOrder{PinlOrderByTitle, PinlOrderByLatest},
+		}
+		opts.Cursor = &Cursor{Keys: []interface{}{"title"}, ID: "pinl-id-1"}
+		_, err = pinls.List(ctx, opts)
+		assert.Equal(t, ErrInvalidCursor, err)
+
 		// Test filter by user.
 		// Test filter by users.
 		// Test filter by monls.
@@ -120,6 +187,7 @@ func expectPinlsCreate(mock sqlmock.Sqlmock, pinl *model.Pinl) {
 			pinl.Description,
 			pinl.ImageID,
 			pinl.Status,
+			pinlutils.Host(pinl.URL),
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1))
@@ -150,11 +218,44 @@ func expectPinlsUpdate(mock sqlmock.Sqlmock, pinl *model.Pinl) {
 			pinl.Description,
 			pinl.ImageID,
 			pinl.Status,
+			pinlutils.Host(pinl.URL),
 			sqlmock.AnyArg(),
 			pinl.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
 
+func testPinlsUpdateMonl(ctx context.Context, pinls *Pinls, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE pinls SET monl_id = ? WHERE id = ?")
+		mock.ExpectExec(query).
+			WithArgs("monl-id-1", "pinl-id-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err := pinls.UpdateMonl(ctx, "pinl-id-1", "monl-id-1")
+		assert.Nil(t, err)
+	}
+}
+
+func testPinlsUpdateTimestamps(ctx context.Context, pinls *Pinls, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("UPDATE pinls SET created_at = ?, updated_at = ? WHERE id = ?")
+			pinl  *model.Pinl
+			err   error
+		)
+
+		pinl = &model.Pinl{
+			ID:        "pinl-id-1",
+			CreatedAt: field.Time(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			UpdatedAt: field.Time(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+		}
+		mock.ExpectExec(query).
+			WithArgs(pinl.CreatedAt, pinl.UpdatedAt, pinl.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = pinls.UpdateTimestamps(ctx, pinl)
+		assert.Nil(t, err)
+	}
+}
+
 func testPinlsDelete(ctx context.Context, pinls *Pinls, mock sqlmock.Sqlmock) func(*testing.T) {
 	return func(t *testing.T) {
 		var (
@@ -173,3 +274,72 @@ func testPinlsDelete(ctx context.Context, pinls *Pinls, mock sqlmock.Sqlmock) fu
 		assert.Equal(t, int64(1), n)
 	}
 }
+
+func TestPinlsSearchQuery(t *testing.T) {
+	tests := []struct {
+		query       string
+		withContent bool
+		terms       []string
+		fts         string
+		ts          string
+	}{
+		{
+			query: "go  http-client",
+			terms: []string{"go", "http", "client"},
+			fts:   `{title description url} : ("go"* "http"* "client"*)`,
+			ts:    "go:*ABC & http:*ABC & client:*ABC",
+		},
+		{
+			query:       `Docker "compose"`,
+			withContent: true,
+			terms:       []string{"Docker", "compose"},
+			fts:         `"Docker"* "compose"*`,
+			ts:          "docker:* & compose:*",
+		},
+		{
+			query: "' OR 1=1 --",
+			terms: []string{"OR", "1", "1"},
+			fts:   `{title description url} : ("OR"* "1"* "1"*)`,
+			ts:    "or:*ABC & 1:*ABC & 1:*ABC",
+		},
+	}
+	for _, test := range tests {
+		terms := searchTerms(test.query)
+		assert.Equal(t, test.terms, terms)
+		assert.Equal(t, test.fts, ftsMatchQuery(terms, test.withContent))
+		assert.Equal(t, test.ts, tsQuery(terms, test.withContent))
+	}
+
+	assert.Empty(t, searchTerms("!!! ---"))
+	assert.Equal(t, "a <mark>&lt;b&gt;</mark> c", highlightSnippet("a "+snippetStart+"<b>"+snippetStop+" c"))
+}
+
+func TestPinlsFilterPostgres(t *testing.T) {
+	db := &database.DB{
+		Builder: database.NewBuilderFromBase(squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)),
+	}
+	pinls := NewPinls(NewStore(db))
+
+	opts := &PinlOpts{
+		UserID: "user-id-1",
+		Filter: PinlFilterAnd{
+			PinlFilterTag{NamePattern: "lang/go"},
+			PinlFilterNot{Filter: PinlFilterTag{NamePattern: "archived"}},
+			PinlFilterOr{
+				PinlFilterNot{Filter: PinlFilterProvider{Provider: "npm"}},
+				PinlFilterHas{What: PinlHasRelease},
+			},
+		},
+	}
+	query, args, err := pinls.bindOpts(pinls.Builder().Select("pinls.id").From("pinls"), opts).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT pinls.id FROM pinls WHERE user_id IN ($1) AND ("+
+		"EXISTS ( SELECT 1 FROM taggables JOIN tags ON tags.id = taggables.tag_id WHERE target_id = pinls.id AND target_name = $2 AND name like $3 ) AND "+
+		"NOT (EXISTS ( SELECT 1 FROM taggables JOIN tags ON tags.id = taggables.tag_id WHERE target_id = pinls.id AND target_name = $4 AND name like $5 )) AND "+
+		"(NOT (EXISTS ( SELECT 1 FROM monpkgs JOIN pkgs ON pkgs.id = monpkgs.pkg_id WHERE monpkgs.monl_id = pinls.monl_id AND pkgs.provider = $6 )) OR "+
+		"EXISTS ( SELECT 1 FROM monpkgs JOIN stats ON stats.pkg_id = monpkgs.pkg_id WHERE monpkgs.monl_id = pinls.monl_id AND stats.kind IN ($7) )))", query)
+	assert.Equal(t, []interface{}{"user-id-1",
+		model.Pinl{}.MorphName(), "lang/go",
+		model.Pinl{}.MorphName(), "archived",
+		"npm", model.TagStat}, args)
+}
@@ -35,6 +35,10 @@ func (p *Pinpkgs) List(ctx context.Context, opts *PinpkgOpts) (model.PinpkgList,
 	qb := p.RunnableBuilder(ctx).
 		Select(p.columns()...).From(p.table())
 	qb = p.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(p.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -49,6 +53,7 @@ func (p *Pinpkgs) List(ctx context.Context, opts *PinpkgOpts) (model.PinpkgList,
 		}
 		list = append(list, pinpkg)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -39,6 +39,10 @@ func (p *Pkgs) List(ctx context.Context, opts *PkgOpts) (model.PkgList, error) {
 	qb := p.RunnableBuilder(ctx).
 		Select(p.columns()...).From(p.table())
 	qb = p.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(p.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -53,6 +57,7 @@ func (p *Pkgs) List(ctx context.Context, opts *PkgOpts) (model.PkgList, error) {
 		}
 		list = append(list, pkg)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+)
+
+type Presets struct {
+	*Store
+}
+
+type PresetOpts struct {
+	ListOpts
+	UserID  string
+	UserIDs []string
+}
+
+func NewPresets(s *Store) *Presets {
+	return &Presets{s}
+}
+
+func (p Presets) table() string {
+	return "presets"
+}
+
+func (p *Presets) List(ctx context.Context, opts *PresetOpts) (model.PresetList, error) {
+	if opts == nil {
+		opts = &PresetOpts{}
+	}
+
+	qb := p.RunnableBuilder(ctx).
+		Select(p.columns()...).From(p.table()).
+		OrderBy("name")
+	qb = p.bindOpts(qb, opts)
+	qb, err := addCursor(qb, nil, opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	qb = addPagination(qb, opts)
+	rows, err := qb.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	list := make([]*model.Preset, 0)
+	for rows.Next() {
+		preset, err := p.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, preset)
+	}
+	reverseList(list, opts.Cursor)
+	return list, nil
+}
+
+func (p *Presets) Count(ctx context.Context, opts *PresetOpts) (int64, error) {
+	if opts == nil {
+		opts = &PresetOpts{}
+	}
+
+	qb := p.RunnableBuilder(ctx).
+		Select("count(*)").From(p.table())
+	qb = p.bindOpts(qb, opts)
+	row := qb.QueryRow()
+	var count int64
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (p *Presets) Find(ctx context.Context, id string) (*model.Preset, error) {
+	qb := p.RunnableBuilder(ctx).
+		Select(p.columns()...).From(p.table()).
+		Where("id = ?", id)
+	row := qb.QueryRow()
+	preset, err := p.scan(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+func (p Presets) bindOpts(b squirrel.SelectBuilder, opts *PresetOpts) squirrel.SelectBuilder {
+	if opts == nil {
+		return b
+	}
+
+	if opts.UserID != "" {
+		opts.UserIDs = append(opts.UserIDs, opts.UserID)
+	}
+	if len(opts.UserIDs) > 0 {
+		b = b.Where(squirrel.Eq{"user_id": opts.UserIDs})
+	}
+
+	return b
+}
+
+func (p Presets) columns() []string {
+	return []string{
+		p.table() + ".id",
+		p.table() + ".user_id",
+		p.table() + ".name",
+		p.table() + ".query",
+		p.table() + ".tags",
+		p.table() + ".sort",
+		p.table() + ".created_at",
+		p.table() + ".updated_at",
+	}
+}
+
+func (p Presets) scanColumns(preset *model.Preset) []interface{} {
+	return []interface{}{
+		&preset.ID,
+		&preset.UserID,
+		&preset.Name,
+		&preset.Query,
+		&preset.TagNames,
+		&preset.Sort,
+		&preset.CreatedAt,
+		&preset.UpdatedAt,
+	}
+}
+
+func (p Presets) scan(row database.RowScanner) (*model.Preset, error) {
+	var preset model.Preset
+	err := row.Scan(p.scanColumns(&preset)...)
+	if err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+func (p *Presets) Create(ctx context.Context, preset *model.Preset) error {
+	preset2 := *preset
+	preset2.ID = newID()
+	preset2.CreatedAt = timestamp()
+	preset2.UpdatedAt = timestamp()
+
+	qb := p.RunnableBuilder(ctx).
+		Insert(p.table()).
+		Columns(
+			"id",
+			"user_id",
+			"name",
+			"query",
+			"tags",
+			"sort",
+			"created_at",
+			"updated_at").
+		Values(
+			preset2.ID,
+			preset2.UserID,
+			preset2.Name,
+			preset2.Query,
+			preset2.TagNames,
+			preset2.Sort,
+			preset2.CreatedAt,
+			preset2.UpdatedAt)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*preset = preset2
+	return nil
+}
+
+func (p *Presets) Update(ctx context.Context, preset *model.Preset) error {
+	preset2 := *preset
+	preset2.UpdatedAt = timestamp()
+
+	qb := p.RunnableBuilder(ctx).
+		Update(p.table()).
+		Set("user_id", preset2.UserID).
+		Set("name", preset2.Name).
+		Set("query", preset2.Query).
+		Set("tags", preset2.TagNames).
+		Set("sort", preset2.Sort).
+		Set("updated_at", preset2.UpdatedAt).
+		Where("id = ?", preset2.ID)
+	_, err := qb.Exec()
+	if err != nil {
+		return err
+	}
+	*preset = preset2
+	return nil
+}
+
+func (p *Presets) Delete(ctx context.Context, id string) (int64, error) {
+	qb := p.RunnableBuilder(ctx).
+		Delete(p.table()).
+		Where("id = ?", id)
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pinmonl/pinmonl/database/dbtest"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	db, mock, err := dbtest.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	ctx := context.TODO()
+	s := NewStore(db)
+	presets := NewPresets(s)
+
+	t.Run("list", testPresetsList(ctx, presets, mock))
+	t.Run("count", testPresetsCount(ctx, presets, mock))
+	t.Run("find", testPresetsFind(ctx, presets, mock))
+	t.Run("create", testPresetsCreate(ctx, presets, mock))
+	t.Run("update", testPresetsUpdate(ctx, presets, mock))
+	t.Run("delete", testPresetsDelete(ctx, presets, mock))
+}
+
+func testPresetsList(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			prefix = "SELECT (.+) FROM presets"
+			opts   *PresetOpts
+			list   []*model.Preset
+			err    error
+		)
+
+		// Test nil opts.
+		opts = nil
+		mock.ExpectQuery(prefix).
+			WillReturnRows(sqlmock.NewRows(presets.columns()).
+				AddRow("preset-id-1", "user-id-1", "go", "provider:github", `["lang/go"]`, "-created", nil, nil))
+		list, err = presets.List(ctx, opts)
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(list)) {
+			assert.Equal(t, field.StringList{"lang/go"}, list[0].TagNames)
+		}
+
+		// Test filter by user.
+		opts = &PresetOpts{UserID: "user-id-1"}
+		mock.ExpectQuery(regexp.QuoteMeta("FROM presets WHERE user_id IN (?) ORDER BY name")).
+			WithArgs("user-id-1").
+			WillReturnRows(sqlmock.NewRows(presets.columns()))
+		_, err = presets.List(ctx, opts)
+		assert.Nil(t, err)
+	}
+}
+
+func testPresetsCount(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("SELECT count(*) FROM presets WHERE user_id IN (?)")
+			opts  *PresetOpts
+			count int64
+			err   error
+		)
+
+		opts = &PresetOpts{UserID: "user-id-1"}
+		mock.ExpectQuery(query).
+			WithArgs("user-id-1").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).
+				AddRow(1))
+		count, err = presets.Count(ctx, opts)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	}
+}
+
+func testPresetsFind(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query  = "SELECT (.+) FROM presets WHERE id = \\?"
+			id     string
+			preset *model.Preset
+			err    error
+		)
+
+		id = "preset-id-1"
+		mock.ExpectQuery(query).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(presets.columns()).
+				AddRow(id, "user-id-1", "go", "provider:github", "", "", nil, nil))
+		preset, err = presets.Find(ctx, id)
+		assert.Nil(t, err)
+		if assert.NotNil(t, preset) {
+			assert.Equal(t, id, preset.ID)
+			assert.Empty(t, preset.TagNames)
+		}
+	}
+}
+
+func testPresetsCreate(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			preset *model.Preset
+			err    error
+		)
+
+		preset = &model.Preset{Name: "go", TagNames: field.StringList{"lang/go"}}
+		expectPresetsCreate(mock, preset)
+		err = presets.Create(ctx, preset)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, preset.ID)
+		assert.NotEmpty(t, preset.CreatedAt)
+	}
+}
+
+func expectPresetsCreate(mock sqlmock.Sqlmock, preset *model.Preset) {
+	mock.ExpectExec("INSERT INTO presets").
+		WithArgs(
+			sqlmock.AnyArg(),
+			preset.UserID,
+			preset.Name,
+			preset.Query,
+			`["lang/go"]`,
+			preset.Sort,
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testPresetsUpdate(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			preset *model.Preset
+			err    error
+		)
+
+		preset = &model.Preset{ID: "preset-id-1"}
+		expectPresetsUpdate(mock, preset)
+		err = presets.Update(ctx, preset)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, preset.UpdatedAt)
+	}
+}
+
+func expectPresetsUpdate(mock sqlmock.Sqlmock, preset *model.Preset) {
+	mock.ExpectExec("UPDATE presets (.+) WHERE id = \\?").
+		WithArgs(
+			preset.UserID,
+			preset.Name,
+			preset.Query,
+			"",
+			preset.Sort,
+			sqlmock.AnyArg(),
+			preset.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func testPresetsDelete(ctx context.Context, presets *Presets, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("DELETE FROM presets WHERE id = ?")
+			id    string
+			n     int64
+			err   error
+		)
+
+		id = "preset-id-1"
+		mock.ExpectExec(query).
+			WithArgs(id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		n, err = presets.Delete(ctx, id)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+}
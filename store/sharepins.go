@@ -25,8 +25,16 @@ type SharepinOpts struct {
 	joinPinls bool
 
 	TagIDs []string
+
+	Orders []SharepinOrder
 }
 
+type SharepinOrder int
+
+const (
+	SharepinOrderByPinlLatest SharepinOrder = iota
+)
+
 func NewSharepins(s *Store) *Sharepins {
 	return &Sharepins{s}
 }
@@ -43,6 +51,10 @@ func (s *Sharepins) List(ctx context.Context, opts *SharepinOpts) (model.Sharepi
 	qb := s.RunnableBuilder(ctx).
 		Select(s.columns()...).From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, s.sortKeys(opts), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -57,6 +69,7 @@ func (s *Sharepins) List(ctx context.Context, opts *SharepinOpts) (model.Sharepi
 		}
 		list = append(list, sharepin)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -122,6 +135,10 @@ func (s *Sharepins) ListWithPinl(ctx context.Context, opts *SharepinOpts) (model
 		Columns(Pinls{}.columns()...).
 		From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, s.sortKeys(opts), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -143,6 +160,7 @@ func (s *Sharepins) ListWithPinl(ctx context.Context, opts *SharepinOpts) (model
 		msp.Pinl = &mp
 		list = append(list, &msp)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -188,6 +206,14 @@ func (s Sharepins) bindOpts(b squirrel.SelectBuilder, opts *SharepinOpts) squirr
 		b = b.Where(sq)
 	}
 
+	for _, order := range opts.Orders {
+		switch order {
+		case SharepinOrderByPinlLatest:
+			opts = opts.JoinPinls()
+			b = b.OrderBy(Pinls{}.table()+".created_at DESC", s.table()+".id DESC")
+		}
+	}
+
 	if opts.joinPinls {
 		b = b.Join(fmt.Sprintf("%s ON %[1]s.id = %s.pinl_id", Pinls{}.table(), s.table()))
 	}
@@ -195,6 +221,15 @@ func (s Sharepins) bindOpts(b squirrel.SelectBuilder, opts *SharepinOpts) squirr
 	return b
 }
 
+// sortKeys returns the key of id if no order is given. Nil is returned
+// for the other orders, which cannot be paginated by cursor.
+func (s Sharepins) sortKeys(opts *SharepinOpts) []sortKey {
+	if len(opts.Orders) > 0 {
+		return nil
+	}
+	return idKeys(s.table())
+}
+
 func (s Sharepins) columns() []string {
 	return []string{
 		s.table() + ".id",
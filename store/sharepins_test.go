@@ -62,6 +62,13 @@ func testSharepinsList(ctx context.Context, sharepins *Sharepins, mock sqlmock.S
 			WillReturnRows(sqlmock.NewRows(sharepins.columns()))
 		_, err = sharepins.List(ctx, opts)
 		assert.Nil(t, err)
+
+		// Test order by latest pinls.
+		opts = &SharepinOpts{Orders: []SharepinOrder{SharepinOrderByPinlLatest}}
+		mock.ExpectQuery(fmt.Sprintf(regexp.QuoteMeta("%s JOIN pinls ON pinls.id = sharepins.pinl_id ORDER BY pinls.created_at DESC, sharepins.id DESC"), prefix)).
+			WillReturnRows(sqlmock.NewRows(sharepins.columns()))
+		_, err = sharepins.List(ctx, opts)
+		assert.Nil(t, err)
 	}
 }
 
@@ -16,10 +16,11 @@ type Shares struct {
 
 type ShareOpts struct {
 	ListOpts
-	UserID  string
-	UserIDs []string
-	Slug    string
-	Status  field.NullValue
+	UserID   string
+	UserIDs  []string
+	Slug     string
+	Status   field.NullValue
+	PresetID string
 }
 
 func NewShares(s *Store) *Shares {
@@ -38,6 +39,10 @@ func (s *Shares) List(ctx context.Context, opts *ShareOpts) (model.ShareList, er
 	qb := s.RunnableBuilder(ctx).
 		Select(s.columns()...).From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(s.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -52,6 +57,7 @@ func (s *Shares) List(ctx context.Context, opts *ShareOpts) (model.ShareList, er
 		}
 		list = append(list, share)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -125,6 +131,10 @@ func (s Shares) bindOpts(b squirrel.SelectBuilder, opts *ShareOpts) squirrel.Sel
 		}
 	}
 
+	if opts.PresetID != "" {
+		b = b.Where("preset_id = ?", opts.PresetID)
+	}
+
 	return b
 }
 
@@ -137,6 +147,7 @@ func (s Shares) columns() []string {
 		s.table() + ".description",
 		s.table() + ".image_id",
 		s.table() + ".status",
+		s.table() + ".preset_id",
 		s.table() + ".created_at",
 		s.table() + ".updated_at",
 	}
@@ -151,6 +162,7 @@ func (s Shares) scanColumns(share *model.Share) []interface{} {
 		&share.Description,
 		&share.ImageID,
 		&share.Status,
+		&share.PresetID,
 		&share.CreatedAt,
 		&share.UpdatedAt,
 	}
@@ -181,6 +193,7 @@ func (s *Shares) Create(ctx context.Context, share *model.Share) error {
 			"description",
 			"image_id",
 			"status",
+			"preset_id",
 			"created_at",
 			"updated_at").
 		Values(
@@ -191,6 +204,7 @@ func (s *Shares) Create(ctx context.Context, share *model.Share) error {
 			share2.Description,
 			share2.ImageID,
 			share2.Status,
+			share2.PresetID,
 			share2.CreatedAt,
 			share2.UpdatedAt)
 	_, err := qb.Exec()
@@ -213,6 +227,7 @@ func (s *Shares) Update(ctx context.Context, share *model.Share) error {
 		Set("description", share2.Description).
 		Set("image_id", share2.ImageID).
 		Set("status", share2.Status).
+		Set("preset_id", share2.PresetID).
 		Set("updated_at", share2.UpdatedAt).
 		Where("id = ?", share2.ID)
 	_, err := qb.Exec()
@@ -223,6 +238,25 @@ func (s *Shares) Update(ctx context.Context, share *model.Share) error {
 	return nil
 }
 
+// ClearPreset unsets the preset of shares.
+func (s *Shares) ClearPreset(ctx context.Context, presetID string) (int64, error) {
+	qb := s.RunnableBuilder(ctx).
+		Update(s.table()).
+		Set("preset_id", "").
+		Where("preset_id = ?", presetID)
+	res, err := qb.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// UpdateTimestamps sets the created and updated time of the share,
+// e.g. restored from archive.
+func (s *Shares) UpdateTimestamps(ctx context.Context, share *model.Share) error {
+	return s.updateTimestamps(ctx, s.table(), share.ID, share.CreatedAt, share.UpdatedAt)
+}
+
 func (s *Shares) Delete(ctx context.Context, id string) (int64, error) {
 	qb := s.RunnableBuilder(ctx).
 		Delete(s.table()).
@@ -25,6 +25,7 @@ func TestShares(t *testing.T) {
 	t.Run("find", testSharesFind(ctx, shares, mock))
 	t.Run("create", testSharesCreate(ctx, shares, mock))
 	t.Run("update", testSharesUpdate(ctx, shares, mock))
+	t.Run("clearPreset", testSharesClearPreset(ctx, shares, mock))
 	t.Run("delete", testSharesDelete(ctx, shares, mock))
 }
 
@@ -41,7 +42,7 @@ func testSharesList(ctx context.Context, shares *Shares, mock sqlmock.Sqlmock) f
 		opts = nil
 		mock.ExpectQuery(prefix).
 			WillReturnRows(sqlmock.NewRows(shares.columns()).
-				AddRow("share-id-1", "user-id-1", "user/share", "share name", "description", "", model.Active, nil, nil))
+				AddRow("share-id-1", "user-id-1", "user/share", "share name", "description", "", model.Active, "", nil, nil))
 		list, err = shares.List(ctx, opts)
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(list))
@@ -80,7 +81,7 @@ func testSharesFind(ctx context.Context, shares *Shares, mock sqlmock.Sqlmock) f
 		mock.ExpectQuery(query).
 			WithArgs(id).
 			WillReturnRows(sqlmock.NewRows(shares.columns()).
-				AddRow(id, "user-id-1", "user/share", "share name", "description", "", model.Active, nil, nil))
+				AddRow(id, "user-id-1", "user/share", "share name", "description", "", model.Active, "", nil, nil))
 		share, err = shares.Find(ctx, id)
 		assert.Nil(t, err)
 		if assert.NotNil(t, share) {
@@ -115,6 +116,7 @@ func expectSharesCreate(mock sqlmock.Sqlmock, share *model.Share) {
 			share.Description,
 			share.ImageID,
 			share.Status,
+			share.PresetID,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1))
@@ -144,11 +146,29 @@ func expectSharesUpdate(mock sqlmock.Sqlmock, share *model.Share) {
 			share.Description,
 			share.ImageID,
 			share.Status,
+			share.PresetID,
 			sqlmock.AnyArg(),
 			share.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
 
+func testSharesClearPreset(ctx context.Context, shares *Shares, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("UPDATE shares SET preset_id = ? WHERE preset_id = ?")
+			n     int64
+			err   error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs("", "preset-id-1").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		n, err = shares.ClearPreset(ctx, "preset-id-1")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), n)
+	}
+}
+
 func testSharesDelete(ctx context.Context, shares *Shares, mock sqlmock.Sqlmock) func(*testing.T) {
 	return func(t *testing.T) {
 		var (
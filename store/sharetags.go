@@ -45,6 +45,10 @@ func (s *Sharetags) List(ctx context.Context, opts *SharetagOpts) (model.Shareta
 	qb := s.RunnableBuilder(ctx).
 		Select(s.columns()...).From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(s.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -59,6 +63,7 @@ func (s *Sharetags) List(ctx context.Context, opts *SharetagOpts) (model.Shareta
 		}
 		list = append(list, sharetag)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -125,6 +130,10 @@ func (s *Sharetags) ListWithTag(ctx context.Context, opts *SharetagOpts) (model.
 		Columns(Tags{}.columns()...).
 		From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(s.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -146,6 +155,7 @@ func (s *Sharetags) ListWithTag(ctx context.Context, opts *SharetagOpts) (model.
 		mst.Tag = &mt
 		list = append(list, &mst)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
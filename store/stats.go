@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/pinmonl/pinmonl/database"
@@ -22,6 +23,8 @@ type StatOpts struct {
 	IsLatest      field.NullBool
 	Kinds         []model.StatKind
 	KindsExcluded []model.StatKind
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
 
 	Orders []StatOrder
 }
@@ -30,6 +33,9 @@ type StatOrder int
 
 const (
 	StatOrderByRecordDesc StatOrder = iota
+	// StatOrderByReleaseDesc falls back to the time first seen if the
+	// stat is not recorded with time.
+	StatOrderByReleaseDesc
 )
 
 func NewStats(s *Store) *Stats {
@@ -48,6 +54,10 @@ func (s *Stats) List(ctx context.Context, opts *StatOpts) (model.StatList, error
 	qb := s.RunnableBuilder(ctx).
 		Select(s.columns()...).From(s.table())
 	qb = s.bindOpts(qb, opts)
+	qb, err := addCursor(qb, s.sortKeys(opts), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -62,6 +72,7 @@ func (s *Stats) List(ctx context.Context, opts *StatOpts) (model.StatList, error
 		}
 		list = append(list, stat)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -150,14 +161,39 @@ func (s Stats) bindOpts(b squirrel.SelectBuilder, opts *StatOpts) squirrel.Selec
 		b = b.Where("is_latest = ?", opts.IsLatest.Value())
 	}
 
+	if !opts.CreatedAfter.IsZero() {
+		b = b.Where("created_at > ?", opts.CreatedAfter.UTC())
+	}
+
+	if !opts.CreatedBefore.IsZero() {
+		b = b.Where("created_at <= ?", opts.CreatedBefore.UTC())
+	}
+
+	return b
+}
+
+// sortKeys returns the keys of orders, which end with id. The keys of
+// (created_at, id) are returned if no order is given.
+func (s Stats) sortKeys(opts *StatOpts) []sortKey {
+	keys := make([]sortKey, 0)
 	for _, order := range opts.Orders {
 		switch order {
 		case StatOrderByRecordDesc:
-			b = b.OrderBy("recorded_at DESC")
+			keys = append(keys, sortKey{expr: "COALESCE(" + s.table() + ".recorded_at, " + nullCreatedAt + ")", desc: true})
+		case StatOrderByReleaseDesc:
+			keys = append(keys, sortKey{expr: "COALESCE(" + s.table() + ".recorded_at, " + s.table() + ".created_at)", desc: true})
 		}
 	}
+	if len(keys) == 0 {
+		return createdKeys(s.table(), false)
+	}
+	return append(keys, sortKey{expr: s.table() + ".id", desc: true})
+}
 
-	return b
+// RecordedCursor returns the encoded cursor of stat in the list sorted
+// by StatOrderByRecordDesc.
+func RecordedCursor(stat *model.Stat) string {
+	return Cursor{Keys: []interface{}{stat.RecordedAt.Time().UTC()}, ID: stat.ID}.Encode()
 }
 
 func (s Stats) columns() []string {
@@ -174,6 +210,9 @@ func (s Stats) columns() []string {
 		s.table() + ".weight",
 		s.table() + ".is_latest",
 		s.table() + ".has_children",
+		s.table() + ".body",
+		s.table() + ".assets",
+		s.table() + ".created_at",
 	}
 }
 
@@ -191,6 +230,9 @@ func (s Stats) scanColumns(stat *model.Stat) []interface{} {
 		&stat.Weight,
 		&stat.IsLatest,
 		&stat.HasChildren,
+		&stat.Body,
+		&stat.Assets,
+		&stat.CreatedAt,
 	}
 }
 
@@ -206,6 +248,7 @@ func (s Stats) scan(row database.RowScanner) (*model.Stat, error) {
 func (s *Stats) Create(ctx context.Context, stat *model.Stat) error {
 	stat2 := *stat
 	stat2.ID = newID()
+	stat2.CreatedAt = timestamp()
 
 	qb := s.RunnableBuilder(ctx).
 		Insert(s.table()).
@@ -221,7 +264,10 @@ func (s *Stats) Create(ctx context.Context, stat *model.Stat) error {
 			"checksum",
 			"weight",
 			"is_latest",
-			"has_children").
+			"has_children",
+			"body",
+			"assets",
+			"created_at").
 		Values(
 			stat2.ID,
 			stat2.PkgID,
@@ -234,7 +280,10 @@ func (s *Stats) Create(ctx context.Context, stat *model.Stat) error {
 			stat2.Checksum,
 			stat2.Weight,
 			stat2.IsLatest,
-			stat2.HasChildren)
+			stat2.HasChildren,
+			stat2.Body,
+			stat2.Assets,
+			stat2.CreatedAt)
 	_, err := qb.Exec()
 	if err != nil {
 		return err
@@ -259,6 +308,8 @@ func (s *Stats) Update(ctx context.Context, stat *model.Stat) error {
 		Set("weight", stat2.Weight).
 		Set("is_latest", stat2.IsLatest).
 		Set("has_children", stat2.HasChildren).
+		Set("body", stat2.Body).
+		Set("assets", stat2.Assets).
 		Where("id = ?", stat2.ID)
 	_, err := qb.Exec()
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
 	"github.com/pinmonl/pinmonl/database/dbtest"
 	"github.com/pinmonl/pinmonl/model"
 	"github.com/pinmonl/pinmonl/model/field"
@@ -151,7 +152,10 @@ func expectStatsCreate(mock sqlmock.Sqlmock, stat *model.Stat) {
 			stat.Checksum,
 			stat.Weight,
 			stat.IsLatest,
-			stat.HasChildren).
+			stat.HasChildren,
+			stat.Body,
+			stat.Assets,
+			sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
 
@@ -182,6 +186,8 @@ func expectStatsUpdate(mock sqlmock.Sqlmock, stat *model.Stat) {
 			stat.Weight,
 			stat.IsLatest,
 			stat.HasChildren,
+			stat.Body,
+			stat.Assets,
 			stat.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
@@ -204,3 +210,30 @@ func testStatsDelete(ctx context.Context, stats *Stats, mock sqlmock.Sqlmock) fu
 		assert.Equal(t, int64(1), n)
 	}
 }
+
+func TestStatsReleaseOrder(t *testing.T) {
+	stats := NewStats(nil)
+	opts := &StatOpts{Orders: []StatOrder{StatOrderByReleaseDesc}}
+	b, err := addCursor(squirrel.Select("*").From("stats"), stats.sortKeys(opts), nil)
+	assert.Nil(t, err)
+	query, _, err := b.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM stats ORDER BY COALESCE(stats.recorded_at, stats.created_at) DESC, stats.id DESC", query)
+}
+
+func TestStatsRecordedCursor(t *testing.T) {
+	stats := NewStats(nil)
+	recordedAt := field.Now()
+	cursor, err := DecodeCursor(RecordedCursor(&model.Stat{ID: "stat-id-1", RecordedAt: recordedAt}))
+	assert.Nil(t, err)
+
+	opts := &StatOpts{Orders: []StatOrder{StatOrderByRecordDesc}}
+	b, err := addCursor(squirrel.Select("*").From("stats"), stats.sortKeys(opts), cursor)
+	assert.Nil(t, err)
+	query, args, err := b.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM stats WHERE ((COALESCE(stats.recorded_at, '0001-01-01 00:00:00+00:00') < ?) OR "+
+		"(COALESCE(stats.recorded_at, '0001-01-01 00:00:00+00:00') = ? AND stats.id < ?)) "+
+		"ORDER BY COALESCE(stats.recorded_at, '0001-01-01 00:00:00+00:00') DESC, stats.id DESC", query)
+	assert.Equal(t, []interface{}{recordedAt.Time().UTC(), recordedAt.Time().UTC(), "stat-id-1"}, args)
+}
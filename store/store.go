@@ -5,6 +5,7 @@ import (
 
 	"github.com/Masterminds/squirrel"
 	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model/field"
 )
 
 type Store struct {
@@ -41,9 +42,25 @@ func (s *Store) RunnableBuilder(ctx context.Context) database.Builder {
 	return b
 }
 
+// updateTimestamps sets created_at and updated_at of the row, which
+// keeps the original time of the restored rows.
+func (s *Store) updateTimestamps(ctx context.Context, table, id string, createdAt, updatedAt field.Time) error {
+	qb := s.RunnableBuilder(ctx).
+		Update(table).
+		Set("created_at", createdAt).
+		Set("updated_at", updatedAt).
+		Where("id = ?", id)
+	_, err := qb.Exec()
+	return err
+}
+
 type ListOpts struct {
 	Limit  int64
 	Offset int64
+
+	// Cursor selects the page after or before the row of cursor, in
+	// place of Offset.
+	Cursor *Cursor
 }
 
 func (o ListOpts) LimitUint64() uint64 {
@@ -54,7 +71,7 @@ func (o ListOpts) LimitUint64() uint64 {
 }
 
 func (o ListOpts) OffsetUint64() uint64 {
-	if o.Offset < 0 {
+	if o.Offset < 0 || o.Cursor != nil {
 		return 0
 	}
 	return uint64(o.Offset)
@@ -81,20 +98,24 @@ func addPagination(b squirrel.SelectBuilder, pt Paginator) squirrel.SelectBuilde
 type Stores struct {
 	Store *Store
 
-	Images    *Images
-	Jobs      *Jobs
-	Monls     *Monls
-	Monpkgs   *Monpkgs
-	Pinls     *Pinls
-	Pinpkgs   *Pinpkgs
-	Pkgs      *Pkgs
-	Sharepins *Sharepins
-	Shares    *Shares
-	Sharetags *Sharetags
-	Stats     *Stats
-	Taggables *Taggables
-	Tags      *Tags
-	Users     *Users
+	Digests          *Digests
+	Images           *Images
+	Jobs             *Jobs
+	Monls            *Monls
+	Monpkgs          *Monpkgs
+	NotifyDeliveries *NotifyDeliveries
+	NotifyRules      *NotifyRules
+	Pinls            *Pinls
+	Pinpkgs          *Pinpkgs
+	Pkgs             *Pkgs
+	Presets          *Presets
+	Sharepins        *Sharepins
+	Shares           *Shares
+	Sharetags        *Sharetags
+	Stats            *Stats
+	Taggables        *Taggables
+	Tags             *Tags
+	Users            *Users
 }
 
 func NewStores(db *database.DB) *Stores {
@@ -102,19 +123,23 @@ func NewStores(db *database.DB) *Stores {
 	return &Stores{
 		Store: s,
 
-		Images:    NewImages(s),
-		Jobs:      NewJobs(s),
-		Monls:     NewMonls(s),
-		Monpkgs:   NewMonpkgs(s),
-		Pinls:     NewPinls(s),
-		Pinpkgs:   NewPinpkgs(s),
-		Pkgs:      NewPkgs(s),
-		Sharepins: NewSharepins(s),
-		Shares:    NewShares(s),
-		Sharetags: NewSharetags(s),
-		Stats:     NewStats(s),
-		Taggables: NewTaggables(s),
-		Tags:      NewTags(s),
-		Users:     NewUsers(s),
+		Digests:          NewDigests(s),
+		Images:           NewImages(s),
+		Jobs:             NewJobs(s),
+		Monls:            NewMonls(s),
+		Monpkgs:          NewMonpkgs(s),
+		NotifyDeliveries: NewNotifyDeliveries(s),
+		NotifyRules:      NewNotifyRules(s),
+		Pinls:            NewPinls(s),
+		Pinpkgs:          NewPinpkgs(s),
+		Pkgs:             NewPkgs(s),
+		Presets:          NewPresets(s),
+		Sharepins:        NewSharepins(s),
+		Shares:           NewShares(s),
+		Sharetags:        NewSharetags(s),
+		Stats:            NewStats(s),
+		Taggables:        NewTaggables(s),
+		Tags:             NewTags(s),
+		Users:            NewUsers(s),
 	}
 }
@@ -0,0 +1,467 @@
+package storeutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// ArchiveVersion is the version of the archive format produced by
+// ExportArchive. Version 2 adds presets.
+const ArchiveVersion = 2
+
+// archiveChunkSize limits the number of ids in one query, sqlite3
+// accepts at most 999 variables.
+const archiveChunkSize = 500
+
+// Archive holds all the data of a user.
+type Archive struct {
+	Version    int               `json:"version"`
+	ExportedAt field.Time        `json:"exportedAt"`
+	Tags       []*model.Tag      `json:"tags"`
+	Pinls      []*model.Pinl     `json:"pinls"`
+	Taggables  []*model.Taggable `json:"taggables"`
+	Presets    []*model.Preset   `json:"presets"`
+	Shares     []*model.Share    `json:"shares"`
+	Sharetags  []*model.Sharetag `json:"sharetags"`
+	Images     []*ArchiveImage   `json:"images"`
+}
+
+// Validate checks whether the archive can be imported.
+func (ar *Archive) Validate() error {
+	if ar.Version < 1 || ar.Version > ArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d", ar.Version)
+	}
+	return nil
+}
+
+// ArchiveImage includes the content of image which is omitted
+// in model.Image.
+type ArchiveImage struct {
+	*model.Image
+	Content []byte `json:"content"`
+}
+
+// ArchiveStores groups the stores used by archive export and import.
+type ArchiveStores struct {
+	Images    *store.Images
+	Pinls     *store.Pinls
+	Presets   *store.Presets
+	Shares    *store.Shares
+	Sharetags *store.Sharetags
+	Taggables *store.Taggables
+	Tags      *store.Tags
+}
+
+// ArchiveCount counts the created and skipped rows of a kind.
+type ArchiveCount struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// ArchiveImportResult reports the outcome of ImportArchive.
+type ArchiveImportResult struct {
+	Tags      ArchiveCount `json:"tags"`
+	Pinls     ArchiveCount `json:"pinls"`
+	Taggables ArchiveCount `json:"taggables"`
+	Presets   ArchiveCount `json:"presets"`
+	Shares    ArchiveCount `json:"shares"`
+	Sharetags ArchiveCount `json:"sharetags"`
+	Images    ArchiveCount `json:"images"`
+
+	// CreatedPinlIDs contains the ids of new pinls.
+	CreatedPinlIDs []string `json:"-"`
+}
+
+// ExportArchive collects the pinls, tags, presets, shares and images of
+// the user.
+func ExportArchive(ctx context.Context, stores ArchiveStores, userID string) (*Archive, error) {
+	ar := &Archive{
+		Version:    ArchiveVersion,
+		ExportedAt: field.Now(),
+		Taggables:  make([]*model.Taggable, 0),
+		Sharetags:  make([]*model.Sharetag, 0),
+		Images:     make([]*ArchiveImage, 0),
+	}
+
+	tList, err := stores.Tags.List(ctx, &store.TagOpts{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	ar.Tags = tList
+
+	pList, err := stores.Pinls.List(ctx, &store.PinlOpts{
+		UserID: userID,
+		Orders: []store.PinlOrder{store.PinlOrderByLatest},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Oldest first, so that import creates them in the original order.
+	for i, j := 0, len(pList)-1; i < j; i, j = i+1, j-1 {
+		pList[i], pList[j] = pList[j], pList[i]
+	}
+	ar.Pinls = pList
+
+	for _, ids := range chunkKeys(tList.Keys()) {
+		tgList, err := stores.Taggables.List(ctx, &store.TaggableOpts{
+			TagIDs:     ids,
+			TargetName: model.Pinl{}.MorphName(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ar.Taggables = append(ar.Taggables, tgList...)
+	}
+
+	psList, err := stores.Presets.List(ctx, &store.PresetOpts{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	ar.Presets = psList
+
+	sList, err := stores.Shares.List(ctx, &store.ShareOpts{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	ar.Shares = sList
+
+	for _, ids := range chunkKeys(sList.Keys()) {
+		stList, err := stores.Sharetags.List(ctx, &store.SharetagOpts{ShareIDs: ids})
+		if err != nil {
+			return nil, err
+		}
+		ar.Sharetags = append(ar.Sharetags, stList...)
+	}
+
+	imageIDs := make([]string, 0)
+	for _, p := range pList {
+		if p.ImageID != "" {
+			imageIDs = append(imageIDs, p.ImageID)
+		}
+	}
+	for _, s := range sList {
+		if s.ImageID != "" {
+			imageIDs = append(imageIDs, s.ImageID)
+		}
+	}
+	for _, ids := range chunkKeys(imageIDs) {
+		iList, err := stores.Images.List(ctx, &store.ImageOpts{IDs: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range iList {
+			ar.Images = append(ar.Images, &ArchiveImage{
+				Image:   image,
+				Content: image.Content,
+			})
+		}
+	}
+
+	return ar, nil
+}
+
+// ImportArchive restores the archive into the user. IDs are remapped to
+// new ones. Pinls are matched by url, presets by name and shares by slug,
+// the matched rows are left untouched so that the import can be run
+// repeatedly.
+func ImportArchive(ctx context.Context, stores ArchiveStores, userID string, ar *Archive) (*ArchiveImportResult, error) {
+	if err := ar.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		res     = &ArchiveImportResult{CreatedPinlIDs: make([]string, 0)}
+		tagIDs  = make(map[string]string)
+		psIDs   = make(map[string]string)
+		tagMap  = make(map[string]*model.Tag)
+		pinlMap = make(map[string]*model.Pinl)
+		shrMap  = make(map[string]*model.Share)
+		newPinl = make(map[string]bool)
+		newShr  = make(map[string]bool)
+	)
+
+	// Tags, parents first.
+	tList := append(make([]*model.Tag, 0), ar.Tags...)
+	sort.SliceStable(tList, func(i, j int) bool {
+		return tList[i].Level < tList[j].Level
+	})
+	for _, t := range tList {
+		found, err := stores.Tags.FindName(ctx, userID, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			tagIDs[t.ID] = found.ID
+			res.Tags.Skipped++
+			continue
+		}
+
+		tag, err := SaveTag(ctx, stores.Tags, userID, &model.Tag{
+			Name:        t.Name,
+			Color:       t.Color,
+			BgColor:     t.BgColor,
+			HasChildren: t.HasChildren,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tagIDs[t.ID] = tag.ID
+		tagMap[t.ID] = tag
+		res.Tags.Created++
+	}
+
+	// Pinls.
+	for _, p := range ar.Pinls {
+		found, err := stores.Pinls.List(ctx, &store.PinlOpts{
+			UserID: userID,
+			URL:    p.URL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(found) > 0 {
+			pinlMap[p.ID] = found[0]
+			res.Pinls.Skipped++
+			continue
+		}
+
+		pinl := &model.Pinl{
+			UserID:      userID,
+			URL:         p.URL,
+			Title:       p.Title,
+			Description: p.Description,
+			Status:      p.Status,
+		}
+		if err := stores.Pinls.Create(ctx, pinl); err != nil {
+			return nil, err
+		}
+		pinlMap[p.ID] = pinl
+		newPinl[p.ID] = true
+		res.Pinls.Created++
+		res.CreatedPinlIDs = append(res.CreatedPinlIDs, pinl.ID)
+	}
+
+	// Taggables of the pinls.
+	for _, tg := range ar.Taggables {
+		pinl, ok := pinlMap[tg.TargetID]
+		tagID, ok2 := tagIDs[tg.TagID]
+		if !ok || !ok2 || tg.TargetName != pinl.MorphName() {
+			res.Taggables.Skipped++
+			continue
+		}
+		if !newPinl[tg.TargetID] {
+			res.Taggables.Skipped++
+			continue
+		}
+
+		err := stores.Taggables.Create(ctx, &model.Taggable{
+			TagID:      tagID,
+			TargetID:   pinl.MorphKey(),
+			TargetName: pinl.MorphName(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.Taggables.Created++
+	}
+
+	// Presets.
+	psList, err := stores.Presets.List(ctx, &store.PresetOpts{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	psNames := make(map[string]string)
+	for _, ps := range psList {
+		psNames[ps.Name] = ps.ID
+	}
+	for _, ps := range ar.Presets {
+		if id, ok := psNames[ps.Name]; ok {
+			psIDs[ps.ID] = id
+			res.Presets.Skipped++
+			continue
+		}
+
+		preset := &model.Preset{
+			UserID:   userID,
+			Name:     ps.Name,
+			Query:    ps.Query,
+			TagNames: ps.TagNames,
+			Sort:     ps.Sort,
+		}
+		if err := stores.Presets.Create(ctx, preset); err != nil {
+			return nil, err
+		}
+		psIDs[ps.ID] = preset.ID
+		psNames[preset.Name] = preset.ID
+		res.Presets.Created++
+	}
+
+	// Shares.
+	for _, s := range ar.Shares {
+		found, err := stores.Shares.FindSlug(ctx, userID, s.Slug)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			shrMap[s.ID] = found
+			res.Shares.Skipped++
+			continue
+		}
+
+		share := &model.Share{
+			UserID:      userID,
+			Slug:        s.Slug,
+			Name:        s.Name,
+			Description: s.Description,
+			PresetID:    psIDs[s.PresetID],
+			Status:      s.Status,
+		}
+		if err := stores.Shares.Create(ctx, share); err != nil {
+			return nil, err
+		}
+		shrMap[s.ID] = share
+		newShr[s.ID] = true
+		res.Shares.Created++
+	}
+
+	// Sharetags of the new shares.
+	stList := append(make([]*model.Sharetag, 0), ar.Sharetags...)
+	sort.SliceStable(stList, func(i, j int) bool {
+		return stList[i].Level < stList[j].Level
+	})
+	for _, st := range stList {
+		share, ok := shrMap[st.ShareID]
+		tagID, ok2 := tagIDs[st.TagID]
+		if !ok || !ok2 || !newShr[st.ShareID] {
+			res.Sharetags.Skipped++
+			continue
+		}
+
+		sharetag := &model.Sharetag{
+			ShareID:     share.ID,
+			TagID:       tagID,
+			Kind:        st.Kind,
+			ParentID:    tagIDs[st.ParentID],
+			Level:       st.Level,
+			Status:      st.Status,
+			HasChildren: st.HasChildren,
+		}
+		if err := stores.Sharetags.Create(ctx, sharetag); err != nil {
+			return nil, err
+		}
+		res.Sharetags.Created++
+	}
+
+	// Images of the new pinls and shares.
+	for _, ai := range ar.Images {
+		if ai.Image == nil {
+			res.Images.Skipped++
+			continue
+		}
+
+		var target model.Morphable
+		switch ai.TargetName {
+		case model.Pinl{}.MorphName():
+			if newPinl[ai.TargetID] {
+				target = pinlMap[ai.TargetID]
+			}
+		case model.Share{}.MorphName():
+			if newShr[ai.TargetID] {
+				target = shrMap[ai.TargetID]
+			}
+		}
+		if target == nil {
+			res.Images.Skipped++
+			continue
+		}
+
+		image := &model.Image{
+			TargetID:    target.MorphKey(),
+			TargetName:  target.MorphName(),
+			Content:     ai.Content,
+			Description: ai.Description,
+			Size:        len(ai.Content),
+			ContentType: ai.ContentType,
+		}
+		if err := stores.Images.Create(ctx, image); err != nil {
+			return nil, err
+		}
+		res.Images.Created++
+
+		switch t := target.(type) {
+		case *model.Pinl:
+			t.ImageID = image.ID
+			if err := stores.Pinls.Update(ctx, t); err != nil {
+				return nil, err
+			}
+		case *model.Share:
+			t.ImageID = image.ID
+			if err := stores.Shares.Update(ctx, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Timestamps of the new rows are restored at last, as the updates
+	// above stamp updated_at.
+	for _, t := range ar.Tags {
+		tag, ok := tagMap[t.ID]
+		if !ok || !restoreTimestamps(&tag.CreatedAt, &tag.UpdatedAt, t.CreatedAt, t.UpdatedAt) {
+			continue
+		}
+		if err := stores.Tags.UpdateTimestamps(ctx, tag); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range ar.Pinls {
+		pinl := pinlMap[p.ID]
+		if !newPinl[p.ID] || !restoreTimestamps(&pinl.CreatedAt, &pinl.UpdatedAt, p.CreatedAt, p.UpdatedAt) {
+			continue
+		}
+		if err := stores.Pinls.UpdateTimestamps(ctx, pinl); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range ar.Shares {
+		share := shrMap[s.ID]
+		if !newShr[s.ID] || !restoreTimestamps(&share.CreatedAt, &share.UpdatedAt, s.CreatedAt, s.UpdatedAt) {
+			continue
+		}
+		if err := stores.Shares.UpdateTimestamps(ctx, share); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// restoreTimestamps copies the archived timestamps, it returns false if
+// the archive has none.
+func restoreTimestamps(createdAt, updatedAt *field.Time, archivedCreatedAt, archivedUpdatedAt field.Time) bool {
+	if archivedCreatedAt.Time().IsZero() {
+		return false
+	}
+	*createdAt = archivedCreatedAt
+	*updatedAt = archivedUpdatedAt
+	if archivedUpdatedAt.Time().IsZero() {
+		*updatedAt = archivedCreatedAt
+	}
+	return true
+}
+
+func chunkKeys(keys []string) [][]string {
+	chunks := make([][]string, 0)
+	for start := 0; start < len(keys); start += archiveChunkSize {
+		end := start + archiveChunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunks = append(chunks, keys[start:end])
+	}
+	return chunks
+}
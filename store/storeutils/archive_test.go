@@ -0,0 +1,125 @@
+package storeutils
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/pkger"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pinmonl/pinmonl/database"
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStores(t *testing.T) (*store.Stores, func()) {
+	dir, err := ioutil.TempDir("", "storeutils")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	dsn := filepath.Join(dir, "test.db")
+	db, err := database.NewDB("sqlite3", dsn)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	if err := db.CheckFTS5(); err != nil {
+		t.Skip(err)
+	}
+	if !assert.Nil(t, db.Migrate.Up()) {
+		t.FailNow()
+	}
+	return store.NewStores(db), func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	var (
+		ctx       = context.TODO()
+		arStores  = ArchiveStores{stores.Images, stores.Pinls, stores.Presets, stores.Shares, stores.Sharetags, stores.Taggables, stores.Tags}
+		createdAt = field.Time(time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC))
+		updatedAt = field.Time(time.Date(2019, 6, 7, 8, 9, 10, 0, time.UTC))
+	)
+
+	// Data of the first user.
+	tag, err := SaveTag(ctx, stores.Tags, "user-id-1", &model.Tag{Name: "lang/go"})
+	assert.Nil(t, err)
+	pinl := &model.Pinl{UserID: "user-id-1", URL: "https://golang.org", Title: "Go"}
+	assert.Nil(t, stores.Pinls.Create(ctx, pinl))
+	pinl.CreatedAt, pinl.UpdatedAt = createdAt, updatedAt
+	assert.Nil(t, stores.Pinls.UpdateTimestamps(ctx, pinl))
+	assert.Nil(t, stores.Taggables.Create(ctx, &model.Taggable{TagID: tag.ID, TargetID: pinl.ID, TargetName: pinl.MorphName()}))
+	preset := &model.Preset{UserID: "user-id-1", Name: "Go", TagNames: []string{"lang/go"}}
+	assert.Nil(t, stores.Presets.Create(ctx, preset))
+	share := &model.Share{UserID: "user-id-1", Slug: "go", Name: "Go", PresetID: preset.ID}
+	assert.Nil(t, stores.Shares.Create(ctx, share))
+	share.CreatedAt, share.UpdatedAt = createdAt, updatedAt
+	assert.Nil(t, stores.Shares.UpdateTimestamps(ctx, share))
+
+	// Export and import through JSON.
+	ar, err := ExportArchive(ctx, arStores, "user-id-1")
+	assert.Nil(t, err)
+	b, err := json.Marshal(ar)
+	assert.Nil(t, err)
+	ar = &Archive{}
+	assert.Nil(t, json.Unmarshal(b, ar))
+
+	res, err := ImportArchive(ctx, arStores, "user-id-2", ar)
+	assert.Nil(t, err)
+	assert.Equal(t, ArchiveCount{Created: 2}, res.Tags)
+	assert.Equal(t, ArchiveCount{Created: 1}, res.Pinls)
+	assert.Equal(t, ArchiveCount{Created: 1}, res.Taggables)
+	assert.Equal(t, ArchiveCount{Created: 1}, res.Presets)
+	assert.Equal(t, ArchiveCount{Created: 1}, res.Shares)
+
+	pList, err := stores.Pinls.List(ctx, &store.PinlOpts{UserID: "user-id-2"})
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(pList)) {
+		got := pList[0]
+		assert.NotEqual(t, pinl.ID, got.ID)
+		assert.Equal(t, pinl.URL, got.URL)
+		assert.True(t, createdAt.Time().Equal(got.CreatedAt.Time()))
+		assert.True(t, updatedAt.Time().Equal(got.UpdatedAt.Time()))
+
+		tgList, err := stores.Taggables.List(ctx, &store.TaggableOpts{TargetIDs: []string{got.ID}})
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(tgList)) {
+			got2, err := stores.Tags.Find(ctx, tgList[0].TagID)
+			assert.Nil(t, err)
+			assert.Equal(t, "lang/go", got2.Name)
+			assert.Equal(t, "user-id-2", got2.UserID)
+		}
+	}
+
+	psList, err := stores.Presets.List(ctx, &store.PresetOpts{UserID: "user-id-2"})
+	assert.Nil(t, err)
+	got, err := stores.Shares.FindSlug(ctx, "user-id-2", "go")
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(psList)) {
+		assert.NotEqual(t, preset.ID, psList[0].ID)
+		assert.Equal(t, []string{"lang/go"}, []string(psList[0].TagNames))
+		assert.Equal(t, psList[0].ID, got.PresetID)
+	}
+	assert.True(t, createdAt.Time().Equal(got.CreatedAt.Time()))
+	assert.True(t, updatedAt.Time().Equal(got.UpdatedAt.Time()))
+
+	// Import again skips the existing rows.
+	res, err = ImportArchive(ctx, arStores, "user-id-2", ar)
+	assert.Nil(t, err)
+	assert.Equal(t, ArchiveCount{Skipped: 2}, res.Tags)
+	assert.Equal(t, ArchiveCount{Skipped: 1}, res.Pinls)
+	assert.Equal(t, ArchiveCount{Skipped: 1}, res.Taggables)
+	assert.Equal(t, ArchiveCount{Skipped: 1}, res.Presets)
+	assert.Equal(t, ArchiveCount{Skipped: 1}, res.Shares)
+}
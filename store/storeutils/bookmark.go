@@ -0,0 +1,180 @@
+package storeutils
+
+import (
+	"context"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/pkgs/bookmark"
+	"github.com/pinmonl/pinmonl/pkgs/pinlutils"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// BookmarkImportBatchSize is the number of bookmarks checked against
+// the existing pinls per query.
+var BookmarkImportBatchSize = 100
+
+// ImportStatus is the outcome of importing a bookmark.
+type ImportStatus string
+
+// ImportStatus constants.
+const (
+	ImportCreated   ImportStatus = "created"
+	ImportDuplicate ImportStatus = "duplicate"
+	ImportInvalid   ImportStatus = "invalid"
+)
+
+// ImportResult reports the outcome of a single row.
+type ImportResult struct {
+	Row     int          `json:"row"`
+	URL     string       `json:"url"`
+	Title   string       `json:"title"`
+	Status  ImportStatus `json:"status"`
+	PinlID  string       `json:"pinlId,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// ImportResultList is a list of ImportResult.
+type ImportResultList []*ImportResult
+
+// Count returns the number of results with the status.
+func (il ImportResultList) Count(status ImportStatus) int {
+	n := 0
+	for _, ir := range il {
+		if ir.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// PinlIDs returns the ids of the created pinls.
+func (il ImportResultList) PinlIDs() []string {
+	ids := make([]string, 0)
+	for _, ir := range il {
+		if ir.Status == ImportCreated {
+			ids = append(ids, ir.PinlID)
+		}
+	}
+	return ids
+}
+
+// ImportBookmarks creates pinls from bookmarks in batches. Folders of
+// the bookmark are saved as hierarchical tag, and the time of adding
+// is kept as the creation time. Bookmarks with an url which is already
+// pinned by the user are skipped.
+func ImportBookmarks(ctx context.Context, pinls *store.Pinls, tags *store.Tags, taggables *store.Taggables, userID string, bookmarks []*bookmark.Bookmark) (ImportResultList, error) {
+	var (
+		results = make(ImportResultList, len(bookmarks))
+		seen    = make(map[string]bool)
+		tagMap  = make(map[string]*model.Tag)
+	)
+
+	for start := 0; start < len(bookmarks); start += BookmarkImportBatchSize {
+		end := start + BookmarkImportBatchSize
+		if end > len(bookmarks) {
+			end = len(bookmarks)
+		}
+		batch := bookmarks[start:end]
+
+		urls := make([]string, 0, len(batch))
+		for _, bm := range batch {
+			if pinlutils.IsValidURL(bm.URL) {
+				urls = append(urls, bm.URL)
+			}
+		}
+		if len(urls) > 0 {
+			found, err := pinls.List(ctx, &store.PinlOpts{
+				UserID: userID,
+				URLs:   urls,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range found {
+				seen[p.URL] = true
+			}
+		}
+
+		for i, bm := range batch {
+			ir := &ImportResult{
+				Row:   start + i + 1,
+				URL:   bm.URL,
+				Title: bm.Title,
+			}
+			results[start+i] = ir
+
+			if !pinlutils.IsValidURL(bm.URL) {
+				ir.Status, ir.Message = ImportInvalid, "invalid url format"
+				continue
+			}
+			if seen[bm.URL] {
+				ir.Status = ImportDuplicate
+				continue
+			}
+
+			pinl := &model.Pinl{
+				UserID:      userID,
+				URL:         bm.URL,
+				Title:       bm.Title,
+				Description: bm.Description,
+				Status:      model.Active,
+			}
+			if pinl.Title == "" {
+				pinl.Title = bm.URL
+			}
+			if err := pinls.Create(ctx, pinl); err != nil {
+				return nil, err
+			}
+			// Keeps the time of bookmark added to browser.
+			if !bm.AddedAt.IsZero() {
+				pinl.CreatedAt = field.Time(bm.AddedAt)
+				pinl.UpdatedAt = pinl.CreatedAt
+				if err := pinls.UpdateTimestamps(ctx, pinl); err != nil {
+					return nil, err
+				}
+			}
+			if err := associateImportedTags(ctx, tags, taggables, tagMap, pinl, userID, bm.TagNames()); err != nil {
+				return nil, err
+			}
+
+			seen[bm.URL] = true
+			ir.Status, ir.PinlID = ImportCreated, pinl.ID
+		}
+	}
+
+	return results, nil
+}
+
+// associateImportedTags associates tags to the newly created pinl. tagMap
+// caches the tags across rows to avoid repeated lookups.
+func associateImportedTags(ctx context.Context, tags *store.Tags, taggables *store.Taggables, tagMap map[string]*model.Tag, pinl *model.Pinl, userID string, tagNames []string) error {
+	for _, tagName := range tagNames {
+		tag, ok := tagMap[tagName]
+		if !ok {
+			var err error
+			tag, err = tags.FindName(ctx, userID, tagName)
+			if err != nil {
+				return err
+			}
+			if tag == nil {
+				if t2, err := SaveTag(ctx, tags, userID, &model.Tag{Name: tagName}); err == nil {
+					tag = t2
+				} else {
+					return err
+				}
+			}
+			tagMap[tagName] = tag
+		}
+
+		tg := &model.Taggable{
+			TagID:      tag.ID,
+			TargetID:   pinl.MorphKey(),
+			TargetName: pinl.MorphName(),
+		}
+		if err := taggables.Create(ctx, tg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package storeutils
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/pkgs/bookmark"
+	"github.com/pinmonl/pinmonl/store"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBookmarkHTML = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+  <DT><H3>Dev</H3>
+  <DL><p>
+    <DT><H3>Go</H3>
+    <DL><p>
+      <DT><A HREF="https://golang.org" ADD_DATE="1590000001" TAGS="lang">Go</A>
+      <DT><A HREF="https://pkg.go.dev" ADD_DATE="1590000002">Packages</A>
+    </DL><p>
+    <DT><A HREF="https://github.com" ADD_DATE="1590000003">GitHub</A>
+  </DL><p>
+  <DT><A HREF="https://golang.org">Go again</A>
+  <DT><A HREF="https://example.com/existing">Existing</A>
+  <DT><A HREF="not a url">Invalid</A>
+  <DT><A HREF="https://example.com/unsorted">Unsorted</A>
+</DL><p>`
+
+func TestImportBookmarks(t *testing.T) {
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	defer func(n int) { BookmarkImportBatchSize = n }(BookmarkImportBatchSize)
+	BookmarkImportBatchSize = 2
+
+	ctx := context.TODO()
+	existing := &model.Pinl{UserID: "user-id-1", URL: "https://example.com/existing", Title: "Existing"}
+	assert.Nil(t, stores.Pinls.Create(ctx, existing))
+
+	bookmarks, err := bookmark.ParseNetscape(strings.NewReader(testBookmarkHTML))
+	if !assert.Nil(t, err) {
+		return
+	}
+	results, err := ImportBookmarks(ctx, stores.Pinls, stores.Tags, stores.Taggables, "user-id-1", bookmarks)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	statuses := make([]ImportStatus, len(results))
+	for i, ir := range results {
+		assert.Equal(t, i+1, ir.Row)
+		statuses[i] = ir.Status
+	}
+	assert.Equal(t, []ImportStatus{
+		ImportCreated,
+		ImportCreated,
+		ImportCreated,
+		ImportDuplicate,
+		ImportDuplicate,
+		ImportInvalid,
+		ImportCreated,
+	}, statuses)
+	assert.Len(t, results.PinlIDs(), 4)
+
+	// Folders are mapped to hierarchical tags.
+	tagNames := func(pinlID string) []string {
+		tgList, err := stores.Taggables.ListWithTag(ctx, &store.TaggableOpts{
+			TargetIDs:  []string{pinlID},
+			TargetName: model.Pinl{}.MorphName(),
+		})
+		assert.Nil(t, err)
+		names := make([]string, 0)
+		for _, tg := range tgList {
+			names = append(names, tg.Tag.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal(t, []string{"Dev/Go", "lang"}, tagNames(results[0].PinlID))
+	assert.Equal(t, []string{"Dev/Go"}, tagNames(results[1].PinlID))
+	assert.Equal(t, []string{"Dev"}, tagNames(results[2].PinlID))
+	assert.Empty(t, tagNames(results[6].PinlID))
+
+	dev, err := stores.Tags.FindName(ctx, "user-id-1", "Dev")
+	assert.Nil(t, err)
+	golang, err := stores.Tags.FindName(ctx, "user-id-1", "Dev/Go")
+	assert.Nil(t, err)
+	if assert.NotNil(t, dev) && assert.NotNil(t, golang) {
+		assert.Equal(t, dev.ID, golang.ParentID)
+		assert.True(t, dev.HasChildren)
+	}
+	tList, err := stores.Tags.List(ctx, &store.TagOpts{UserID: "user-id-1"})
+	assert.Nil(t, err)
+	assert.Len(t, tList, 3)
+
+	// The time of adding is kept.
+	pinl, err := stores.Pinls.Find(ctx, results[0].PinlID)
+	assert.Nil(t, err)
+	if assert.NotNil(t, pinl) {
+		assert.True(t, time.Unix(1590000001, 0).Equal(pinl.CreatedAt.Time()))
+		assert.True(t, pinl.CreatedAt.Time().Equal(pinl.UpdatedAt.Time()))
+	}
+	pinl, err = stores.Pinls.Find(ctx, results[6].PinlID)
+	assert.Nil(t, err)
+	if assert.NotNil(t, pinl) {
+		assert.True(t, pinl.CreatedAt.Time().After(time.Unix(1590000003, 0)))
+	}
+
+	// Importing again finds all duplicates.
+	results, err = ImportBookmarks(ctx, stores.Pinls, stores.Tags, stores.Taggables, "user-id-1", bookmarks)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, results.Count(ImportDuplicate))
+	assert.Equal(t, 1, results.Count(ImportInvalid))
+}
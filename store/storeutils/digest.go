@@ -0,0 +1,153 @@
+package storeutils
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// DigestStores groups the stores used by release digest.
+type DigestStores struct {
+	Monpkgs   *store.Monpkgs
+	Pinls     *store.Pinls
+	Stats     *store.Stats
+	Taggables *store.Taggables
+}
+
+// DigestRelease is a new release of the pkg monitored by the pinl.
+type DigestRelease struct {
+	Pinl *model.Pinl
+	Pkg  *model.Pkg
+	Stat *model.Stat
+}
+
+// DigestGroup collects the releases of the pinls under a tag. Tag
+// is empty for the pinls without tag.
+type DigestGroup struct {
+	Tag      string
+	Releases []*DigestRelease
+}
+
+// ListDigestReleases finds the releases created within (since, until]
+// of the pkgs linked to the user's pinls, grouped by tag name. A pinl
+// with several tags appears in each of the groups.
+func ListDigestReleases(ctx context.Context, stores DigestStores, userID string, since, until time.Time) ([]*DigestGroup, error) {
+	pList, err := stores.Pinls.List(ctx, &store.PinlOpts{
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pinlsByMonl := make(map[string]model.PinlList)
+	for _, pinl := range pList {
+		if pinl.MonlID == "" {
+			continue
+		}
+		pinlsByMonl[pinl.MonlID] = append(pinlsByMonl[pinl.MonlID], pinl)
+	}
+	monlIDs := make([]string, 0, len(pinlsByMonl))
+	for monlID := range pinlsByMonl {
+		monlIDs = append(monlIDs, monlID)
+	}
+
+	mpList := model.MonpkgList{}
+	for _, chunk := range chunkKeys(monlIDs) {
+		found, err := stores.Monpkgs.ListWithPkg(ctx, &store.MonpkgOpts{
+			MonlIDs: chunk,
+		})
+		if err != nil {
+			return nil, err
+		}
+		mpList = append(mpList, found...)
+	}
+
+	pkgByID := make(map[string]*model.Pkg)
+	monlsByPkg := make(map[string][]string)
+	for _, mp := range mpList {
+		pkgByID[mp.PkgID] = mp.Pkg
+		monlsByPkg[mp.PkgID] = append(monlsByPkg[mp.PkgID], mp.MonlID)
+	}
+	pkgIDs := make([]string, 0, len(pkgByID))
+	for pkgID := range pkgByID {
+		pkgIDs = append(pkgIDs, pkgID)
+	}
+
+	sList := model.StatList{}
+	for _, chunk := range chunkKeys(pkgIDs) {
+		found, err := stores.Stats.List(ctx, &store.StatOpts{
+			PkgIDs:        chunk,
+			ParentIDs:     []string{""},
+			Kinds:         model.ReleaseStatKinds,
+			CreatedAfter:  since,
+			CreatedBefore: until,
+			Orders:        []store.StatOrder{store.StatOrderByRecordDesc},
+		})
+		if err != nil {
+			return nil, err
+		}
+		sList = append(sList, found...)
+	}
+	if len(sList) == 0 {
+		return nil, nil
+	}
+
+	tMap := make(map[string]model.TagList)
+	for _, chunk := range chunkKeys(pList.Keys()) {
+		targets := make(model.MorphableList, len(chunk))
+		for i := range chunk {
+			targets[i] = model.Pinl{ID: chunk[i]}
+		}
+		found, err := GetTags(ctx, stores.Taggables, targets)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range found {
+			tMap[k] = v
+		}
+	}
+
+	groups := make(map[string]*DigestGroup)
+	addRelease := func(tag string, release *DigestRelease) {
+		group, has := groups[tag]
+		if !has {
+			group = &DigestGroup{Tag: tag}
+			groups[tag] = group
+		}
+		group.Releases = append(group.Releases, release)
+	}
+	for _, stat := range sList {
+		for _, monlID := range monlsByPkg[stat.PkgID] {
+			for _, pinl := range pinlsByMonl[monlID] {
+				release := &DigestRelease{
+					Pinl: pinl,
+					Pkg:  pkgByID[stat.PkgID],
+					Stat: stat,
+				}
+				tags := tMap[pinl.ID]
+				if len(tags) == 0 {
+					addRelease("", release)
+				}
+				for _, tag := range tags {
+					addRelease(tag.Name, release)
+				}
+			}
+		}
+	}
+
+	out := make([]*DigestGroup, 0, len(groups))
+	for _, group := range groups {
+		out = append(out, group)
+	}
+	// Untagged group goes last.
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].Tag == "") != (out[j].Tag == "") {
+			return out[j].Tag == ""
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out, nil
+}
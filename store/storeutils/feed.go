@@ -0,0 +1,114 @@
+package storeutils
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// FeedStores groups the stores used by release feed.
+type FeedStores struct {
+	Monpkgs *store.Monpkgs
+	Pinls   *store.Pinls
+	Stats   *store.Stats
+}
+
+// FeedRelease is a release of pkg along with the pinls
+// monitoring the pkg.
+type FeedRelease struct {
+	Stat  *model.Stat
+	Pkg   *model.Pkg
+	Pinls model.PinlList
+}
+
+// ReleasedAt falls back to the time first seen if the release is not
+// recorded with time, which is kept across syncs.
+func (r *FeedRelease) ReleasedAt() time.Time {
+	return releasedAt(r.Stat)
+}
+
+func releasedAt(stat *model.Stat) time.Time {
+	if t := stat.RecordedAt.Time(); !t.IsZero() {
+		return t
+	}
+	return stat.CreatedAt.Time()
+}
+
+// ListFeedReleases finds the latest releases of the pkgs linked to the
+// pinls filtered by pinlOpts, in the order of release time.
+func ListFeedReleases(ctx context.Context, stores FeedStores, pinlOpts *store.PinlOpts, limit int64) ([]*FeedRelease, error) {
+	pList, err := stores.Pinls.List(ctx, pinlOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	pinlsByMonl := make(map[string]model.PinlList)
+	for _, pinl := range pList {
+		if pinl.MonlID == "" {
+			continue
+		}
+		pinlsByMonl[pinl.MonlID] = append(pinlsByMonl[pinl.MonlID], pinl)
+	}
+	monlIDs := make([]string, 0, len(pinlsByMonl))
+	for monlID := range pinlsByMonl {
+		monlIDs = append(monlIDs, monlID)
+	}
+
+	mpList := model.MonpkgList{}
+	for _, chunk := range chunkKeys(monlIDs) {
+		found, err := stores.Monpkgs.ListWithPkg(ctx, &store.MonpkgOpts{
+			MonlIDs: chunk,
+		})
+		if err != nil {
+			return nil, err
+		}
+		mpList = append(mpList, found...)
+	}
+
+	pkgByID := make(map[string]*model.Pkg)
+	pinlsByPkg := make(map[string]model.PinlList)
+	for _, mp := range mpList {
+		pkgByID[mp.PkgID] = mp.Pkg
+		pinlsByPkg[mp.PkgID] = append(pinlsByPkg[mp.PkgID], pinlsByMonl[mp.MonlID]...)
+	}
+	pkgIDs := make([]string, 0, len(pkgByID))
+	for pkgID := range pkgByID {
+		pkgIDs = append(pkgIDs, pkgID)
+	}
+
+	// Each chunk is limited individually, the merged list
+	// is truncated after sorting.
+	sList := model.StatList{}
+	for _, chunk := range chunkKeys(pkgIDs) {
+		found, err := stores.Stats.List(ctx, &store.StatOpts{
+			PkgIDs:    chunk,
+			ParentIDs: []string{""},
+			Kinds:     model.ReleaseStatKinds,
+			Orders:    []store.StatOrder{store.StatOrderByReleaseDesc},
+			ListOpts:  store.ListOpts{Limit: limit},
+		})
+		if err != nil {
+			return nil, err
+		}
+		sList = append(sList, found...)
+	}
+	sort.SliceStable(sList, func(i, j int) bool {
+		return releasedAt(sList[i]).After(releasedAt(sList[j]))
+	})
+	if limit > 0 && int64(len(sList)) > limit {
+		sList = sList[:limit]
+	}
+
+	out := make([]*FeedRelease, len(sList))
+	for i, stat := range sList {
+		out[i] = &FeedRelease{
+			Stat:  stat,
+			Pkg:   pkgByID[stat.PkgID],
+			Pinls: pinlsByPkg[stat.PkgID],
+		}
+	}
+	return out, nil
+}
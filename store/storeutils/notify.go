@@ -0,0 +1,186 @@
+package storeutils
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pinmonl/pinmonl/model"
+	"github.com/pinmonl/pinmonl/model/field"
+	"github.com/pinmonl/pinmonl/store"
+)
+
+// NotifyEventRelease is the event name of new release webhook.
+const NotifyEventRelease = "release"
+
+// NotifyStores groups the stores used by release notification.
+type NotifyStores struct {
+	Monpkgs     *store.Monpkgs
+	NotifyRules *store.NotifyRules
+	Pinls       *store.Pinls
+	Pkgs        *store.Pkgs
+	Taggables   *store.Taggables
+}
+
+// ReleaseNotice is a release matched by a notify rule, along with
+// the pinls of the rule owner which monitor the package.
+type ReleaseNotice struct {
+	Rule  *model.NotifyRule
+	Pkg   *model.Pkg
+	Stat  *model.Stat
+	Pinls model.PinlList
+}
+
+// ReleasePayload is the webhook body of release event.
+type ReleasePayload struct {
+	Event   string             `json:"event"`
+	Rule    ReleasePayloadRule `json:"rule"`
+	Pkg     *model.Pkg         `json:"pkg"`
+	Release *model.Stat        `json:"release"`
+	Pinls   []*model.Pinl      `json:"pinls"`
+	SentAt  field.Time         `json:"sentAt"`
+}
+
+// ReleasePayloadRule identifies the rule in the payload.
+type ReleasePayloadRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Payload encodes the notice into webhook body.
+func (n *ReleaseNotice) Payload() ([]byte, error) {
+	return json.Marshal(ReleasePayload{
+		Event: NotifyEventRelease,
+		Rule: ReleasePayloadRule{
+			ID:   n.Rule.ID,
+			Name: n.Rule.Name,
+		},
+		Pkg:     n.Pkg,
+		Release: n.Stat,
+		Pinls:   n.Pinls,
+		SentAt:  field.Now(),
+	})
+}
+
+// MatchReleaseRules finds the enabled rules matching the release stats
+// and the pinls monitoring the package of each release.
+func MatchReleaseRules(ctx context.Context, stores NotifyStores, stats model.StatList) ([]*ReleaseNotice, error) {
+	out := make([]*ReleaseNotice, 0)
+	for _, stat := range stats {
+		if stat.ParentID != "" || !model.IsReleaseStatKind(stat.Kind) {
+			continue
+		}
+
+		pkg, err := stores.Pkgs.Find(ctx, stat.PkgID)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			continue
+		}
+
+		pinls, tagMap, err := listPkgPinls(ctx, stores, pkg.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(pinls) == 0 {
+			continue
+		}
+
+		pinlsByUser := make(map[string]model.PinlList)
+		userIDs := make([]string, 0)
+		for _, pinl := range pinls {
+			if _, has := pinlsByUser[pinl.UserID]; !has {
+				userIDs = append(userIDs, pinl.UserID)
+			}
+			pinlsByUser[pinl.UserID] = append(pinlsByUser[pinl.UserID], pinl)
+		}
+
+		rules, err := stores.NotifyRules.List(ctx, &store.NotifyRuleOpts{
+			UserIDs: userIDs,
+			Enabled: field.NewNullBool(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range rules {
+			if !rule.MatchStat(stat) {
+				continue
+			}
+			if len(rule.PkgIDs) > 0 && !rule.PkgIDs.Contains(pkg.ID) {
+				continue
+			}
+
+			matched := model.PinlList{}
+			for _, pinl := range pinlsByUser[rule.UserID] {
+				if matchRulePinl(rule, pinl, tagMap[pinl.ID]) {
+					matched = append(matched, pinl)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+
+			out = append(out, &ReleaseNotice{
+				Rule:  rule,
+				Pkg:   pkg,
+				Stat:  stat,
+				Pinls: matched,
+			})
+		}
+	}
+	return out, nil
+}
+
+func listPkgPinls(ctx context.Context, stores NotifyStores, pkgID string) (model.PinlList, map[string]model.TagList, error) {
+	monpkgs, err := stores.Monpkgs.List(ctx, &store.MonpkgOpts{
+		PkgIDs: []string{pkgID},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(monpkgs) == 0 {
+		return nil, nil, nil
+	}
+
+	monlIDs := make([]string, len(monpkgs))
+	for i := range monpkgs {
+		monlIDs[i] = monpkgs[i].MonlID
+	}
+	pinls, err := stores.Pinls.List(ctx, &store.PinlOpts{
+		MonlIDs: monlIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pinls) == 0 {
+		return nil, nil, nil
+	}
+
+	tagMap, err := GetTags(ctx, stores.Taggables, pinls.Morphables())
+	if err != nil {
+		return nil, nil, err
+	}
+	pinls.SetTagNames(tagMap)
+	return pinls, tagMap, nil
+}
+
+// matchRulePinl checks the pinl and tag filters of rule. Tag filter
+// includes the children tags.
+func matchRulePinl(rule *model.NotifyRule, pinl *model.Pinl, tags model.TagList) bool {
+	if len(rule.PinlIDs) > 0 && !rule.PinlIDs.Contains(pinl.ID) {
+		return false
+	}
+	if len(rule.TagNames) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, name := range rule.TagNames {
+			if tag.Name == name || strings.HasPrefix(tag.Name, name+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
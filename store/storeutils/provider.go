@@ -9,39 +9,42 @@ import (
 	"github.com/pinmonl/pinmonl/store"
 )
 
+// SaveProviderReport saves the pkg and stats of the report. Releases
+// which are not found in previous crawl are returned separately, the
+// first crawl of a pkg does not produce any new release.
 func SaveProviderReport(
 	ctx context.Context,
 	pkgs *store.Pkgs,
 	stats *store.Stats,
 	report provider.Report,
 	force bool,
-) (*model.Pkg, model.StatList, error) {
+) (*model.Pkg, model.StatList, model.StatList, error) {
 	pkg, err := findOrCreatePkgFromReport(ctx, pkgs, report)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if !force && !pkg.FetchedAt.Time().IsZero() {
-		return pkg, nil, nil
+		return pkg, nil, nil, nil
 	}
 
 	sList := model.StatList{}
 	if rsList, err := saveReportStats(ctx, stats, pkg.ID, report); err == nil {
 		sList = append(sList, rsList...)
 	} else {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	if rtList, err := saveReportTags(ctx, stats, pkg.ID, report); err == nil {
-		sList = append(sList, rtList...)
-	} else {
-		return nil, nil, err
+	rtList, releases, err := saveReportTags(ctx, stats, pkg.ID, report)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	sList = append(sList, rtList...)
 
 	pkg.FetchedAt = field.Now()
 	if err := pkgs.Update(ctx, pkg); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return pkg, sList, nil
+	return pkg, sList, releases, nil
 }
 
 type statKey struct {
@@ -92,13 +95,13 @@ type reportTagKey struct {
 	value string
 }
 
-func saveReportTags(ctx context.Context, stats *store.Stats, pkgID string, report provider.Report) (model.StatList, error) {
+func saveReportTags(ctx context.Context, stats *store.Stats, pkgID string, report provider.Report) (model.StatList, model.StatList, error) {
 	// Get all tags.
 	tags := model.StatList{}
 	for report.Next() {
 		tag, err := report.Tag()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		tags = append(tags, tag)
 	}
@@ -110,7 +113,7 @@ func saveReportTags(ctx context.Context, stats *store.Stats, pkgID string, repor
 		ParentIDs: []string{""},
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	prevTagSet := make(map[reportTagKey]*model.Stat)
@@ -130,6 +133,7 @@ func saveReportTags(ctx context.Context, stats *store.Stats, pkgID string, repor
 
 	// Save tags.
 	out := make([]*model.Stat, len(tags))
+	releases := model.StatList{}
 	for i := range tags {
 		deref := *tags[i]
 		tag := &deref
@@ -138,24 +142,65 @@ func saveReportTags(ctx context.Context, stats *store.Stats, pkgID string, repor
 		key := reportTagKey{kind: tag.Kind, value: tag.Value}
 		prevTag, has := prevTagSet[key]
 		if has {
-			tag = prevTag
-			// Skip if the previous tag is not marked as latest.
-			if !tag.IsLatest {
-				continue
+			_, isLatest := latestKeys[key]
+			if err := updateReportTag(ctx, stats, pkgID, prevTag, tag, isLatest); err != nil {
+				return nil, nil, err
 			}
-			// Skip if the previous tag is same as the latest one.
-			if _, match := latestKeys[key]; match {
-				continue
-			}
-			// Update to unset latest flag.
-			tag.IsLatest = false
+			continue
 		}
-		_, err := saveStat(ctx, stats, pkgID, tag)
+		saved, err := saveStat(ctx, stats, pkgID, tag)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if len(prevTags) > 0 {
+			releases = append(releases, saved)
 		}
 	}
-	return out, nil
+	return out, releases, nil
+}
+
+// updateReportTag updates the previous tag with the release of tag,
+// and unsets its latest flag if it is no longer the latest one. The
+// time first seen is kept.
+func updateReportTag(ctx context.Context, stats *store.Stats, pkgID string, prevTag, tag *model.Stat, isLatest bool) error {
+	changed := false
+	if prevTag.IsLatest && !isLatest {
+		prevTag.IsLatest = false
+		changed = true
+	}
+
+	// Release is kept if it is not reported, e.g. failed to list.
+	if hasRelease(tag) {
+		assets, _ := tag.Assets.Value()
+		prevAssets, _ := prevTag.Assets.Value()
+		if tag.Name != prevTag.Name || tag.Body != prevTag.Body || assets != prevAssets {
+			prevTag.Name = tag.Name
+			prevTag.Body = tag.Body
+			prevTag.Assets = tag.Assets
+			changed = true
+		}
+	}
+
+	// Substats are saved once, e.g. the tag is released afterward.
+	if !prevTag.HasChildren && tag.Substats != nil && len(*tag.Substats) > 0 {
+		for _, substat := range *tag.Substats {
+			substat.ParentID = prevTag.ID
+			if _, err := saveStat(ctx, stats, pkgID, substat); err != nil {
+				return err
+			}
+		}
+		prevTag.HasChildren = true
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return stats.Update(ctx, prevTag)
+}
+
+func hasRelease(tag *model.Stat) bool {
+	return tag.Name != "" || tag.Body != "" || len(tag.Assets) > 0
 }
 
 func findOrCreatePkgFromReport(ctx context.Context, pkgs *store.Pkgs, report provider.Report) (*model.Pkg, error) {
@@ -122,7 +122,19 @@ func replaceTagsPrefix(ctx context.Context, tags *store.Tags, fromPrefix, toPref
 }
 
 func ReAssociateTags(ctx context.Context, tags *store.Tags, taggables *store.Taggables, target model.Morphable, userID string, tagNames []string) (model.TagList, error) {
-	_, err := taggables.DeleteByTarget(ctx, target)
+	// Keep the manual positions of the tags which are not removed.
+	tgList, err := taggables.List(ctx, &store.TaggableOpts{
+		Targets: model.MorphableList{target},
+	})
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[string]int)
+	for _, tg := range tgList {
+		positions[tg.TagID] = tg.Position
+	}
+
+	_, err = taggables.DeleteByTarget(ctx, target)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +160,7 @@ func ReAssociateTags(ctx context.Context, tags *store.Tags, taggables *store.Tag
 			TagID:      t.ID,
 			TargetID:   target.MorphKey(),
 			TargetName: target.MorphName(),
+			Position:   positions[t.ID],
 		}
 		err = taggables.Create(ctx, tg)
 		if err != nil {
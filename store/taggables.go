@@ -40,6 +40,10 @@ func (t *Taggables) List(ctx context.Context, opts *TaggableOpts) (model.Taggabl
 	qb := t.RunnableBuilder(ctx).
 		Select(t.columns()...).From(t.table())
 	qb = t.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(t.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -54,6 +58,7 @@ func (t *Taggables) List(ctx context.Context, opts *TaggableOpts) (model.Taggabl
 		}
 		list = append(list, taggable)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -139,6 +144,7 @@ func (t Taggables) columns() []string {
 		t.table() + ".tag_id",
 		t.table() + ".target_id",
 		t.table() + ".target_name",
+		t.table() + ".position",
 	}
 }
 
@@ -148,6 +154,7 @@ func (t Taggables) scanColumns(taggable *model.Taggable) []interface{} {
 		&taggable.TagID,
 		&taggable.TargetID,
 		&taggable.TargetName,
+		&taggable.Position,
 	}
 }
 
@@ -171,6 +178,10 @@ func (t *Taggables) ListWithTag(ctx context.Context, opts *TaggableOpts) (model.
 		From(t.table()).
 		LeftJoin(fmt.Sprintf("%s ON %[1]s.id = %s.tag_id", Tags{}.table(), t.table()))
 	qb = t.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(t.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -191,6 +202,7 @@ func (t *Taggables) ListWithTag(ctx context.Context, opts *TaggableOpts) (model.
 		mtg.Tag = &mt
 		list = append(list, &mtg)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -212,6 +224,10 @@ func (t *Taggables) listWithTarget(
 		LeftJoin(fmt.Sprintf("%s ON %[1]s.id = %s.target_id", tableName, t.table())).
 		Where(t.table()+".target_name = ?", targetName)
 	qb = t.bindOpts(qb, opts)
+	qb, err := addCursor(qb, idKeys(t.table()), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -226,6 +242,7 @@ func (t *Taggables) listWithTarget(
 		}
 		list = append(list, taggable)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -257,12 +274,14 @@ func (t *Taggables) Create(ctx context.Context, taggable *model.Taggable) error
 			"id",
 			"tag_id",
 			"target_id",
-			"target_name").
+			"target_name",
+			"position").
 		Values(
 			taggable2.ID,
 			taggable2.TagID,
 			taggable2.TargetID,
-			taggable2.TargetName)
+			taggable2.TargetName,
+			taggable2.Position)
 	_, err := qb.Exec()
 	if err != nil {
 		return err
@@ -279,6 +298,7 @@ func (t *Taggables) Update(ctx context.Context, taggable *model.Taggable) error
 		Set("tag_id", taggable2.TagID).
 		Set("target_id", taggable2.TargetID).
 		Set("target_name", taggable2.TargetName).
+		Set("position", taggable2.Position).
 		Where("id = ?", taggable2.ID)
 	_, err := qb.Exec()
 	if err != nil {
@@ -288,6 +308,23 @@ func (t *Taggables) Update(ctx context.Context, taggable *model.Taggable) error
 	return nil
 }
 
+// SetPositions sets the manual positions of targets under tag by the
+// order of targetIDs. Targets not tagged are skipped.
+func (t *Taggables) SetPositions(ctx context.Context, tag *model.Tag, targetName string, targetIDs []string) error {
+	for i, targetID := range targetIDs {
+		qb := t.RunnableBuilder(ctx).
+			Update(t.table()).
+			Set("position", i+1).
+			Where("tag_id = ?", tag.ID).
+			Where("target_name = ?", targetName).
+			Where("target_id = ?", targetID)
+		if _, err := qb.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *Taggables) Delete(ctx context.Context, id string) (int64, error) {
 	qb := t.RunnableBuilder(ctx).
 		Delete(t.table()).
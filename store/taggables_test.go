@@ -28,6 +28,7 @@ func TestTaggables(t *testing.T) {
 	t.Run("create", testTaggablesCreate(ctx, taggables, mock))
 	t.Run("update", testTaggablesUpdate(ctx, taggables, mock))
 	t.Run("delete", testTaggablesDelete(ctx, taggables, mock))
+	t.Run("setPositions", testTaggablesSetPositions(ctx, taggables, mock))
 }
 
 func testTaggablesList(ctx context.Context, taggables *Taggables, mock sqlmock.Sqlmock) func(*testing.T) {
@@ -43,7 +44,7 @@ func testTaggablesList(ctx context.Context, taggables *Taggables, mock sqlmock.S
 		opts = nil
 		mock.ExpectQuery(prefix).
 			WillReturnRows(sqlmock.NewRows(taggables.columns()).
-				AddRow("taggable-id-1", "tag-id-1", "target-id-1", "target"))
+				AddRow("taggable-id-1", "tag-id-1", "target-id-1", "target", 0))
 		list, err = taggables.List(ctx, opts)
 		assert.Nil(t, err)
 		assert.Equal(t, 1, len(list))
@@ -106,7 +107,7 @@ func testTaggablesFind(ctx context.Context, taggables *Taggables, mock sqlmock.S
 		mock.ExpectQuery(query).
 			WithArgs(id).
 			WillReturnRows(sqlmock.NewRows(taggables.columns()).
-				AddRow("taggable-id-1", "tag-id-1", "target-id-1", "target"))
+				AddRow("taggable-id-1", "tag-id-1", "target-id-1", "target", 0))
 		taggable, err = taggables.Find(ctx, id)
 		assert.Nil(t, err)
 		if assert.NotNil(t, taggable) {
@@ -163,7 +164,8 @@ func expectTaggablesCreate(mock sqlmock.Sqlmock, taggable *model.Taggable) {
 			sqlmock.AnyArg(),
 			taggable.TagID,
 			taggable.TargetID,
-			taggable.TargetName).
+			taggable.TargetName,
+			taggable.Position).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
 
@@ -187,6 +189,7 @@ func expectTaggablesUpdate(mock sqlmock.Sqlmock, taggable *model.Taggable) {
 			taggable.TagID,
 			taggable.TargetID,
 			taggable.TargetName,
+			taggable.Position,
 			taggable.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 }
@@ -209,3 +212,22 @@ func testTaggablesDelete(ctx context.Context, taggables *Taggables, mock sqlmock
 		assert.Equal(t, int64(1), n)
 	}
 }
+
+func testTaggablesSetPositions(ctx context.Context, taggables *Taggables, mock sqlmock.Sqlmock) func(*testing.T) {
+	return func(t *testing.T) {
+		var (
+			query = regexp.QuoteMeta("UPDATE taggables SET position = ? WHERE tag_id = ? AND target_name = ? AND target_id = ?")
+			tag   = &model.Tag{ID: "tag-id-1"}
+			err   error
+		)
+
+		mock.ExpectExec(query).
+			WithArgs(1, tag.ID, "pinl", "pinl-id-2").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).
+			WithArgs(2, tag.ID, "pinl", "pinl-id-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = taggables.SetPositions(ctx, tag, "pinl", []string{"pinl-id-2", "pinl-id-1"})
+		assert.Nil(t, err)
+	}
+}
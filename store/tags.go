@@ -42,6 +42,10 @@ func (t *Tags) List(ctx context.Context, opts *TagOpts) (model.TagList, error) {
 	qb := t.RunnableBuilder(ctx).
 		Select(t.columns()...).From(t.table())
 	qb = t.bindOpts(qb, opts)
+	qb, err := addCursor(qb, nil, opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -56,6 +60,7 @@ func (t *Tags) List(ctx context.Context, opts *TagOpts) (model.TagList, error) {
 		}
 		list = append(list, tag)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
@@ -267,6 +272,12 @@ func (t *Tags) Update(ctx context.Context, tag *model.Tag) error {
 	return nil
 }
 
+// UpdateTimestamps sets the created and updated time of the tag,
+// e.g. restored from archive.
+func (t *Tags) UpdateTimestamps(ctx context.Context, tag *model.Tag) error {
+	return t.updateTimestamps(ctx, t.table(), tag.ID, tag.CreatedAt, tag.UpdatedAt)
+}
+
 func (t *Tags) Delete(ctx context.Context, id string) (int64, error) {
 	qb := t.RunnableBuilder(ctx).
 		Delete(t.table()).
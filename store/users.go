@@ -37,6 +37,10 @@ func (u *Users) List(ctx context.Context, opts *UserOpts) (model.UserList, error
 	qb := u.RunnableBuilder(ctx).
 		Select(u.columns()...).From(u.table())
 	qb = u.bindOpts(qb, opts)
+	qb, err := addCursor(qb, createdKeys(u.table(), false), opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 	qb = addPagination(qb, opts)
 	rows, err := qb.Query()
 	if err != nil {
@@ -51,6 +55,7 @@ func (u *Users) List(ctx context.Context, opts *UserOpts) (model.UserList, error
 		}
 		list = append(list, user)
 	}
+	reverseList(list, opts.Cursor)
 	return list, nil
 }
 
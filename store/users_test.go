@@ -77,7 +77,7 @@ func testUsersList(ctx context.Context, users *Users, mock sqlmock.Sqlmock) func
 		opts = &UserOpts{}
 		opts.Limit = 10
 		opts.Offset = 1
-		mock.ExpectQuery(fmt.Sprintf("%s LIMIT %d OFFSET %d", prefix, opts.Limit, opts.Offset)).
+		mock.ExpectQuery(fmt.Sprintf("%s ORDER BY (.+) LIMIT %d OFFSET %d", prefix, opts.Limit, opts.Offset)).
 			WillReturnRows(sqlmock.NewRows(users.columns()))
 		_, err = users.List(ctx, opts)
 		assert.Nil(t, err)